@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"gitnote/internal/attachment"
+	"gitnote/internal/config"
+)
+
+var attachmentsDelete bool
+
+var attachCmd = &cobra.Command{
+	Use:   "attach <note> <file>",
+	Short: "Attach a file or image to a note",
+	Long:  "Copy a file into the note's assets directory under a content-hash or date-based name and append a relative Markdown link to the note. The note can be a path or a title",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runAttach,
+}
+
+var attachmentsCmd = &cobra.Command{
+	Use:   "attachments",
+	Short: "Manage note attachments",
+}
+
+var attachmentsGCCmd = &cobra.Command{
+	Use:   "gc",
+	Short: "Find attachments no note links to",
+	Long:  "List files in assets directories that are not referenced by any note. Use --delete to remove them",
+	Args:  cobra.NoArgs,
+	RunE:  runAttachmentsGC,
+}
+
+func init() {
+	attachmentsGCCmd.Flags().BoolVar(&attachmentsDelete, "delete", false, "Remove the unreferenced attachments")
+	attachmentsCmd.AddCommand(attachmentsGCCmd)
+}
+
+func newAttachmentManager() (*attachment.Manager, error) {
+	cfg, err := config.Load(".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	return attachment.NewManager(".", cfg.Attachments), nil
+}
+
+func runAttach(cmd *cobra.Command, args []string) error {
+	notePath, err := resolveNote(args[0])
+	if err != nil {
+		return err
+	}
+
+	attachmentManager, err := newAttachmentManager()
+	if err != nil {
+		return err
+	}
+
+	assetPath, err := attachmentManager.Attach(notePath, rootPath(args[1]), time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to attach file: %w", err)
+	}
+
+	fmt.Printf("Attached %s to %s\n", assetPath, notePath)
+	return nil
+}
+
+func runAttachmentsGC(cmd *cobra.Command, args []string) error {
+	attachmentManager, err := newAttachmentManager()
+	if err != nil {
+		return err
+	}
+
+	attachmentManager.SetDecrypter(lazyDecrypter())
+
+	unreferenced, err := attachmentManager.Unreferenced()
+	if err != nil {
+		return fmt.Errorf("failed to find unreferenced attachments: %w", err)
+	}
+
+	if len(unreferenced) == 0 {
+		fmt.Println("No unreferenced attachments")
+		return nil
+	}
+
+	for _, path := range unreferenced {
+		fmt.Println(path)
+	}
+
+	if !attachmentsDelete {
+		fmt.Printf("\n%d unreferenced attachment(s). Run with --delete to remove them\n", len(unreferenced))
+		return nil
+	}
+
+	if err := attachmentManager.Remove(unreferenced); err != nil {
+		return err
+	}
+
+	fmt.Printf("\nRemoved %d unreferenced attachment(s)\n", len(unreferenced))
+	return nil
+}
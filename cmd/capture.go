@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"gitnote/internal/config"
+	"gitnote/internal/inbox"
+)
+
+var captureCmd = &cobra.Command{
+	Use:   "capture [text]",
+	Short: "Append a quick note to the inbox",
+	Long:  "Append a timestamped bullet to the inbox note without any prompts. Reads from stdin when no text (or -) is given",
+	RunE:  runCapture,
+}
+
+func runCapture(cmd *cobra.Command, args []string) error {
+	text := strings.Join(args, " ")
+
+	if len(args) == 0 || text == "-" {
+		input, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return fmt.Errorf("failed to read stdin: %w", err)
+		}
+		text = string(input)
+	}
+
+	cfg, err := config.Load(".")
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	inboxManager := inbox.NewManager(".", cfg.Inbox)
+
+	if err := inboxManager.Capture(text, time.Now()); err != nil {
+		return fmt.Errorf("failed to capture note: %w", err)
+	}
+
+	fmt.Printf("Captured to %s\n", inboxManager.Path())
+	return nil
+}
@@ -1,10 +1,22 @@
 package cmd
 
 import (
+	"context"
+	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"gitnote/internal/config"
+	"gitnote/internal/git"
+	"gitnote/internal/note"
+	"gitnote/internal/notebook"
+	"gitnote/internal/task"
 )
 
 func setupTestRepo(t *testing.T) string {
@@ -128,51 +140,1549 @@ func TestCommitCommand(t *testing.T) {
 	}
 }
 
+func TestCommitCommandSelective(t *testing.T) {
+	tempDir := setupTestRepo(t)
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	
+	if err := os.Chdir(tempDir); err != nil {
+		t.Fatalf("Failed to change directory: %v", err)
+	}
+	
+	os.Mkdir("work", 0755)
+	os.WriteFile(filepath.Join("work", "2024-01-01 plan.md"), []byte("# plan\n"), 0644)
+	os.WriteFile("2024-01-02 groceries.md", []byte("# groceries\n"), 0644)
+	
+	if err := runCommit(nil, []string{"work"}); err != nil {
+		t.Fatalf("runCommit with category failed: %v", err)
+	}
+	
+	status, _ := exec.Command("git", "status", "--porcelain", "-z").Output()
+	if string(status) != "?? 2024-01-02 groceries.md\x00" {
+		t.Errorf("Expected only the work category to be committed, got %q", string(status))
+	}
+	
+	commitMessage = "Shopping list"
+	defer func() { commitMessage = "" }()
+	
+	if err := runCommit(nil, []string{"2024-01-02 groceries.md"}); err != nil {
+		t.Fatalf("runCommit with path failed: %v", err)
+	}
+	
+	os.WriteFile("2024-01-02 groceries.md", []byte("# groceries\n\n- eggs\n"), 0644)
+	
+	commitMessage = ""
+	commitAmend = true
+	defer func() { commitAmend = false }()
+	
+	if err := runCommit(nil, []string{}); err != nil {
+		t.Fatalf("runCommit --amend failed: %v", err)
+	}
+	
+	output, _ := exec.Command("git", "log", "--format=%s").Output()
+	if string(output) != "Shopping list\nAdd plan\n" {
+		t.Errorf("Unexpected history after amend: %q", string(output))
+	}
+	
+	status, _ = exec.Command("git", "status", "--porcelain").Output()
+	if len(strings.TrimSpace(string(status))) != 0 {
+		t.Errorf("Expected clean status after amend, got %q", string(status))
+	}
+}
+
 func TestBuildCommitMessage(t *testing.T) {
 	tests := []struct {
-		name         string
-		newFiles     []string
-		modifiedFiles []string
-		expected     string
+		name     string
+		changes  noteChanges
+		expected string
 	}{
 		{
-			name:     "single new file",
-			newFiles: []string{"test.md"},
-			expected: "Add test.md",
+			name:     "single new note",
+			changes:  noteChanges{Added: []string{"test"}},
+			expected: "Add test",
+		},
+		{
+			name:     "multiple new notes",
+			changes:  noteChanges{Added: []string{"test1", "test2"}},
+			expected: "Add 2 notes\n\nAdded:\n- test1\n- test2",
 		},
 		{
-			name:     "multiple new files",
-			newFiles: []string{"test1.md", "test2.md"},
-			expected: "Add 2 new files",
+			name:     "single updated note",
+			changes:  noteChanges{Updated: []string{"existing"}},
+			expected: "Update existing",
 		},
 		{
-			name:          "single modified file",
-			modifiedFiles: []string{"existing.md"},
-			expected:      "Update existing.md",
+			name:     "single deleted note",
+			changes:  noteChanges{Deleted: []string{"old idea"}},
+			expected: "Delete old idea",
 		},
 		{
-			name:          "multiple modified files",
-			modifiedFiles: []string{"file1.md", "file2.md"},
-			expected:      "Update 2 files",
+			name:     "single renamed note",
+			changes:  noteChanges{Renamed: []noteRename{{From: "draft", To: "final"}}},
+			expected: "Rename draft to final",
 		},
 		{
-			name:          "new and modified files",
-			newFiles:      []string{"new.md"},
-			modifiedFiles: []string{"existing.md"},
-			expected:      "Add new.md and Update existing.md",
+			name: "mixed changes",
+			changes: noteChanges{
+				Added:   []string{"new"},
+				Updated: []string{"existing"},
+				Deleted: []string{"gone"},
+			},
+			expected: "Add new and Update existing and Delete gone\n\nAdded:\n- new\nUpdated:\n- existing\nDeleted:\n- gone",
 		},
 		{
-			name:     "no files",
+			name:     "no notes",
 			expected: "Update files",
 		},
 	}
 	
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := buildCommitMessage(tt.newFiles, tt.modifiedFiles)
+			result := buildCommitMessage(tt.changes)
 			if result != tt.expected {
 				t.Errorf("Expected %q, got %q", tt.expected, result)
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+
+func TestCommitCommandRenamesAndDeletions(t *testing.T) {
+	tempDir := setupTestRepo(t)
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	
+	if err := os.Chdir(tempDir); err != nil {
+		t.Fatalf("Failed to change directory: %v", err)
+	}
+	
+	os.Mkdir("work", 0755)
+	os.WriteFile("2024-01-01 draft plan.md", []byte("# draft plan\n\nA fairly long body so git detects the rename.\n"), 0644)
+	os.WriteFile(filepath.Join("work", "2024-01-02 old idea.md"), []byte("# old idea\n"), 0644)
+	os.WriteFile("scratch.txt", []byte("not a note"), 0644)
+	
+	if err := runCommit(nil, []string{}); err != nil {
+		t.Fatalf("runCommit failed: %v", err)
+	}
+	
+	os.Rename("2024-01-01 draft plan.md", "2024-01-01 final plan.md")
+	os.Remove(filepath.Join("work", "2024-01-02 old idea.md"))
+	
+	if err := runCommit(nil, []string{}); err != nil {
+		t.Fatalf("runCommit failed: %v", err)
+	}
+	
+	output, err := exec.Command("git", "log", "-1", "--format=%B").Output()
+	if err != nil {
+		t.Fatalf("Failed to read commit message: %v", err)
+	}
+	
+	expected := "Rename draft plan to final plan and Delete old idea\n\nRenamed:\n- draft plan to final plan\nDeleted:\n- old idea"
+	if strings.TrimSpace(string(output)) != expected {
+		t.Errorf("Expected commit message %q, got %q", expected, strings.TrimSpace(string(output)))
+	}
+	
+	status, _ := exec.Command("git", "status", "--porcelain").Output()
+	if strings.TrimSpace(string(status)) != "?? scratch.txt" {
+		t.Errorf("Expected only scratch.txt to remain uncommitted, got %q", string(status))
+	}
+	
+	os.WriteFile(filepath.Join("work", "2024-01-03 doomed.md"), []byte("# doomed\n"), 0644)
+	if err := runCommit(nil, []string{}); err != nil {
+		t.Fatalf("runCommit failed: %v", err)
+	}
+	
+	if err := exec.Command("git", "mv", "2024-01-01 final plan.md", "2024-01-01 shipped plan.md").Run(); err != nil {
+		t.Fatalf("git mv failed: %v", err)
+	}
+	if err := exec.Command("git", "rm", "-q", filepath.Join("work", "2024-01-03 doomed.md")).Run(); err != nil {
+		t.Fatalf("git rm failed: %v", err)
+	}
+	
+	if err := runCommit(nil, []string{}); err != nil {
+		t.Fatalf("runCommit with staged rename and deletion failed: %v", err)
+	}
+	
+	output, _ = exec.Command("git", "log", "-1", "--format=%B").Output()
+	expected = "Rename final plan to shipped plan and Delete doomed\n\nRenamed:\n- final plan to shipped plan\nDeleted:\n- doomed"
+	if strings.TrimSpace(string(output)) != expected {
+		t.Errorf("Expected commit message %q, got %q", expected, strings.TrimSpace(string(output)))
+	}
+	
+	os.WriteFile("2024-01-04 synced.md", []byte("# synced\n\nAnother fairly long body so git detects the rename.\n"), 0644)
+	if err := runCommit(nil, []string{}); err != nil {
+		t.Fatalf("runCommit failed: %v", err)
+	}
+	exec.Command("git", "mv", "2024-01-04 synced.md", "2024-01-04 moved.md").Run()
+	exec.Command("git", "rm", "-q", "2024-01-01 shipped plan.md").Run()
+	
+	cfg, _ := config.Load(".")
+	gitManager, _ := newGitManager()
+	if _, err := commitAllNotes(context.Background(), gitManager, cfg); err != nil {
+		t.Fatalf("sync commit with staged rename and deletion failed: %v", err)
+	}
+	
+	status, _ = exec.Command("git", "status", "--porcelain").Output()
+	if strings.TrimSpace(string(status)) != "?? scratch.txt" {
+		t.Errorf("Expected the staged rename and deletion to be committed, got %q", string(status))
+	}
+}
+
+func TestExportCommand(t *testing.T) {
+	tempDir := setupTestRepo(t)
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	
+	if err := os.Chdir(tempDir); err != nil {
+		t.Fatalf("Failed to change directory: %v", err)
+	}
+	
+	os.Mkdir("work", 0755)
+	os.WriteFile("work/2025-01-02 work note.md", []byte("# work note\nSome content"), 0644)
+	os.WriteFile("2025-01-01 root note.md", []byte("# root note"), 0644)
+	
+	exportFormat = "json"
+	exportCategory = "work"
+	exportOutput = "work.json"
+	defer func() {
+		exportFormat = "md"
+		exportCategory = ""
+		exportOutput = ""
+	}()
+	
+	if err := runExport(nil, []string{}); err != nil {
+		t.Fatalf("runExport failed: %v", err)
+	}
+	
+	content, err := os.ReadFile("work.json")
+	if err != nil {
+		t.Fatalf("Failed to read export: %v", err)
+	}
+	
+	if !strings.Contains(string(content), "Some content") || strings.Contains(string(content), "root note") {
+		t.Errorf("Expected export to contain only work notes, got %s", content)
+	}
+	
+	exportFormat = "pdf"
+	if err := runExport(nil, []string{}); err == nil {
+		t.Error("Expected runExport to fail for unsupported format")
+	}
+	
+	gitIn(t, tempDir, "add", "work")
+	gitIn(t, tempDir, "commit", "-m", "Add work note")
+	os.WriteFile(".gitnote.yaml", []byte("git:\n  backend: go-git\n"), 0644)
+	t.Setenv("PATH", t.TempDir())
+	
+	exportFormat = "json"
+	if err := runExport(nil, []string{}); err != nil {
+		t.Fatalf("runExport with the go-git backend and no git binary failed: %v", err)
+	}
+	content, _ = os.ReadFile("work.json")
+	if !strings.Contains(string(content), `"created"`) || strings.Contains(string(content), `"created": "0001-01-01`) {
+		t.Errorf("Expected dates from history through the go-git backend, got %s", content)
+	}
+}
+
+func TestImportCommand(t *testing.T) {
+	tempDir := setupTestRepo(t)
+	sourceDir := t.TempDir()
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	
+	if err := os.Chdir(tempDir); err != nil {
+		t.Fatalf("Failed to change directory: %v", err)
+	}
+	
+	os.Mkdir(sourceDir+"/Work", 0755)
+	os.WriteFile(sourceDir+"/Work/2024-01-02 plan.md", []byte("# plan"), 0644)
+	
+	importDryRun = true
+	if err := runImport(nil, []string{sourceDir}); err != nil {
+		t.Fatalf("runImport dry run failed: %v", err)
+	}
+	importDryRun = false
+	
+	if _, err := os.Stat("Work/2024-01-02 plan.md"); !os.IsNotExist(err) {
+		t.Fatal("Expected dry run not to import notes")
+	}
+	
+	importYes = true
+	defer func() { importYes = false }()
+	
+	if err := runImport(nil, []string{sourceDir}); err != nil {
+		t.Fatalf("runImport failed: %v", err)
+	}
+	
+	if _, err := os.Stat("Work/2024-01-02 plan.md"); err != nil {
+		t.Errorf("Expected note to be imported: %v", err)
+	}
+}
+
+
+func TestJournalCommand(t *testing.T) {
+	tempDir := setupTestRepo(t)
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	
+	if err := os.Chdir(tempDir); err != nil {
+		t.Fatalf("Failed to change directory: %v", err)
+	}
+	
+	journalAppend = "Quick thought"
+	defer func() { journalAppend = "" }()
+	
+	if err := runJournal(nil, []string{"2026-10-18"}); err != nil {
+		t.Fatalf("runJournal failed: %v", err)
+	}
+	
+	content, err := os.ReadFile("journal/2026/10/2026-10-18.md")
+	if err != nil {
+		t.Fatalf("Failed to read journal note: %v", err)
+	}
+	
+	if !strings.Contains(string(content), "# 2026-10-18") || !strings.HasSuffix(string(content), "Quick thought\n") {
+		t.Errorf("Unexpected journal content: %q", string(content))
+	}
+	
+	if err := runJournal(nil, []string{"18/10/2026"}); err == nil {
+		t.Error("Expected runJournal to fail for an invalid date")
+	}
+	
+	journalAppend = ""
+	journalNoEdit = true
+	defer func() { journalNoEdit = false }()
+	
+	if err := runToday(nil, []string{}); err != nil {
+		t.Fatalf("runToday failed: %v", err)
+	}
+	
+	today := time.Now()
+	todayPath := fmt.Sprintf("journal/%s/%s/%s.md", today.Format("2006"), today.Format("01"), today.Format("2006-01-02"))
+	if _, err := os.Stat(todayPath); err != nil {
+		t.Errorf("Expected today's journal note to be created: %v", err)
+	}
+}
+
+
+func TestEditorCommand(t *testing.T) {
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", "code --wait")
+	
+	editor := editorCommand()
+	if len(editor) != 2 || editor[0] != "code" || editor[1] != "--wait" {
+		t.Errorf("Expected editor from EDITOR, got %v", editor)
+	}
+	
+	t.Setenv("VISUAL", "nano")
+	
+	editor = editorCommand()
+	if len(editor) != 1 || editor[0] != "nano" {
+		t.Errorf("Expected VISUAL to take precedence, got %v", editor)
+	}
+}
+
+
+func TestCaptureCommand(t *testing.T) {
+	tempDir := setupTestRepo(t)
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	
+	if err := os.Chdir(tempDir); err != nil {
+		t.Fatalf("Failed to change directory: %v", err)
+	}
+	
+	if err := runCapture(nil, []string{"Buy", "milk"}); err != nil {
+		t.Fatalf("runCapture failed: %v", err)
+	}
+	
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Failed to create pipe: %v", err)
+	}
+	writer.WriteString("From stdin\n")
+	writer.Close()
+	
+	originalStdin := os.Stdin
+	os.Stdin = reader
+	defer func() { os.Stdin = originalStdin }()
+	
+	if err := runCapture(nil, []string{}); err != nil {
+		t.Fatalf("runCapture from stdin failed: %v", err)
+	}
+	
+	content, err := os.ReadFile("inbox.md")
+	if err != nil {
+		t.Fatalf("Failed to read inbox: %v", err)
+	}
+	
+	if !strings.Contains(string(content), " Buy milk\n") || !strings.Contains(string(content), " From stdin\n") {
+		t.Errorf("Unexpected inbox content: %q", string(content))
+	}
+	
+	if err := runInbox(nil, []string{}); err != nil {
+		t.Fatalf("runInbox failed: %v", err)
+	}
+}
+
+func TestTodoCommand(t *testing.T) {
+	tempDir := setupTestRepo(t)
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	
+	if err := os.Chdir(tempDir); err != nil {
+		t.Fatalf("Failed to change directory: %v", err)
+	}
+	
+	os.WriteFile("2026-10-19 chores.md", []byte("# chores\n\n- [ ] Fix fence @due(2026-10-20)\n"), 0644)
+	
+	if err := runTodo(nil, []string{}); err != nil {
+		t.Fatalf("runTodo failed: %v", err)
+	}
+	
+	todoDue = "someday"
+	err := runTodo(nil, []string{})
+	todoDue = ""
+	if err == nil {
+		t.Error("Expected error for invalid due filter")
+	}
+	
+	tasks, err := task.NewManager(".").FindTasks()
+	if err != nil || len(tasks) != 1 {
+		t.Fatalf("Expected 1 task, got %d (%v)", len(tasks), err)
+	}
+	
+	if err := runTodoDone(nil, []string{tasks[0].ID}); err != nil {
+		t.Fatalf("runTodoDone failed: %v", err)
+	}
+	
+	content, _ := os.ReadFile("2026-10-19 chores.md")
+	if !strings.Contains(string(content), "- [x] Fix fence") {
+		t.Errorf("Expected task to be ticked, got %q", string(content))
+	}
+}
+
+func TestParseDueFilter(t *testing.T) {
+	now := time.Date(2026, 10, 19, 15, 0, 0, 0, time.Local)
+	
+	tests := map[string]string{
+		"today":      "2026-10-19",
+		"overdue":    "2026-10-18",
+		"week":       "2026-10-26",
+		"2026-12-01": "2026-12-01",
+	}
+	
+	for value, expected := range tests {
+		date, err := parseDueFilter(value, now)
+		if err != nil {
+			t.Fatalf("parseDueFilter(%q) failed: %v", value, err)
+		}
+		if date.Format("2006-01-02") != expected {
+			t.Errorf("parseDueFilter(%q) = %s, expected %s", value, date.Format("2006-01-02"), expected)
+		}
+	}
+}
+
+func gitIn(t *testing.T, dir string, args ...string) {
+	command := exec.Command("git", args...)
+	command.Dir = dir
+	if output, err := command.CombinedOutput(); err != nil {
+		t.Fatalf("git %v failed: %v\n%s", args, err, output)
+	}
+}
+
+func setupClonedRepos(t *testing.T) (string, string) {
+	remote := t.TempDir()
+	gitIn(t, remote, "init", "--bare")
+	
+	local := setupTestRepo(t)
+	os.WriteFile(filepath.Join(local, "2024-01-01 first.md"), []byte("# first\n"), 0644)
+	gitIn(t, local, "add", "-A")
+	gitIn(t, local, "commit", "-m", "initial")
+	gitIn(t, local, "remote", "add", "origin", remote)
+	gitIn(t, local, "push", "-u", "origin", "HEAD")
+	
+	other := t.TempDir()
+	gitIn(t, other, "clone", remote, ".")
+	gitIn(t, other, "config", "user.email", "other@example.com")
+	gitIn(t, other, "config", "user.name", "Other")
+	
+	return local, other
+}
+
+func TestSyncCommand(t *testing.T) {
+	tempDir, other := setupClonedRepos(t)
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	
+	if err := os.Chdir(tempDir); err != nil {
+		t.Fatalf("Failed to change directory: %v", err)
+	}
+	
+	os.WriteFile(filepath.Join(other, "2024-01-02 second.md"), []byte("# second\n"), 0644)
+	gitIn(t, other, "add", "-A")
+	gitIn(t, other, "commit", "-m", "second")
+	gitIn(t, other, "push")
+	
+	os.WriteFile("2024-01-03 third.md", []byte("# third\n"), 0644)
+	
+	if err := runSync(nil, []string{}); err != nil {
+		t.Fatalf("runSync failed: %v", err)
+	}
+	
+	readme, err := os.ReadFile("readme.md")
+	if err != nil || !strings.Contains(string(readme), "second") || !strings.Contains(string(readme), "third") {
+		t.Errorf("Expected regenerated readme to list pulled and local notes, got %q", string(readme))
+	}
+	
+	output, _ := exec.Command("git", "status", "--porcelain", "--branch").Output()
+	if strings.Contains(string(output), "ahead") || strings.Contains(string(output), "behind") || strings.Count(string(output), "\n") != 1 {
+		t.Errorf("Expected clean status in sync with remote, got %q", string(output))
+	}
+	
+	syncStrategy = "squash"
+	defer func() { syncStrategy = "" }()
+	if err := runSync(nil, []string{}); err == nil {
+		t.Error("Expected error for invalid strategy")
+	}
+}
+
+func TestPullResolvesReadmeConflicts(t *testing.T) {
+	for _, strategy := range []string{"merge", "rebase"} {
+		t.Run(strategy, func(t *testing.T) {
+			tempDir, other := setupClonedRepos(t)
+			originalDir, _ := os.Getwd()
+			defer os.Chdir(originalDir)
+			
+			if err := os.Chdir(tempDir); err != nil {
+				t.Fatalf("Failed to change directory: %v", err)
+			}
+			
+			os.WriteFile(filepath.Join(other, "2024-01-02 second.md"), []byte("# second\n"), 0644)
+			os.WriteFile(filepath.Join(other, "readme.md"), []byte("index from other\n"), 0644)
+			gitIn(t, other, "add", "-A")
+			gitIn(t, other, "commit", "-m", "second")
+			gitIn(t, other, "push")
+			
+			os.WriteFile("2024-01-03 third.md", []byte("# third\n"), 0644)
+			os.WriteFile("readme.md", []byte("index from local\n"), 0644)
+			gitIn(t, tempDir, "add", "-A")
+			gitIn(t, tempDir, "commit", "-m", "third")
+			os.WriteFile(".gitnote.yaml", []byte("sync:\n  strategy: "+strategy+"\n"), 0644)
+			
+			if err := runPull(nil, []string{}); err != nil {
+				t.Fatalf("runPull failed: %v", err)
+			}
+			
+			readme, _ := os.ReadFile("readme.md")
+			if strings.Contains(string(readme), "<<<<<<<") || !strings.Contains(string(readme), "second") || !strings.Contains(string(readme), "third") {
+				t.Errorf("Expected regenerated readme, got %q", string(readme))
+			}
+			
+			status, _ := exec.Command("git", "status", "--porcelain").Output()
+			if strings.TrimSpace(string(status)) != "?? .gitnote.yaml" {
+				t.Errorf("Expected merge to be completed, got status %q", string(status))
+			}
+		})
+	}
+}
+
+func setupConflict(t *testing.T, files ...string) string {
+	tempDir := setupTestRepo(t)
+	
+	for _, file := range files {
+		os.WriteFile(filepath.Join(tempDir, file), []byte("base\n"), 0644)
+	}
+	gitIn(t, tempDir, "add", "-A")
+	gitIn(t, tempDir, "commit", "-m", "base")
+	gitIn(t, tempDir, "checkout", "-b", "theirs")
+	for _, file := range files {
+		os.WriteFile(filepath.Join(tempDir, file), []byte("theirs\n"), 0644)
+	}
+	gitIn(t, tempDir, "commit", "-am", "theirs")
+	gitIn(t, tempDir, "checkout", "-")
+	for _, file := range files {
+		os.WriteFile(filepath.Join(tempDir, file), []byte("mine\n"), 0644)
+	}
+	gitIn(t, tempDir, "commit", "-am", "mine")
+	
+	command := exec.Command("git", "merge", "theirs")
+	command.Dir = tempDir
+	if err := command.Run(); err == nil {
+		t.Fatal("Expected merge to conflict")
+	}
+	
+	return tempDir
+}
+
+func TestResolveConflict(t *testing.T) {
+	tempDir := setupConflict(t, "a.md", "b.md", "c.md")
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	
+	if err := os.Chdir(tempDir); err != nil {
+		t.Fatalf("Failed to change directory: %v", err)
+	}
+	
+	gitManager := git.NewManager(".")
+	
+	for path, action := range map[string]string{"a.md": conflictKeepLocal, "b.md": conflictKeepRemote, "c.md": conflictKeepBoth} {
+		if err := resolveConflict(context.Background(), gitManager, path, action, git.StateMerge, ""); err != nil {
+			t.Fatalf("resolveConflict(%s) failed: %v", path, err)
+		}
+	}
+	
+	expected := map[string]string{"a.md": "mine\n", "b.md": "theirs\n", "c.md": "mine\n", "c (conflict).md": "theirs\n"}
+	for path, content := range expected {
+		actual, _ := os.ReadFile(path)
+		if string(actual) != content {
+			t.Errorf("Expected %s to contain %q, got %q", path, content, string(actual))
+		}
+	}
+	
+	if conflicted, _ := conflictedPaths(context.Background(), gitManager); len(conflicted) != 0 {
+		t.Errorf("Expected no remaining conflicts, got %v", conflicted)
+	}
+	
+	if err := gitManager.Continue(context.Background()); err != nil {
+		t.Fatalf("Continue failed: %v", err)
+	}
+	
+	if !hasConflictMarkers("a\n<<<<<<< HEAD\nb\n=======\nc\n>>>>>>> theirs\n") || hasConflictMarkers("plain\n") {
+		t.Error("Unexpected conflict marker detection")
+	}
+}
+
+func TestUndoCommand(t *testing.T) {
+	tempDir, other := setupClonedRepos(t)
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	
+	if err := os.Chdir(tempDir); err != nil {
+		t.Fatalf("Failed to change directory: %v", err)
+	}
+	
+	if err := runUndo(nil, []string{}); err != nil {
+		t.Fatalf("runUndo without pull failed: %v", err)
+	}
+	
+	os.WriteFile(filepath.Join(other, "2024-01-02 second.md"), []byte("# second\n"), 0644)
+	gitIn(t, other, "add", "-A")
+	gitIn(t, other, "commit", "-m", "second")
+	gitIn(t, other, "push")
+	
+	os.WriteFile("2024-01-01 first.md", []byte("# first\n\nlocal edit\n"), 0644)
+	
+	if err := runPull(nil, []string{}); err != nil {
+		t.Fatalf("runPull failed: %v", err)
+	}
+	
+	if _, err := os.Stat("2024-01-02 second.md"); err != nil {
+		t.Fatalf("Expected pulled note to exist: %v", err)
+	}
+	
+	if err := runUndo(nil, []string{}); err != nil {
+		t.Fatalf("runUndo failed: %v", err)
+	}
+	
+	if _, err := os.Stat("2024-01-02 second.md"); !os.IsNotExist(err) {
+		t.Error("Expected pulled note to be gone after undo")
+	}
+	
+	content, _ := os.ReadFile("2024-01-01 first.md")
+	if !strings.Contains(string(content), "local edit") {
+		t.Errorf("Expected uncommitted edit to survive pull and undo, got %q", string(content))
+	}
+}
+
+func TestFormatPullSummary(t *testing.T) {
+	summary := git.ChangeSummary{
+		Changes: []git.FileChange{
+			{Status: 'D', Path: filepath.Join("work", "2024-01-01 old idea.md")},
+			{Status: 'A', Path: filepath.Join("work", "2024-01-02 plan.md")},
+			{Status: 'M', Path: "2024-01-03 groceries.md"},
+			{Status: 'R', Path: filepath.Join("work", "2024-01-04 final.md"), OrigPath: filepath.Join("work", "2024-01-04 draft.md")},
+			{Status: 'M', Path: "readme.md"},
+			{Status: 'A', Path: "image.png"},
+		},
+		Authors: []string{"Alice", "Bob"},
+	}
+	
+	expected := "Changes by Alice, Bob:\n\nroot\n  updated: groceries\n\nwork\n  new:     plan\n  renamed: draft -> final\n  deleted: old idea\n"
+	if result := formatPullSummary(summary); result != expected {
+		t.Errorf("Expected summary %q, got %q", expected, result)
+	}
+	
+	if result := formatPullSummary(git.ChangeSummary{Changes: []git.FileChange{{Status: 'M', Path: "readme.md"}}}); result != "No notes changed.\n" {
+		t.Errorf("Unexpected summary without note changes: %q", result)
+	}
+}
+
+func TestHistoryDiffAndShowCommands(t *testing.T) {
+	tempDir := setupTestRepo(t)
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	
+	if err := os.Chdir(tempDir); err != nil {
+		t.Fatalf("Failed to change directory: %v", err)
+	}
+	
+	os.Mkdir("work", 0755)
+	os.WriteFile("2024-01-01 draft plan.md", []byte("# plan\n\nship the first version of the plan\n"), 0644)
+	gitIn(t, tempDir, "add", "-A")
+	gitIn(t, tempDir, "commit", "-m", "Add plan")
+	gitIn(t, tempDir, "mv", "2024-01-01 draft plan.md", filepath.Join("work", "2024-01-01 plan.md"))
+	gitIn(t, tempDir, "commit", "-m", "Move plan")
+	
+	notePath, err := resolveNote("PLAN")
+	if err != nil || notePath != filepath.Join("work", "2024-01-01 plan.md") {
+		t.Fatalf("Expected title lookup to find the note, got %q %v", notePath, err)
+	}
+	
+	if _, err := resolveNote("missing"); err == nil {
+		t.Error("Expected error for unknown note")
+	}
+	
+	if err := runHistory(nil, []string{"plan"}); err != nil {
+		t.Fatalf("runHistory failed: %v", err)
+	}
+	
+	os.WriteFile(notePath, []byte("# plan\n\nship the second version of the plan\n"), 0644)
+	
+	if err := runDiff(nil, []string{"plan"}); err != nil {
+		t.Fatalf("runDiff failed: %v", err)
+	}
+	
+	content, err := showNoteAt(context.Background(), git.NewManager("."), notePath, "HEAD~1")
+	if err != nil || !strings.Contains(string(content), "first version") {
+		t.Errorf("Expected old content through the rename, got %q %v", string(content), err)
+	}
+	
+	if err := runShow(nil, []string{"plan@HEAD"}); err != nil {
+		t.Fatalf("runShow failed: %v", err)
+	}
+	
+	if err := runShow(nil, []string{"plan"}); err == nil {
+		t.Error("Expected error without a revision")
+	}
+}
+
+func TestRestoreAndTrashCommands(t *testing.T) {
+	tempDir := setupTestRepo(t)
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	
+	if err := os.Chdir(tempDir); err != nil {
+		t.Fatalf("Failed to change directory: %v", err)
+	}
+	
+	os.Mkdir("work", 0755)
+	notePath := filepath.Join("work", "2024-01-01 plan.md")
+	os.WriteFile(notePath, []byte("# plan\n\nfirst\n"), 0644)
+	gitIn(t, tempDir, "add", "-A")
+	gitIn(t, tempDir, "commit", "-m", "Add plan")
+	os.WriteFile(notePath, []byte("# plan\n\nsecond\n"), 0644)
+	gitIn(t, tempDir, "commit", "-am", "Edit plan")
+	gitIn(t, tempDir, "rm", "-q", notePath)
+	gitIn(t, tempDir, "commit", "-m", "Delete plan")
+	
+	os.Mkdir("work", 0755)
+	os.WriteFile(filepath.Join("work", "2024-01-02 plan review.md"), []byte("# plan review\n"), 0644)
+	os.WriteFile(filepath.Join("work", "2024-01-03 draft.md"), []byte("# draft\n\nsome longer text to keep renames detectable\n"), 0644)
+	gitIn(t, tempDir, "add", "-A")
+	gitIn(t, tempDir, "commit", "-m", "Add review and draft")
+	gitIn(t, tempDir, "mv", filepath.Join("work", "2024-01-03 draft.md"), filepath.Join("work", "2024-01-03 final.md"))
+	gitIn(t, tempDir, "commit", "-m", "Rename draft")
+	
+	if err := runTrash(nil, []string{}); err != nil {
+		t.Fatalf("runTrash failed: %v", err)
+	}
+	
+	if deleted, _ := deletedNotes(context.Background(), git.NewManager("."), 10); len(deleted) != 1 || deleted[0].Path != notePath {
+		t.Fatalf("Expected deleted plan in trash, got %v", deleted)
+	}
+	
+	if err := runRestore(nil, []string{"plan"}); err != nil {
+		t.Fatalf("runRestore failed: %v", err)
+	}
+	
+	content, _ := os.ReadFile(notePath)
+	if string(content) != "# plan\n\nsecond\n" {
+		t.Errorf("Expected latest version to be restored, got %q", string(content))
+	}
+	
+	content, _ = os.ReadFile(filepath.Join("work", "2024-01-02 plan review.md"))
+	if string(content) != "# plan review\n" {
+		t.Errorf("Expected the live note with a similar title to be left alone, got %q", string(content))
+	}
+	
+	if deleted, _ := deletedNotes(context.Background(), git.NewManager("."), 10); len(deleted) != 0 {
+		t.Errorf("Expected restored note to leave the trash, got %v", deleted)
+	}
+	
+	gitIn(t, tempDir, "add", "-A")
+	gitIn(t, tempDir, "commit", "-m", "Restore plan")
+	os.WriteFile(notePath, []byte("# plan\n\nlocal edit\n"), 0644)
+	
+	restoreRev = "HEAD~5"
+	defer func() { restoreRev = "" }()
+	
+	if err := runRestore(nil, []string{notePath}); err == nil {
+		t.Error("Expected restore to refuse overwriting uncommitted changes")
+	}
+	
+	content, _ = os.ReadFile(notePath)
+	if string(content) != "# plan\n\nlocal edit\n" {
+		t.Errorf("Expected local edit to be kept, got %q", string(content))
+	}
+	
+	restoreForce = true
+	defer func() { restoreForce = false }()
+	
+	if err := runRestore(nil, []string{notePath}); err != nil {
+		t.Fatalf("runRestore with rev failed: %v", err)
+	}
+	
+	content, _ = os.ReadFile(notePath)
+	if string(content) != "# plan\n\nfirst\n" {
+		t.Errorf("Expected old version to be restored, got %q", string(content))
+	}
+}
+
+func TestFindRepoRoot(t *testing.T) {
+	tempDir := setupTestRepo(t)
+	root, _ := filepath.EvalSymlinks(tempDir)
+	
+	os.MkdirAll(filepath.Join(tempDir, "work", "projects"), 0755)
+	
+	found, err := findRepoRoot(context.Background(), filepath.Join(tempDir, "work", "projects"))
+	if err != nil {
+		t.Fatalf("findRepoRoot failed: %v", err)
+	}
+	if found != root {
+		t.Errorf("Expected the git toplevel %s, got %s", root, found)
+	}
+	
+	os.WriteFile(filepath.Join(tempDir, "work", ".gitnote"), []byte{}, 0644)
+	
+	found, err = findRepoRoot(context.Background(), filepath.Join(tempDir, "work", "projects"))
+	if err != nil {
+		t.Fatalf("findRepoRoot failed: %v", err)
+	}
+	if found != filepath.Join(root, "work") {
+		t.Errorf("Expected the .gitnote marker directory, got %s", found)
+	}
+	
+	plain, _ := filepath.EvalSymlinks(t.TempDir())
+	found, err = findRepoRoot(context.Background(), plain)
+	if err != nil {
+		t.Fatalf("findRepoRoot failed: %v", err)
+	}
+	if found != "" {
+		t.Errorf("Expected no root outside a repository, got %s", found)
+	}
+	
+	t.Setenv("PATH", t.TempDir())
+	
+	found, err = findRepoRoot(context.Background(), filepath.Join(tempDir, "work", "projects"))
+	if err != nil {
+		t.Fatalf("findRepoRoot without git failed: %v", err)
+	}
+	if found != filepath.Join(root, "work") {
+		t.Errorf("Expected the .gitnote marker directory without git, got %s", found)
+	}
+	
+	found, err = findRepoRoot(context.Background(), plain)
+	if err != nil || found != "" {
+		t.Errorf("Expected no root and no error without git, got %q, %v", found, err)
+	}
+}
+
+func TestMarkerInSubdirectoryPaths(t *testing.T) {
+	tempDir := setupTestRepo(t)
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	defer func() { invocationDir = "." }()
+	
+	os.MkdirAll(filepath.Join(tempDir, "notes", "work"), 0755)
+	os.WriteFile(filepath.Join(tempDir, "notes", ".gitnote"), []byte{}, 0644)
+	os.WriteFile(filepath.Join(tempDir, "top.md"), []byte("# top\n"), 0644)
+	notePath := filepath.Join("work", "2024-01-01 plan.md")
+	os.WriteFile(filepath.Join(tempDir, "notes", notePath), []byte("# plan\n\nfirst\n"), 0644)
+	gitIn(t, tempDir, "add", "-A")
+	gitIn(t, tempDir, "commit", "-m", "Add plan")
+	os.WriteFile(filepath.Join(tempDir, "notes", notePath), []byte("# plan\n\nsecond\n"), 0644)
+	gitIn(t, tempDir, "commit", "-am", "Edit plan")
+	gitIn(t, tempDir, "rm", "-q", filepath.Join("notes", notePath))
+	gitIn(t, tempDir, "commit", "-m", "Delete plan")
+	
+	if err := os.Chdir(filepath.Join(tempDir, "notes")); err != nil {
+		t.Fatalf("Failed to change directory: %v", err)
+	}
+	if err := enterRepoRoot(nil, nil); err != nil {
+		t.Fatalf("enterRepoRoot failed: %v", err)
+	}
+	if cwd, _ := os.Getwd(); filepath.Base(cwd) != "notes" {
+		t.Fatalf("Expected to run in the marker directory, got %s", cwd)
+	}
+	
+	gitManager := git.NewManager(".")
+	deleted, err := deletedNotes(context.Background(), gitManager, 0)
+	if err != nil || len(deleted) != 1 || deleted[0].Path != notePath {
+		t.Fatalf("Expected the deleted note relative to the notes root, got %+v, %v", deleted, err)
+	}
+	
+	if err := runRestore(nil, []string{"plan"}); err != nil {
+		t.Fatalf("runRestore failed: %v", err)
+	}
+	if content, _ := os.ReadFile(notePath); string(content) != "# plan\n\nsecond\n" {
+		t.Errorf("Expected the note to be restored inside the notes root, got %q", content)
+	}
+	
+	content, err := showNoteAt(context.Background(), gitManager, notePath, "HEAD~2")
+	if err != nil || string(content) != "# plan\n\nfirst\n" {
+		t.Errorf("Expected the first version, got %q, %v", content, err)
+	}
+	
+	summary, err := gitManager.Changes(context.Background(), "HEAD~2", "HEAD")
+	if err != nil || len(summary.Changes) != 1 || summary.Changes[0].Path != notePath {
+		t.Errorf("Expected changes relative to the notes root, got %+v, %v", summary.Changes, err)
+	}
+	
+	os.WriteFile(filepath.Join(tempDir, "top.md"), []byte("# top\n\nchanged\n"), 0644)
+	if err := runCommit(nil, []string{}); err != nil {
+		t.Fatalf("runCommit failed: %v", err)
+	}
+	
+	status, _ := gitManager.GetStatus(context.Background())
+	if len(status) != 1 || status[0].Path != filepath.Join("..", "top.md") {
+		t.Errorf("Expected the restored note to be committed and top.md to be left alone, got %+v", status)
+	}
+}
+
+func TestEnterRepoRoot(t *testing.T) {
+	tempDir := setupTestRepo(t)
+	root, _ := filepath.EvalSymlinks(tempDir)
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	defer func() { invocationDir = "." }()
+	
+	os.MkdirAll(filepath.Join(tempDir, "work"), 0755)
+	os.Chdir(filepath.Join(tempDir, "work"))
+	
+	if err := enterRepoRoot(nil, nil); err != nil {
+		t.Fatalf("enterRepoRoot failed: %v", err)
+	}
+	
+	if cwd, _ := os.Getwd(); cwd != root {
+		t.Errorf("Expected to run in %s, got %s", root, cwd)
+	}
+	if invocationDir != "work" {
+		t.Errorf("Expected invocation directory work, got %s", invocationDir)
+	}
+	if path := rootPath("ideas.md"); path != filepath.Join("work", "ideas.md") {
+		t.Errorf("Expected work/ideas.md, got %s", path)
+	}
+	if path := rootPath(filepath.Join(root, "ideas.md")); path != "ideas.md" {
+		t.Errorf("Expected ideas.md, got %s", path)
+	}
+	
+	other := setupTestRepo(t)
+	otherRoot, _ := filepath.EvalSymlinks(other)
+	t.Setenv(repoDirEnv, other)
+	
+	if err := enterRepoRoot(nil, nil); err != nil {
+		t.Fatalf("enterRepoRoot failed: %v", err)
+	}
+	if cwd, _ := os.Getwd(); cwd != otherRoot {
+		t.Errorf("Expected GITNOTE_DIR %s to be used, got %s", otherRoot, cwd)
+	}
+	
+	repoDir = tempDir
+	defer func() { repoDir = "" }()
+	
+	if err := enterRepoRoot(nil, nil); err != nil {
+		t.Fatalf("enterRepoRoot failed: %v", err)
+	}
+	if cwd, _ := os.Getwd(); cwd != root {
+		t.Errorf("Expected --repo %s to take precedence, got %s", root, cwd)
+	}
+	
+	repoDir = filepath.Join(tempDir, "missing")
+	if err := enterRepoRoot(nil, nil); err == nil {
+		t.Error("Expected an error for a missing repository directory")
+	}
+}
+
+func TestIndexFromSubdirectory(t *testing.T) {
+	tempDir := setupTestRepo(t)
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	defer func() { invocationDir = "." }()
+	
+	os.MkdirAll(filepath.Join(tempDir, "work"), 0755)
+	os.WriteFile(filepath.Join(tempDir, "root note.md"), []byte("# root note"), 0644)
+	os.WriteFile(filepath.Join(tempDir, "work", "work note.md"), []byte("# work note"), 0644)
+	os.Chdir(filepath.Join(tempDir, "work"))
+	
+	rootCmd.SetArgs([]string{"index"})
+	defer rootCmd.SetArgs(nil)
+	
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("index failed: %v", err)
+	}
+	
+	if _, err := os.Stat(filepath.Join(tempDir, "work", "readme.md")); !os.IsNotExist(err) {
+		t.Error("Expected no readme.md in the subdirectory")
+	}
+	
+	content, err := os.ReadFile(filepath.Join(tempDir, "readme.md"))
+	if err != nil {
+		t.Fatalf("Expected readme.md at the repository root: %v", err)
+	}
+	if !strings.Contains(string(content), "root note") || !strings.Contains(string(content), "work note") {
+		t.Errorf("Expected the index to list notes from the whole repository, got:\n%s", content)
+	}
+}
+
+func TestNotebookCommands(t *testing.T) {
+	configDir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configDir)
+	t.Setenv("HOME", configDir)
+	
+	personal := setupTestRepo(t)
+	work := setupTestRepo(t)
+	os.WriteFile(filepath.Join(personal, "meeting prep.md"), []byte("# meeting prep"), 0644)
+	os.WriteFile(filepath.Join(work, "meeting notes.md"), []byte("# meeting notes"), 0644)
+	
+	searchAll = true
+	defer func() { searchAll = false }()
+	
+	if err := runSearch(nil, []string{"meeting"}); err == nil {
+		t.Error("Expected an error when searching without notebooks")
+	}
+	
+	if err := runNotebookAdd(nil, []string{"personal", personal}); err != nil {
+		t.Fatalf("notebook add failed: %v", err)
+	}
+	if err := runNotebookAdd(nil, []string{"work", work}); err != nil {
+		t.Fatalf("notebook add failed: %v", err)
+	}
+	if err := runNotebookAdd(nil, []string{"work", personal}); err == nil {
+		t.Error("Expected an error for a duplicate notebook")
+	}
+	if err := runNotebookDefault(nil, []string{"work"}); err != nil {
+		t.Fatalf("notebook default failed: %v", err)
+	}
+	if err := runNotebookList(nil, []string{}); err != nil {
+		t.Fatalf("notebook list failed: %v", err)
+	}
+	if err := runSearch(nil, []string{"meeting"}); err != nil {
+		t.Fatalf("search --all failed: %v", err)
+	}
+	
+	registry, err := loadNotebooks()
+	if err != nil {
+		t.Fatalf("Failed to load notebooks: %v", err)
+	}
+	if defaultNotebook, ok := registry.DefaultNotebook(); !ok || defaultNotebook.Name != "work" {
+		t.Errorf("Expected work to be the default notebook, got %+v", defaultNotebook)
+	}
+	
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	defer func() { invocationDir = "." }()
+	
+	personalRoot, _ := filepath.EvalSymlinks(personal)
+	workRoot, _ := filepath.EvalSymlinks(work)
+	
+	os.Chdir(configDir)
+	if err := enterRepoRoot(nil, nil); err != nil {
+		t.Fatalf("enterRepoRoot failed: %v", err)
+	}
+	if cwd, _ := os.Getwd(); cwd != workRoot {
+		t.Errorf("Expected the default notebook outside a repository, got %s", cwd)
+	}
+	
+	notebookName = "personal"
+	defer func() { notebookName = "" }()
+	
+	if err := enterRepoRoot(nil, nil); err != nil {
+		t.Fatalf("enterRepoRoot failed: %v", err)
+	}
+	if cwd, _ := os.Getwd(); cwd != personalRoot {
+		t.Errorf("Expected --notebook personal, got %s", cwd)
+	}
+	
+	notebookName = "missing"
+	if err := enterRepoRoot(nil, nil); err == nil {
+		t.Error("Expected an error for an unknown notebook")
+	}
+	
+	searchDecrypt = true
+	if err := runSearch(nil, []string{"meeting"}); err == nil {
+		t.Error("Expected --decrypt to be rejected with --all")
+	}
+	searchDecrypt = false
+	
+	registryPath, _ := notebook.DefaultPath()
+	valid, _ := os.ReadFile(registryPath)
+	os.WriteFile(registryPath, []byte("notebooks: [unterminated\n"), 0644)
+	notebookName = ""
+	os.Chdir(configDir)
+	if err := enterRepoRoot(nil, nil); err == nil {
+		t.Error("Expected a malformed notebooks.yaml to be reported")
+	}
+	os.WriteFile(registryPath, valid, 0644)
+	
+	if err := runNotebookRemove(nil, []string{"work"}); err != nil {
+		t.Fatalf("notebook remove failed: %v", err)
+	}
+	if err := runNotebookRemove(nil, []string{"work"}); err == nil {
+		t.Error("Expected an error when removing an unknown notebook")
+	}
+}
+
+func TestInitCommand(t *testing.T) {
+	t.Setenv("GIT_AUTHOR_NAME", "Test User")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Test User")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+	
+	notesDir := filepath.Join(t.TempDir(), "notes")
+	
+	initCategories = []string{"work"}
+	defer func() { initCategories = nil }()
+	
+	if err := runInit(nil, []string{notesDir}); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+	
+	for _, path := range []string{".gitnote.yaml", ".gitignore", filepath.Join(".gitnote", "templates", "journal.md"), filepath.Join("work", ".gitkeep"), "readme.md"} {
+		if _, err := os.Stat(filepath.Join(notesDir, path)); err != nil {
+			t.Errorf("Expected %s to be created: %v", path, err)
+		}
+	}
+	
+	if _, err := os.Stat(filepath.Join(notesDir, ".git", "hooks", "pre-commit")); err != nil {
+		t.Errorf("Expected the pre-commit hook to be installed: %v", err)
+	}
+	
+	status, _ := exec.Command("git", "-C", notesDir, "status", "--porcelain").Output()
+	if len(status) != 0 {
+		t.Errorf("Expected the scaffold to be committed, got status %q", status)
+	}
+	
+	if err := runInit(nil, []string{notesDir}); err != nil {
+		t.Fatalf("Re-running init failed: %v", err)
+	}
+	
+	count, _ := exec.Command("git", "-C", notesDir, "rev-list", "--count", "HEAD").Output()
+	if strings.TrimSpace(string(count)) != "1" {
+		t.Errorf("Expected re-running init to leave history alone, got %s commits", count)
+	}
+	
+	os.WriteFile(filepath.Join(notesDir, "broken.md"), []byte("<<<<<<< HEAD\nmine\n=======\ntheirs\n>>>>>>> other\n"), 0644)
+	gitIn(t, notesDir, "add", "broken.md")
+	if err := exec.Command("git", "-C", notesDir, "commit", "-m", "broken").Run(); err == nil {
+		t.Error("Expected the pre-commit hook to reject conflict markers")
+	}
+}
+
+func TestInitCommandClone(t *testing.T) {
+	t.Setenv("GIT_AUTHOR_NAME", "Test User")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Test User")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+	
+	local, _ := setupClonedRepos(t)
+	cloneDir := filepath.Join(t.TempDir(), "clone")
+	
+	initClone = local
+	initNoHooks = true
+	defer func() {
+		initClone = ""
+		initNoHooks = false
+	}()
+	
+	if err := runInit(nil, []string{cloneDir}); err != nil {
+		t.Fatalf("init --clone failed: %v", err)
+	}
+	
+	if _, err := os.Stat(filepath.Join(cloneDir, "2024-01-01 first.md")); err != nil {
+		t.Errorf("Expected the cloned note: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(cloneDir, ".git", "hooks", "pre-commit")); err == nil {
+		t.Error("Expected no hook with --no-hooks")
+	}
+	
+	readme, _ := os.ReadFile(filepath.Join(cloneDir, "readme.md"))
+	if !strings.Contains(string(readme), "first") {
+		t.Errorf("Expected the index to list the cloned note, got %q", readme)
+	}
+}
+
+func TestNonInteractiveOutsideRepo(t *testing.T) {
+	defer git.SetTerminalPrompts(git.TerminalPrompts())
+	defer func() { nonInteractive = false }()
+	
+	for _, command := range []*cobra.Command{initCmd, notebookAddCmd} {
+		git.SetTerminalPrompts(true)
+		nonInteractive = true
+		
+		for parent := command; parent != nil; parent = parent.Parent() {
+			if parent.PersistentPreRunE != nil {
+				if err := parent.PersistentPreRunE(command, nil); err != nil {
+					t.Fatalf("%s pre-run failed: %v", command.Name(), err)
+				}
+				break
+			}
+		}
+		
+		if git.TerminalPrompts() {
+			t.Errorf("Expected --non-interactive to disable git prompts for %s", command.Name())
+		}
+	}
+}
+
+func TestAttachCommands(t *testing.T) {
+	tempDir := setupTestRepo(t)
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	
+	if err := os.Chdir(tempDir); err != nil {
+		t.Fatalf("Failed to change directory: %v", err)
+	}
+	
+	os.MkdirAll("work", 0755)
+	os.WriteFile(filepath.Join("work", "2025-01-05 plan.md"), []byte("# plan\n"), 0644)
+	
+	source := filepath.Join(t.TempDir(), "diagram.png")
+	os.WriteFile(source, []byte("png data"), 0644)
+	
+	if err := runAttach(nil, []string{"plan", source}); err != nil {
+		t.Fatalf("attach failed: %v", err)
+	}
+	
+	content, _ := os.ReadFile(filepath.Join("work", "2025-01-05 plan.md"))
+	if !strings.Contains(string(content), "![diagram](assets/") {
+		t.Errorf("Expected an image link in the note, got %q", content)
+	}
+	
+	if err := runIndex(nil, []string{}); err != nil {
+		t.Fatalf("index failed: %v", err)
+	}
+	readme, _ := os.ReadFile("readme.md")
+	if strings.Contains(string(readme), "assets") {
+		t.Errorf("Expected the index to skip asset directories, got %q", readme)
+	}
+	
+	os.WriteFile(filepath.Join("work", "assets", "orphan.png"), []byte("orphan"), 0644)
+	
+	if err := runAttachmentsGC(nil, []string{}); err != nil {
+		t.Fatalf("attachments gc failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join("work", "assets", "orphan.png")); err != nil {
+		t.Errorf("Expected gc without --delete to keep files: %v", err)
+	}
+	
+	attachmentsDelete = true
+	defer func() { attachmentsDelete = false }()
+	
+	if err := runAttachmentsGC(nil, []string{}); err != nil {
+		t.Fatalf("attachments gc --delete failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join("work", "assets", "orphan.png")); !os.IsNotExist(err) {
+		t.Error("Expected the orphaned attachment to be removed")
+	}
+	
+	entries, _ := os.ReadDir(filepath.Join("work", "assets"))
+	if len(entries) != 1 {
+		t.Errorf("Expected the linked attachment to be kept, got %v", entries)
+	}
+	
+	os.WriteFile(".gitnote.yaml", []byte("attachments:\n  dir: files\n"), 0644)
+	os.MkdirAll(filepath.Join("work", "files"), 0755)
+	os.WriteFile(filepath.Join("work", "files", "exported.md"), []byte("# exported"), 0644)
+	
+	if err := runIndex(nil, []string{}); err != nil {
+		t.Fatalf("index failed: %v", err)
+	}
+	readme, _ = os.ReadFile("readme.md")
+	if strings.Contains(string(readme), "exported") {
+		t.Errorf("Expected the index to skip the configured asset directory, got %q", readme)
+	}
+	
+	os.WriteFile(".gitnote.yaml", []byte("attachments: [\n"), 0644)
+	if err := runIndex(nil, []string{}); err == nil || !strings.Contains(err.Error(), "failed to load config") {
+		t.Errorf("Expected index to report an invalid config, got %v", err)
+	}
+}
+
+func TestCommitLargeAttachments(t *testing.T) {
+	tempDir := setupTestRepo(t)
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	
+	if err := os.Chdir(tempDir); err != nil {
+		t.Fatalf("Failed to change directory: %v", err)
+	}
+	
+	gitPath, _ := exec.LookPath("git")
+	binDir := t.TempDir()
+	os.Symlink(gitPath, filepath.Join(binDir, "git"))
+	t.Setenv("PATH", binDir)
+	
+	os.MkdirAll(filepath.Join("work", "assets"), 0755)
+	os.WriteFile(".gitnote.yaml", []byte("lfs:\n  threshold: 1KB\n  track: true\n"), 0644)
+	os.WriteFile(filepath.Join("work", "plan.md"), []byte("# plan\n\n![mock](assets/mock.psd)\n"), 0644)
+	os.WriteFile(filepath.Join("work", "assets", "mock.psd"), make([]byte, 2048), 0644)
+	
+	if err := runCommit(nil, []string{"work"}); err != nil {
+		t.Fatalf("commit without git lfs failed: %v", err)
+	}
+	
+	output, _ := exec.Command("git", "ls-files").Output()
+	if !strings.Contains(string(output), "work/assets/mock.psd") {
+		t.Errorf("Expected the attachment to be committed with its note, got %q", output)
+	}
+	if strings.Contains(string(output), ".gitattributes") {
+		t.Error("Expected no .gitattributes without git lfs")
+	}
+	
+	os.WriteFile(filepath.Join(binDir, "git-lfs"), []byte("#!/bin/sh\ncase \"$1\" in\nversion) echo fake ;;\ntrack) echo \"$2 filter=lfs diff=lfs merge=lfs -text\" >> .gitattributes ;;\n*) exit 1 ;;\nesac\n"), 0755)
+	os.WriteFile(filepath.Join("work", "assets", "cover.psd"), make([]byte, 4096), 0644)
+	os.WriteFile(filepath.Join("work", "plan.md"), []byte("# plan\n\n![mock](assets/mock.psd)\n![cover](assets/cover.psd)\n"), 0644)
+	
+	stdout, err := os.CreateTemp(t.TempDir(), "stdout")
+	if err != nil {
+		t.Fatalf("Failed to create output file: %v", err)
+	}
+	originalStdout := os.Stdout
+	os.Stdout = stdout
+	err = runCommit(nil, []string{"work"})
+	os.Stdout = originalStdout
+	if err != nil {
+		t.Fatalf("commit with git lfs failed: %v", err)
+	}
+	
+	printed, _ := os.ReadFile(stdout.Name())
+	if !strings.Contains(string(printed), "every .psd file in the repository") || !strings.Contains(string(printed), "in Git LFS instead of the repository (currently ") {
+		t.Errorf("Expected the repository-wide pattern and a size summary, got %q", printed)
+	}
+	
+	output, _ = exec.Command("git", "show", "--name-only", "--format=", "HEAD").Output()
+	if !strings.Contains(string(output), ".gitattributes") || !strings.Contains(string(output), "work/assets/cover.psd") {
+		t.Errorf("Expected .gitattributes and the attachment in the commit, got %q", output)
+	}
+	
+	attributes, _ := os.ReadFile(".gitattributes")
+	if strings.TrimSpace(string(attributes)) != "*.psd filter=lfs diff=lfs merge=lfs -text" {
+		t.Errorf("Expected a single *.psd pattern, got %q", attributes)
+	}
+}
+
+func TestEncryptionCommands(t *testing.T) {
+	configDir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configDir)
+	t.Setenv("HOME", configDir)
+	
+	tempDir := setupTestRepo(t)
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	
+	if err := os.Chdir(tempDir); err != nil {
+		t.Fatalf("Failed to change directory: %v", err)
+	}
+	
+	if err := runKeygen(nil, []string{}); err != nil {
+		t.Fatalf("keygen failed: %v", err)
+	}
+	identity, _ := os.ReadFile(filepath.Join(configDir, "gitnote", "identity.txt"))
+	var recipient string
+	for _, line := range strings.Split(string(identity), "\n") {
+		if strings.HasPrefix(line, "# public key: ") {
+			recipient = strings.TrimPrefix(line, "# public key: ")
+		}
+	}
+	if recipient == "" {
+		t.Fatalf("Expected a public key in the identity file, got %q", identity)
+	}
+	if err := runKeygen(nil, []string{}); err == nil {
+		t.Error("Expected keygen to refuse to overwrite an identity")
+	}
+	
+	os.WriteFile(".gitnote.yaml", []byte("encryption:\n  recipients:\n    - "+recipient+"\n"), 0644)
+	os.MkdirAll("oncall", 0755)
+	os.WriteFile(filepath.Join("oncall", "2025-02-01 database runbook.md"), []byte("# database runbook\n\nroot password: hunter2\n"), 0644)
+	
+	if err := runEncrypt(nil, []string{"oncall"}); err != nil {
+		t.Fatalf("encrypt failed: %v", err)
+	}
+	
+	encryptedPath := filepath.Join("oncall", "2025-02-01 database runbook.md.age")
+	ciphertext, err := os.ReadFile(encryptedPath)
+	if err != nil || strings.Contains(string(ciphertext), "hunter2") {
+		t.Fatalf("Expected only ciphertext on disk, got %q, %v", ciphertext, err)
+	}
+	if _, err := os.Stat(filepath.Join("oncall", "2025-02-01 database runbook.md")); !os.IsNotExist(err) {
+		t.Error("Expected the plain note to be removed")
+	}
+	
+	if err := runIndex(nil, []string{}); err != nil {
+		t.Fatalf("index failed: %v", err)
+	}
+	readme, _ := os.ReadFile("readme.md")
+	if !strings.Contains(string(readme), "database runbook (encrypted)") || strings.Contains(string(readme), "hunter2") {
+		t.Errorf("Expected the index to list the encrypted note by title only, got %q", readme)
+	}
+	
+	searchFull = true
+	searchDecrypt = true
+	defer func() {
+		searchFull = false
+		searchDecrypt = false
+	}()
+	if err := runSearch(nil, []string{"hunter2"}); err != nil {
+		t.Fatalf("search --decrypt failed: %v", err)
+	}
+	
+	editor := filepath.Join(t.TempDir(), "editor.sh")
+	os.WriteFile(editor, []byte("#!/bin/sh\necho 'rotated: 2025-03-01' >> \"$1\"\n"), 0755)
+	t.Setenv("VISUAL", editor)
+	
+	if err := runOpen(nil, []string{"database runbook"}); err != nil {
+		t.Fatalf("open failed: %v", err)
+	}
+	
+	if err := runDecrypt(nil, []string{encryptedPath}); err != nil {
+		t.Fatalf("decrypt failed: %v", err)
+	}
+	plaintext, _ := os.ReadFile(filepath.Join("oncall", "2025-02-01 database runbook.md"))
+	if string(plaintext) != "# database runbook\n\nroot password: hunter2\nrotated: 2025-03-01\n" {
+		t.Errorf("Expected the edit made through open to be kept, got %q", plaintext)
+	}
+}
+
+func TestEncryptWithPassphrase(t *testing.T) {
+	tempDir := setupTestRepo(t)
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	
+	if err := os.Chdir(tempDir); err != nil {
+		t.Fatalf("Failed to change directory: %v", err)
+	}
+	
+	t.Setenv("GITNOTE_PASSPHRASE", "correct horse")
+	os.WriteFile("secrets.md", []byte("# secrets\n"), 0644)
+	
+	if err := runEncrypt(nil, []string{"secrets"}); err != nil {
+		t.Fatalf("encrypt failed: %v", err)
+	}
+	if err := runEncrypt(nil, []string{"."}); err == nil {
+		t.Error("Expected encrypting the whole repository to be refused")
+	}
+	
+	t.Setenv("GITNOTE_PASSPHRASE", "battery staple")
+	if err := runDecrypt(nil, []string{"secrets.md.age"}); err == nil {
+		t.Error("Expected the wrong passphrase to fail")
+	}
+	
+	t.Setenv("GITNOTE_PASSPHRASE", "correct horse")
+	if err := runDecrypt(nil, []string{"secrets.md.age"}); err != nil {
+		t.Fatalf("decrypt failed: %v", err)
+	}
+	if content, _ := os.ReadFile("secrets.md"); string(content) != "# secrets\n" {
+		t.Errorf("Unexpected content after decrypting: %q", content)
+	}
+}
+
+func TestEncryptedCategoryGuards(t *testing.T) {
+	tempDir := setupTestRepo(t)
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	
+	if err := os.Chdir(tempDir); err != nil {
+		t.Fatalf("Failed to change directory: %v", err)
+	}
+	
+	t.Setenv("GITNOTE_PASSPHRASE", "correct horse")
+	os.MkdirAll("vault", 0755)
+	os.WriteFile(filepath.Join("vault", "2025-02-01 old.md"), []byte("# old\n\nhunter2\n"), 0644)
+	exec.Command("git", "add", ".").Run()
+	exec.Command("git", "commit", "-m", "Add old").Run()
+	
+	os.WriteFile(".gitnote.yaml", []byte("encryption:\n  categories:\n    - vault\n"), 0644)
+	os.WriteFile(filepath.Join("vault", "2025-02-02 new.md"), []byte("# new\n\nhunter3\n"), 0644)
+	
+	if err := runCommit(nil, []string{}); err == nil || !strings.Contains(err.Error(), "2025-02-02 new.md") {
+		t.Errorf("Expected commit to refuse a plain note in an encrypted category, got %v", err)
+	}
+	
+	cfg, _ := config.Load(".")
+	gitManager, _ := newGitManager()
+	if _, err := commitAllNotes(context.Background(), gitManager, cfg); err == nil {
+		t.Error("Expected sync to refuse a plain note in an encrypted category")
+	}
+	
+	stdout, err := os.CreateTemp(t.TempDir(), "stdout")
+	if err != nil {
+		t.Fatalf("Failed to create output file: %v", err)
+	}
+	originalStdout := os.Stdout
+	os.Stdout = stdout
+	err = runEncrypt(nil, []string{"vault"})
+	os.Stdout = originalStdout
+	if err != nil {
+		t.Fatalf("encrypt failed: %v", err)
+	}
+	
+	printed, _ := os.ReadFile(stdout.Name())
+	if !strings.Contains(string(printed), "Warning: "+filepath.Join("vault", "2025-02-01 old.md")+" was already committed") {
+		t.Errorf("Expected a warning about the plain text in history, got %q", printed)
+	}
+	if strings.Contains(string(printed), "2025-02-02 new.md was already committed") {
+		t.Errorf("Expected no history warning for an uncommitted note, got %q", printed)
+	}
+	
+	if err := runCommit(nil, []string{}); err != nil {
+		t.Fatalf("commit of encrypted notes failed: %v", err)
+	}
+	
+	os.WriteFile(".gitnote.yaml", []byte("encryption:\n  categories:\n    - vault\n  recipients:\n    - not-a-key\n"), 0644)
+	cfg, _ = config.Load(".")
+	if _, err := createNote(note.NewManager("."), cfg, "vault", "leak"); err == nil {
+		t.Error("Expected creating a note with a broken encryption config to fail")
+	}
+	if matches, _ := filepath.Glob(filepath.Join("vault", "*leak.md")); len(matches) != 0 {
+		t.Errorf("Expected the plain note to be removed after encryption failed, got %v", matches)
+	}
+	
+	decrypt := lazyDecrypter()
+	if _, err := decrypt(nil); err == nil {
+		t.Error("Expected the decrypter to fail with a broken encryption config")
+	}
+	os.WriteFile(".gitnote.yaml", []byte("encryption:\n  categories:\n    - vault\n"), 0644)
+	ciphertext, _ := os.ReadFile(filepath.Join("vault", "2025-02-01 old.md.age"))
+	if _, err := decrypt(ciphertext); err == nil {
+		t.Error("Expected the decrypter to remember its first failure")
+	}
+	
+	searchDecrypt = true
+	defer func() {
+		searchFull = false
+		searchDecrypt = false
+	}()
+	if err := runSearch(nil, []string{"hunter2"}); err == nil || !strings.Contains(err.Error(), "--full") {
+		t.Errorf("Expected --decrypt without --full to be rejected, got %v", err)
+	}
+	
+	searchFull = true
+	t.Setenv("GITNOTE_PASSPHRASE", "battery staple")
+	if err := runSearch(nil, []string{"hunter2"}); err == nil || !strings.Contains(err.Error(), "failed to decrypt") {
+		t.Errorf("Expected search to report the decryption failure, got %v", err)
+	}
+	
+	t.Setenv("GITNOTE_PASSPHRASE", "correct horse")
+	if err := runSearch(nil, []string{"hunter2"}); err != nil {
+		t.Errorf("search --full --decrypt failed: %v", err)
+	}
+}
@@ -2,100 +2,330 @@ package cmd
 
 import (
 	"fmt"
+	"path/filepath"
 	"strings"
 
+	"github.com/manifoldco/promptui"
 	"github.com/spf13/cobra"
 
+	"gitnote/internal/attachment"
+	"gitnote/internal/config"
 	"gitnote/internal/git"
+	"gitnote/internal/note"
+)
+
+var (
+	commitMessage     string
+	commitInteractive bool
+	commitAmend       bool
 )
 
 var commitCmd = &cobra.Command{
-	Use:   "commit",
-	Short: "Commit new or updated files to git",
-	Long:  "Add and commit any new or updated files with a descriptive commit message",
+	Use:   "commit [path or category...]",
+	Short: "Commit new, updated, renamed or deleted notes to git",
+	Long:  "Stage changes to notes (including deletions and renames) and commit them with a message listing the affected notes by title. Pass paths or categories, or use --interactive, to commit only some notes",
 	RunE:  runCommit,
 }
 
+func init() {
+	commitCmd.Flags().StringVarP(&commitMessage, "message", "m", "", "Use this commit message instead of the generated one")
+	commitCmd.Flags().BoolVarP(&commitInteractive, "interactive", "i", false, "Choose the changed notes to commit from a checklist")
+	commitCmd.Flags().BoolVar(&commitAmend, "amend", false, "Amend the previous commit instead of creating a new one")
+}
+
+type noteRename struct {
+	From string
+	To   string
+}
+
+type noteChanges struct {
+	Added   []string
+	Updated []string
+	Renamed []noteRename
+	Deleted []string
+}
+
 func runCommit(cmd *cobra.Command, args []string) error {
-	gitManager := git.NewManager(".")
+	ctx := commandContext(cmd)
+	args = rootPaths(args)
+	gitManager, err := newGitManager()
+	if err != nil {
+		return err
+	}
 	
-	if !gitManager.IsGitRepo() {
+	if !gitManager.IsGitRepo(ctx) {
 		return fmt.Errorf("current directory is not a git repository")
 	}
 	
-	status, err := gitManager.GetStatus()
+	cfg, err := config.Load(".")
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	
+	status, err := gitManager.GetStatus(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to get git status: %w", err)
 	}
 	
-	if len(status) == 0 {
+	var candidates []git.StatusEntry
+	for _, entry := range status {
+		if isNoteChange(entry, cfg.Attachments.Dir) && matchesCommitArgs(entry, args) {
+			candidates = append(candidates, entry)
+		}
+	}
+	
+	selective := len(args) > 0 || commitInteractive
+	
+	if commitInteractive && len(candidates) > 0 {
+		candidates, err = selectCommitEntries(candidates)
+		if err != nil {
+			return err
+		}
+	}
+	
+	if err := checkPlainNotes(cfg, candidates); err != nil {
+		return err
+	}
+	
+	var notePaths []string
+	for _, entry := range candidates {
+		notePaths = append(notePaths, entry.Path)
+		if entry.OrigPath != "" {
+			notePaths = append(notePaths, entry.OrigPath)
+		}
+	}
+	
+	if selective && len(notePaths) == 0 && !commitAmend {
 		fmt.Println("No changes to commit")
 		return nil
 	}
 	
-	var newFiles []string
-	var modifiedFiles []string
-	var filesToAdd []string
+	if err := gitManager.AddAll(ctx, unstagedPaths(candidates)); err != nil {
+		return fmt.Errorf("failed to add files: %w", err)
+	}
 	
-	for _, line := range status {
-		if len(line) < 3 {
+	tracking, err := checkLargeFiles(ctx, gitManager, cfg.LFS, notePaths)
+	if err != nil {
+		return err
+	}
+	notePaths = append(notePaths, tracking...)
+	
+	status, err = gitManager.GetStatus(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get git status: %w", err)
+	}
+	
+	var included []string
+	if selective {
+		included = notePaths
+	}
+	
+	changes, staged := collectNoteChanges(status, included)
+	
+	if commitAmend {
+		if err := gitManager.Amend(ctx, commitMessage, included); err != nil {
+			return fmt.Errorf("failed to amend commit: %w", err)
+		}
+		fmt.Println("Amended the previous commit")
+		return nil
+	}
+	
+	if !staged {
+		fmt.Println("No changes to commit")
+		return nil
+	}
+	
+	message := commitMessage
+	if message == "" {
+		message = buildCommitMessage(changes)
+	}
+	
+	if selective {
+		err = gitManager.CommitPaths(ctx, message, included)
+	} else {
+		err = gitManager.Commit(ctx, message)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to commit: %w", err)
+	}
+	
+	fmt.Printf("Committed changes with message: %s\n", message)
+	return nil
+}
+
+func unstagedPaths(entries []git.StatusEntry) []string {
+	var paths []string
+	for _, entry := range entries {
+		if !entry.IsUntracked() && !entry.HasWorktreeChanges() {
 			continue
 		}
+		paths = append(paths, entry.Path)
+		if entry.OrigPath != "" && entry.Worktree == 'R' {
+			paths = append(paths, entry.OrigPath)
+		}
+	}
+	
+	return paths
+}
+
+func isNoteChange(entry git.StatusEntry, assetDir string) bool {
+	for _, path := range []string{entry.Path, entry.OrigPath} {
+		if path != "" && filepath.IsLocal(path) && (note.IsNotePath(path) || attachment.IsAssetPath(path, assetDir)) {
+			return true
+		}
+	}
+	
+	return false
+}
+
+func matchesCommitArgs(entry git.StatusEntry, args []string) bool {
+	if len(args) == 0 {
+		return true
+	}
+	
+	for _, arg := range args {
+		target := filepath.Clean(arg)
+		for _, path := range []string{entry.Path, entry.OrigPath} {
+			if path != "" && (path == target || note.InCategory(filepath.Dir(path), target)) {
+				return true
+			}
+		}
+	}
+	
+	return false
+}
+
+func selectCommitEntries(entries []git.StatusEntry) ([]git.StatusEntry, error) {
+	selected := make([]bool, len(entries))
+	for i := range selected {
+		selected[i] = true
+	}
+	
+	const commitSelected = "Commit selected notes"
+	cursor := 0
+	
+	for {
+		items := make([]string, 0, len(entries)+1)
+		for i, entry := range entries {
+			mark := "[ ]"
+			if selected[i] {
+				mark = "[x]"
+			}
+			items = append(items, fmt.Sprintf("%s %s", mark, entry))
+		}
+		items = append(items, commitSelected)
+		
+		prompt := promptui.Select{
+			Label: "Toggle notes to commit",
+			Items: items,
+			Size:  10,
+		}
 		
-		statusCode := line[:2]
-		filename := strings.TrimSpace(line[3:])
+		index, _, err := prompt.RunCursorAt(cursor, 0)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get user choice: %w", err)
+		}
 		
-		switch statusCode {
-		case "??":
-			newFiles = append(newFiles, filename)
-			filesToAdd = append(filesToAdd, filename)
-		case " M", "MM":
-			modifiedFiles = append(modifiedFiles, filename)
-			filesToAdd = append(filesToAdd, filename)
-		case "M ", "A ":
-		default:
-			filesToAdd = append(filesToAdd, filename)
+		if index == len(entries) {
+			break
 		}
+		
+		selected[index] = !selected[index]
+		cursor = index
 	}
 	
-	if len(filesToAdd) > 0 {
-		if err := gitManager.AddFiles(filesToAdd); err != nil {
-			return fmt.Errorf("failed to add files: %w", err)
+	var chosen []git.StatusEntry
+	for i, entry := range entries {
+		if selected[i] {
+			chosen = append(chosen, entry)
 		}
 	}
 	
-	commitMessage := buildCommitMessage(newFiles, modifiedFiles)
+	return chosen, nil
+}
+
+func collectNoteChanges(status []git.StatusEntry, paths []string) (noteChanges, bool) {
+	var changes noteChanges
+	staged := false
 	
-	if err := gitManager.Commit(commitMessage); err != nil {
-		return fmt.Errorf("failed to commit: %w", err)
+	included := make(map[string]bool)
+	for _, path := range paths {
+		included[path] = true
 	}
 	
-	fmt.Printf("Committed changes with message: %s\n", commitMessage)
-	return nil
+	for _, entry := range status {
+		if !entry.IsStaged() || (paths != nil && !included[entry.Path]) {
+			continue
+		}
+		staged = true
+		
+		if !note.IsNotePath(entry.Path) {
+			continue
+		}
+		
+		switch entry.Index {
+		case 'A', 'C':
+			changes.Added = append(changes.Added, noteTitle(entry.Path))
+		case 'D':
+			changes.Deleted = append(changes.Deleted, noteTitle(entry.Path))
+		case 'R':
+			rename := noteRename{From: noteTitle(entry.OrigPath), To: noteTitle(entry.Path)}
+			if rename.From == rename.To {
+				rename.To = filepath.ToSlash(strings.TrimSuffix(entry.Path, filepath.Ext(entry.Path)))
+			}
+			changes.Renamed = append(changes.Renamed, rename)
+		default:
+			changes.Updated = append(changes.Updated, noteTitle(entry.Path))
+		}
+	}
+	
+	return changes, staged
+}
+
+func noteTitle(path string) string {
+	title, _, _ := note.ParseFilename(filepath.Base(path))
+	return title
 }
 
-func buildCommitMessage(newFiles, modifiedFiles []string) string {
+func buildCommitMessage(changes noteChanges) string {
 	var messageParts []string
+	var body []string
 	
-	if len(newFiles) > 0 {
-		if len(newFiles) == 1 {
-			messageParts = append(messageParts, fmt.Sprintf("Add %s", newFiles[0]))
+	summarise := func(verb, heading string, titles []string) {
+		if len(titles) == 0 {
+			return
+		}
+		
+		if len(titles) == 1 {
+			messageParts = append(messageParts, fmt.Sprintf("%s %s", verb, titles[0]))
 		} else {
-			messageParts = append(messageParts, fmt.Sprintf("Add %d new files", len(newFiles)))
+			messageParts = append(messageParts, fmt.Sprintf("%s %d notes", verb, len(titles)))
+		}
+		
+		body = append(body, heading+":")
+		for _, title := range titles {
+			body = append(body, "- "+title)
 		}
 	}
 	
-	if len(modifiedFiles) > 0 {
-		if len(modifiedFiles) == 1 {
-			messageParts = append(messageParts, fmt.Sprintf("Update %s", modifiedFiles[0]))
-		} else {
-			messageParts = append(messageParts, fmt.Sprintf("Update %d files", len(modifiedFiles)))
-		}
+	var renamed []string
+	for _, rename := range changes.Renamed {
+		renamed = append(renamed, fmt.Sprintf("%s to %s", rename.From, rename.To))
 	}
 	
+	summarise("Add", "Added", changes.Added)
+	summarise("Update", "Updated", changes.Updated)
+	summarise("Rename", "Renamed", renamed)
+	summarise("Delete", "Deleted", changes.Deleted)
+	
 	if len(messageParts) == 0 {
 		return "Update files"
 	}
 	
-	return strings.Join(messageParts, " and ")
+	message := strings.Join(messageParts, " and ")
+	if len(changes.Added)+len(changes.Updated)+len(changes.Renamed)+len(changes.Deleted) > 1 {
+		message += "\n\n" + strings.Join(body, "\n")
+	}
+	
+	return message
 }
\ No newline at end of file
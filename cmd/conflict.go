@@ -0,0 +1,206 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/manifoldco/promptui"
+
+	"gitnote/internal/config"
+	"gitnote/internal/git"
+)
+
+const (
+	conflictKeepLocal  = "Keep my version"
+	conflictKeepRemote = "Keep their version"
+	conflictKeepBoth   = "Keep both (save theirs as a separate conflict note)"
+	conflictEdit       = "Open in editor"
+	conflictMergeTool  = "Launch merge tool"
+	conflictSkip       = "Leave unresolved for now"
+)
+
+func resolveConflictsInteractively(ctx context.Context, gitManager *git.Manager) (bool, error) {
+	cfg, err := config.Load(".")
+	if err != nil {
+		return false, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	for {
+		conflicted, err := conflictedPaths(ctx, gitManager)
+		if err != nil {
+			return false, err
+		}
+
+		state, err := gitManager.State(ctx)
+		if err != nil {
+			return false, err
+		}
+
+		if len(conflicted) > 0 {
+			for _, path := range conflicted {
+				resolved, err := promptConflictResolution(ctx, gitManager, path, state, cfg.Sync.MergeTool)
+				if err != nil || !resolved {
+					return false, err
+				}
+			}
+			continue
+		}
+
+		if state == git.StateNone {
+			return true, nil
+		}
+
+		if err := gitManager.Continue(ctx); err != nil {
+			hasConflicts, conflictErr := gitManager.HasMergeConflicts(ctx)
+			if conflictErr != nil || !hasConflicts {
+				return false, err
+			}
+			if _, err := resolveIndexConflicts(ctx, gitManager); err != nil {
+				return false, err
+			}
+		}
+	}
+}
+
+func promptConflictResolution(ctx context.Context, gitManager *git.Manager, path, state, mergeTool string) (bool, error) {
+	for {
+		prompt := promptui.Select{
+			Label: fmt.Sprintf("Conflict in %s", path),
+			Items: []string{conflictKeepLocal, conflictKeepRemote, conflictKeepBoth, conflictEdit, conflictMergeTool, conflictSkip},
+		}
+
+		_, action, err := prompt.Run()
+		if err != nil {
+			return false, fmt.Errorf("failed to get user choice: %w", err)
+		}
+
+		if action == conflictSkip {
+			return false, nil
+		}
+
+		if err := resolveConflict(ctx, gitManager, path, action, state, mergeTool); err != nil {
+			fmt.Printf("Could not resolve %s: %v\n", path, err)
+			continue
+		}
+
+		fmt.Printf("Resolved %s\n", path)
+		return true, nil
+	}
+}
+
+func resolveConflict(ctx context.Context, gitManager *git.Manager, path, action, state, mergeTool string) error {
+	local, remote := git.StageOurs, git.StageTheirs
+	if state == git.StateRebase {
+		local, remote = remote, local
+	}
+
+	switch action {
+	case conflictKeepLocal:
+		return keepConflictStage(ctx, gitManager, path, local)
+	case conflictKeepRemote:
+		return keepConflictStage(ctx, gitManager, path, remote)
+	case conflictKeepBoth:
+		content, exists, err := gitManager.ShowStage(ctx, path, remote)
+		if err != nil {
+			return err
+		}
+		if err := keepConflictStage(ctx, gitManager, path, local); err != nil {
+			return err
+		}
+		if !exists {
+			return nil
+		}
+		copyPath := conflictCopyPath(path)
+		if err := os.WriteFile(copyPath, content, 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", copyPath, err)
+		}
+		fmt.Printf("Saved their version as %s\n", copyPath)
+		return gitManager.AddAll(ctx, []string{copyPath})
+	case conflictEdit:
+		if err := openInEditor(path); err != nil {
+			return err
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		if hasConflictMarkers(string(content)) {
+			return fmt.Errorf("conflict markers are still present")
+		}
+		return gitManager.AddAll(ctx, []string{path})
+	case conflictMergeTool:
+		if err := gitManager.MergeTool(ctx, path, mergeTool); err != nil {
+			return err
+		}
+		status, err := gitManager.GetStatus(ctx)
+		if err != nil {
+			return err
+		}
+		for _, entry := range status {
+			if entry.Path == path && entry.IsConflicted() {
+				return fmt.Errorf("merge tool did not resolve the conflict")
+			}
+		}
+		return nil
+	}
+
+	return fmt.Errorf("unknown action %q", action)
+}
+
+func keepConflictStage(ctx context.Context, gitManager *git.Manager, path string, stage int) error {
+	content, exists, err := gitManager.ShowStage(ctx, path, stage)
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		if err := os.WriteFile(path, content, 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+	} else if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove %s: %w", path, err)
+	}
+
+	return gitManager.AddAll(ctx, []string{path})
+}
+
+func conflictCopyPath(path string) string {
+	ext := filepath.Ext(path)
+	base := strings.TrimSuffix(path, ext)
+
+	candidate := fmt.Sprintf("%s (conflict)%s", base, ext)
+	for i := 2; ; i++ {
+		if _, err := os.Stat(candidate); os.IsNotExist(err) {
+			return candidate
+		}
+		candidate = fmt.Sprintf("%s (conflict %d)%s", base, i, ext)
+	}
+}
+
+func hasConflictMarkers(content string) bool {
+	for _, line := range strings.Split(content, "\n") {
+		if strings.HasPrefix(line, "<<<<<<< ") || strings.HasPrefix(line, ">>>>>>> ") {
+			return true
+		}
+	}
+	return false
+}
+
+func conflictedPaths(ctx context.Context, gitManager *git.Manager) ([]string, error) {
+	status, err := gitManager.GetStatus(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get git status: %w", err)
+	}
+
+	var paths []string
+	for _, entry := range status {
+		if entry.IsConflicted() {
+			paths = append(paths, entry.Path)
+		}
+	}
+
+	return paths, nil
+}
@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+func editorCommand() []string {
+	for _, variable := range []string{"VISUAL", "EDITOR"} {
+		if fields := strings.Fields(os.Getenv(variable)); len(fields) > 0 {
+			return fields
+		}
+	}
+
+	if runtime.GOOS == "windows" {
+		return []string{"notepad"}
+	}
+
+	return []string{"vi"}
+}
+
+func openInEditor(path string) error {
+	editor := editorCommand()
+
+	cmd := exec.Command(editor[0], append(editor[1:], path)...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to run editor %s: %w", editor[0], err)
+	}
+
+	return nil
+}
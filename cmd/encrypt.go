@@ -0,0 +1,359 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/manifoldco/promptui"
+	"github.com/spf13/cobra"
+
+	"gitnote/internal/config"
+	"gitnote/internal/crypt"
+	"gitnote/internal/git"
+	"gitnote/internal/note"
+)
+
+const passphraseEnv = "GITNOTE_PASSPHRASE"
+
+var openCmd = &cobra.Command{
+	Use:   "open <note>",
+	Short: "Open a note in $EDITOR, decrypting it if needed",
+	Long:  "Open a note by path or title in $EDITOR. Encrypted notes are decrypted to a private temporary file and encrypted again when the editor exits with changes",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runOpen,
+}
+
+var encryptCmd = &cobra.Command{
+	Use:   "encrypt <note or category>",
+	Short: "Encrypt a note, or every note in a category",
+	Long:  "Replace plain notes with age-encrypted <name>.md.age files. Uses encryption.recipients from .gitnote.yaml, or a passphrase when no recipients are configured",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runEncrypt,
+}
+
+var decryptCmd = &cobra.Command{
+	Use:   "decrypt <note>",
+	Short: "Turn an encrypted note back into a plain note",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runDecrypt,
+}
+
+var keygenCmd = &cobra.Command{
+	Use:   "keygen",
+	Short: "Generate an age identity for encrypted notes",
+	Long:  "Write a new age identity to the user config directory (or encryption.identity) and print its public key to add to encryption.recipients",
+	Args:  cobra.NoArgs,
+	RunE:  runKeygen,
+}
+
+func newCipher(confirm bool) (*crypt.Cipher, error) {
+	cfg, err := config.Load(".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	return crypt.New(cfg.Encryption, func() (string, error) {
+		return readPassphrase(confirm)
+	})
+}
+
+func readPassphrase(confirm bool) (string, error) {
+	if secret := os.Getenv(passphraseEnv); secret != "" {
+		return secret, nil
+	}
+
+	prompt := promptui.Prompt{Label: "Passphrase", Mask: '*'}
+	secret, err := prompt.Run()
+	if err != nil {
+		return "", err
+	}
+
+	if confirm {
+		prompt = promptui.Prompt{Label: "Confirm passphrase", Mask: '*'}
+		again, err := prompt.Run()
+		if err != nil {
+			return "", err
+		}
+		if again != secret {
+			return "", fmt.Errorf("passphrases do not match")
+		}
+	}
+
+	return secret, nil
+}
+
+func lazyDecrypter() note.Decrypter {
+	var cipher *crypt.Cipher
+	var failure error
+	return func(ciphertext []byte) ([]byte, error) {
+		if failure != nil {
+			return nil, failure
+		}
+		if cipher == nil {
+			c, err := newCipher(false)
+			if err != nil {
+				failure = err
+				return nil, err
+			}
+			cipher = c
+		}
+		return cipher.Decrypt(ciphertext)
+	}
+}
+
+func isEncryptedCategory(cfg *config.Config, notePath string) bool {
+	for _, category := range cfg.Encryption.Categories {
+		if note.InCategory(filepath.Dir(notePath), category) {
+			return true
+		}
+	}
+	return false
+}
+
+func checkPlainNotes(cfg *config.Config, entries []git.StatusEntry) error {
+	var plain []string
+	for _, entry := range entries {
+		if !entry.IsDeleted() && note.IsNotePath(entry.Path) && !note.IsEncryptedPath(entry.Path) && isEncryptedCategory(cfg, entry.Path) {
+			plain = append(plain, entry.Path)
+		}
+	}
+
+	if len(plain) == 0 {
+		return nil
+	}
+	return fmt.Errorf("refusing to commit plain notes in encrypted categories: %s (run 'gitnote encrypt' on them first)", strings.Join(plain, ", "))
+}
+
+func encryptNote(cipher *crypt.Cipher, notePath string) (string, error) {
+	content, err := os.ReadFile(notePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read note: %w", err)
+	}
+
+	ciphertext, err := cipher.Encrypt(content)
+	if err != nil {
+		return "", err
+	}
+
+	encryptedPath := notePath + note.EncryptedExt
+	if _, err := os.Stat(encryptedPath); err == nil {
+		return "", fmt.Errorf("%s already exists", encryptedPath)
+	}
+
+	if err := os.WriteFile(encryptedPath, ciphertext, 0644); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", encryptedPath, err)
+	}
+	if err := os.Remove(notePath); err != nil {
+		return "", fmt.Errorf("failed to remove plain note: %w", err)
+	}
+
+	return encryptedPath, nil
+}
+
+func runOpen(cmd *cobra.Command, args []string) error {
+	notePath, err := resolveNote(args[0])
+	if err != nil {
+		return err
+	}
+
+	if !note.IsEncryptedPath(notePath) {
+		return openInEditor(notePath)
+	}
+
+	cipher, err := newCipher(false)
+	if err != nil {
+		return err
+	}
+
+	ciphertext, err := os.ReadFile(notePath)
+	if err != nil {
+		return fmt.Errorf("failed to read note: %w", err)
+	}
+
+	plaintext, err := cipher.Decrypt(ciphertext)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt %s: %w", notePath, err)
+	}
+
+	tempDir, err := os.MkdirTemp("", "gitnote-")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	tempPath := filepath.Join(tempDir, strings.TrimSuffix(filepath.Base(notePath), note.EncryptedExt))
+	if err := os.WriteFile(tempPath, plaintext, 0600); err != nil {
+		return fmt.Errorf("failed to write temporary file: %w", err)
+	}
+
+	if err := openInEditor(tempPath); err != nil {
+		return err
+	}
+
+	edited, err := os.ReadFile(tempPath)
+	if err != nil {
+		return fmt.Errorf("failed to read edited note: %w", err)
+	}
+
+	if bytes.Equal(edited, plaintext) {
+		fmt.Println("No changes")
+		return nil
+	}
+
+	ciphertext, err = cipher.Encrypt(edited)
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(notePath, ciphertext, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", notePath, err)
+	}
+
+	fmt.Printf("Saved encrypted note: %s\n", notePath)
+	return nil
+}
+
+func runEncrypt(cmd *cobra.Command, args []string) error {
+	target := rootPath(args[0])
+
+	var notePaths []string
+	if info, err := os.Stat(target); err == nil && info.IsDir() {
+		if filepath.Clean(target) == "." {
+			return fmt.Errorf("choose a category to encrypt rather than the whole repository")
+		}
+		noteManager, err := newNoteManager()
+		if err != nil {
+			return err
+		}
+		notes, err := noteManager.FindNotesInCategory(target)
+		if err != nil {
+			return fmt.Errorf("failed to find notes: %w", err)
+		}
+		for _, n := range notes {
+			if !n.Encrypted {
+				notePaths = append(notePaths, n.Path)
+			}
+		}
+	} else {
+		notePath, err := resolveNote(args[0])
+		if err != nil {
+			return err
+		}
+		if note.IsEncryptedPath(notePath) {
+			return fmt.Errorf("%s is already encrypted", notePath)
+		}
+		notePaths = append(notePaths, notePath)
+	}
+
+	if len(notePaths) == 0 {
+		fmt.Println("No plain notes to encrypt")
+		return nil
+	}
+
+	cipher, err := newCipher(true)
+	if err != nil {
+		return err
+	}
+
+	committed := committedNotes(commandContext(cmd), notePaths)
+
+	for _, notePath := range notePaths {
+		encryptedPath, err := encryptNote(cipher, notePath)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Encrypted %s\n", encryptedPath)
+	}
+
+	for _, notePath := range committed {
+		fmt.Printf("Warning: %s was already committed, so its plain text is still in the git history; rewrite the history (for example with git filter-repo) if it must stay secret\n", notePath)
+	}
+
+	return nil
+}
+
+func committedNotes(ctx context.Context, notePaths []string) []string {
+	gitManager, err := newGitManager()
+	if err != nil || !gitManager.IsGitRepo(ctx) {
+		return nil
+	}
+
+	var committed []string
+	for _, notePath := range notePaths {
+		commits, err := gitManager.History(ctx, notePath)
+		if err != nil {
+			fmt.Printf("Warning: could not check the git history of %s: %v\n", notePath, err)
+			continue
+		}
+		if len(commits) > 0 {
+			committed = append(committed, notePath)
+		}
+	}
+	return committed
+}
+
+func runDecrypt(cmd *cobra.Command, args []string) error {
+	notePath, err := resolveNote(args[0])
+	if err != nil {
+		return err
+	}
+	if !note.IsEncryptedPath(notePath) {
+		return fmt.Errorf("%s is not encrypted", notePath)
+	}
+
+	cipher, err := newCipher(false)
+	if err != nil {
+		return err
+	}
+
+	ciphertext, err := os.ReadFile(notePath)
+	if err != nil {
+		return fmt.Errorf("failed to read note: %w", err)
+	}
+
+	plaintext, err := cipher.Decrypt(ciphertext)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt %s: %w", notePath, err)
+	}
+
+	plainPath := strings.TrimSuffix(notePath, note.EncryptedExt)
+	if _, err := os.Stat(plainPath); err == nil {
+		return fmt.Errorf("%s already exists", plainPath)
+	}
+
+	if err := os.WriteFile(plainPath, plaintext, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", plainPath, err)
+	}
+	if err := os.Remove(notePath); err != nil {
+		return fmt.Errorf("failed to remove encrypted note: %w", err)
+	}
+
+	fmt.Printf("Decrypted %s\n", plainPath)
+	return nil
+}
+
+func runKeygen(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load(".")
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	identityPath, err := crypt.IdentityPath(cfg.Encryption)
+	if err != nil {
+		return err
+	}
+
+	recipient, err := crypt.GenerateIdentity(identityPath)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Wrote identity to %s\n", identityPath)
+	fmt.Printf("Public key: %s\n", recipient)
+	fmt.Println("Add the public key to encryption.recipients in .gitnote.yaml, and keep the identity file safe: it is the only way to read your encrypted notes")
+	return nil
+}
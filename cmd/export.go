@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"gitnote/internal/config"
+	"gitnote/internal/export"
+)
+
+var (
+	exportFormat   string
+	exportCategory string
+	exportQuery    string
+	exportFull     bool
+	exportOutput   string
+	exportTitle    string
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export notes to a single Markdown, EPUB or JSON file",
+	Long:  "Export a category or search result set as a single Markdown document with a table of contents, an EPUB, or a JSON archive",
+	Args:  cobra.NoArgs,
+	RunE:  runExport,
+}
+
+func init() {
+	exportCmd.Flags().StringVar(&exportFormat, "format", export.FormatMarkdown, "Export format (md, epub or json)")
+	exportCmd.Flags().StringVar(&exportCategory, "category", "", "Only export notes in this category and its subcategories")
+	exportCmd.Flags().StringVar(&exportQuery, "query", "", "Only export notes matching this search query")
+	exportCmd.Flags().BoolVar(&exportFull, "full", false, "Search in file content as well as titles when using --query")
+	exportCmd.Flags().StringVarP(&exportOutput, "output", "o", "", "Output file (defaults to stdout for md and json, or <title>.epub)")
+	exportCmd.Flags().StringVar(&exportTitle, "title", "", "Title of the export (defaults to the category name)")
+}
+
+func runExport(cmd *cobra.Command, args []string) error {
+	if !export.IsValidFormat(exportFormat) {
+		return fmt.Errorf("unsupported export format %q: use md, epub or json", exportFormat)
+	}
+
+	cfg, err := config.Load(".")
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	gitManager, err := newGitManager()
+	if err != nil {
+		return err
+	}
+
+	exporter := export.NewExporter(".")
+	exporter.SetGitManager(commandContext(cmd), gitManager)
+	exporter.SetAssetDir(cfg.Attachments.Dir)
+
+	notes, err := exporter.SelectNotes(exportCategory, exportQuery, exportFull)
+	if err != nil {
+		return fmt.Errorf("failed to find notes: %w", err)
+	}
+
+	if len(notes) == 0 {
+		fmt.Println("No notes found to export")
+		return nil
+	}
+
+	title := exportTitleFor(exportTitle, exportCategory)
+
+	output := exportOutput
+	if output == "" && exportFormat == export.FormatEPUB {
+		output = title + ".epub"
+	}
+
+	if output == "" || output == "-" {
+		if err := exporter.Export(os.Stdout, exportFormat, title, notes); err != nil {
+			return fmt.Errorf("failed to export notes: %w", err)
+		}
+		return nil
+	}
+
+	file, err := os.Create(rootPath(output))
+	if err != nil {
+		return fmt.Errorf("failed to create export file: %w", err)
+	}
+
+	if err := exporter.Export(file, exportFormat, title, notes); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to export notes: %w", err)
+	}
+
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write export file: %w", err)
+	}
+
+	fmt.Printf("Exported %d notes to %s\n", len(notes), output)
+	return nil
+}
+
+func exportTitleFor(title, category string) string {
+	if title != "" {
+		return title
+	}
+
+	if category != "" {
+		return filepath.Base(filepath.Clean(category))
+	}
+
+	return "notes"
+}
@@ -0,0 +1,196 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"gitnote/internal/git"
+	"gitnote/internal/note"
+)
+
+var historyCmd = &cobra.Command{
+	Use:   "history <note>",
+	Short: "List the commits that changed a note",
+	Long:  "List the commits that touched a note, following renames. The note can be given as a path or a title",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runHistory,
+}
+
+var diffCmd = &cobra.Command{
+	Use:   "diff <note> [rev]",
+	Short: "Show a word-level diff of a note",
+	Long:  "Show a word-level diff between the note on disk and a revision (defaults to the last commit)",
+	Args:  cobra.RangeArgs(1, 2),
+	RunE:  runDiff,
+}
+
+var showCmd = &cobra.Command{
+	Use:   "show <note>@<rev>",
+	Short: "Print an old version of a note",
+	Long:  "Print the content of a note as it was at a revision, for example 'gitnote show ideas.md@HEAD~2'",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runShow,
+}
+
+func runHistory(cmd *cobra.Command, args []string) error {
+	ctx := commandContext(cmd)
+	gitManager, err := newGitManager()
+	if err != nil {
+		return err
+	}
+
+	notePath, err := resolveNote(args[0])
+	if err != nil {
+		return err
+	}
+
+	commits, err := gitManager.History(ctx, notePath)
+	if err != nil {
+		return err
+	}
+
+	if len(commits) == 0 {
+		fmt.Printf("%s has not been committed yet\n", notePath)
+		return nil
+	}
+
+	for _, commit := range commits {
+		line := fmt.Sprintf("%s  %s  %s  %s", commit.Hash[:7], commit.Date.Format("2006-01-02"), commit.Author, commit.Subject)
+		if commit.Path != notePath {
+			line += fmt.Sprintf("  (as %s)", commit.Path)
+		}
+		fmt.Println(line)
+	}
+
+	return nil
+}
+
+func runDiff(cmd *cobra.Command, args []string) error {
+	ctx := commandContext(cmd)
+	gitManager, err := newGitManager()
+	if err != nil {
+		return err
+	}
+
+	notePath, err := resolveNote(args[0])
+	if err != nil {
+		return err
+	}
+
+	rev := ""
+	if len(args) == 2 {
+		rev = args[1]
+	}
+
+	diff, err := gitManager.WordDiff(ctx, notePath, rev)
+	if err != nil {
+		return err
+	}
+
+	if diff == "" {
+		fmt.Println("No differences")
+		return nil
+	}
+
+	fmt.Print(diff)
+	return nil
+}
+
+func runShow(cmd *cobra.Command, args []string) error {
+	ctx := commandContext(cmd)
+	gitManager, err := newGitManager()
+	if err != nil {
+		return err
+	}
+
+	at := strings.LastIndex(args[0], "@")
+	if at <= 0 || at == len(args[0])-1 {
+		return fmt.Errorf("invalid argument %q: use <note>@<rev>", args[0])
+	}
+
+	notePath, err := resolveNote(args[0][:at])
+	if err != nil {
+		return err
+	}
+
+	content, err := showNoteAt(ctx, gitManager, notePath, args[0][at+1:])
+	if err != nil {
+		return err
+	}
+
+	fmt.Print(string(content))
+	return nil
+}
+
+func showNoteAt(ctx context.Context, gitManager *git.Manager, notePath, rev string) ([]byte, error) {
+	content, err := gitManager.Show(ctx, rev, notePath)
+	if err == nil {
+		return content, nil
+	}
+
+	hash, resolveErr := gitManager.ResolveRef(ctx, rev+"^{commit}")
+	if resolveErr != nil || hash == "" {
+		return nil, fmt.Errorf("unknown revision %s", rev)
+	}
+
+	commits, historyErr := gitManager.History(ctx, notePath)
+	if historyErr != nil {
+		return nil, historyErr
+	}
+
+	for _, commit := range commits {
+		if commit.Hash == hash && commit.Path != notePath {
+			return gitManager.Show(ctx, rev, commit.Path)
+		}
+	}
+
+	return nil, err
+}
+
+func resolveNote(arg string) (string, error) {
+	path := rootPath(arg)
+	if _, err := os.Stat(path); err == nil {
+		return path, nil
+	}
+
+	noteManager, err := newNoteManager()
+	if err != nil {
+		return "", err
+	}
+
+	notes, err := noteManager.FindNotes()
+	if err != nil {
+		return "", fmt.Errorf("failed to find notes: %w", err)
+	}
+
+	var matches []string
+	for _, n := range notes {
+		if strings.EqualFold(n.Title, arg) {
+			matches = append(matches, n.Path)
+		}
+	}
+
+	if len(matches) == 0 {
+		for _, n := range notes {
+			if strings.Contains(strings.ToLower(n.Title), strings.ToLower(arg)) {
+				matches = append(matches, n.Path)
+			}
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		if note.IsNotePath(path) {
+			return path, nil
+		}
+		return "", fmt.Errorf("no note found matching %q", arg)
+	case 1:
+		return matches[0], nil
+	default:
+		return "", fmt.Errorf("%q matches several notes: %s", arg, strings.Join(matches, ", "))
+	}
+}
@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/manifoldco/promptui"
+	"github.com/spf13/cobra"
+
+	"gitnote/internal/importer"
+)
+
+var (
+	importFrom     string
+	importCategory string
+	importDryRun   bool
+	importYes      bool
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import [path]",
+	Short: "Import notes from Obsidian, Joplin or a Markdown folder",
+	Long: `Import notes from an Obsidian vault, a Joplin JEX/RAW export or a plain Markdown folder.
+Notes are renamed to the yyyy-mm-dd title.md convention, folders and notebooks become
+categories and tags are moved into front matter. The planned changes are always shown
+before anything is written.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runImport,
+}
+
+func init() {
+	importCmd.Flags().StringVar(&importFrom, "from", "", "Source type (obsidian, joplin or folder); detected when omitted")
+	importCmd.Flags().StringVar(&importCategory, "category", "", "Category to import the notes into")
+	importCmd.Flags().BoolVar(&importDryRun, "dry-run", false, "Show what would be imported without writing any files")
+	importCmd.Flags().BoolVarP(&importYes, "yes", "y", false, "Import without asking for confirmation")
+}
+
+func runImport(cmd *cobra.Command, args []string) error {
+	source := rootPath(args[0])
+
+	from := importFrom
+	if from == "" {
+		detected, err := importer.DetectSource(source)
+		if err != nil {
+			return fmt.Errorf("failed to detect import source: %w", err)
+		}
+		from = detected
+	} else if !importer.IsValidSource(from) {
+		return fmt.Errorf("unsupported import source %q: use obsidian, joplin or folder", from)
+	}
+
+	noteImporter := importer.NewImporter(".")
+	noteImporter.SetCategory(importCategory)
+
+	items, err := noteImporter.Plan(source, from)
+	if err != nil {
+		return fmt.Errorf("failed to plan import: %w", err)
+	}
+
+	if len(items) == 0 {
+		fmt.Println("No notes found to import")
+		return nil
+	}
+
+	toImport := printImportPlan(from, items)
+
+	if importDryRun || toImport == 0 {
+		return nil
+	}
+
+	if !importYes {
+		prompt := promptui.Prompt{
+			Label:     fmt.Sprintf("Import %d notes", toImport),
+			IsConfirm: true,
+		}
+
+		if _, err := prompt.Run(); err != nil {
+			fmt.Println("Import cancelled")
+			return nil
+		}
+	}
+
+	imported, err := noteImporter.Apply(items)
+	if err != nil {
+		return fmt.Errorf("failed to import notes: %w", err)
+	}
+
+	fmt.Printf("Imported %d notes\n", imported)
+	return nil
+}
+
+func printImportPlan(from string, items []importer.Item) int {
+	toImport := 0
+
+	fmt.Printf("Importing from %s:\n", from)
+
+	for _, item := range items {
+		if item.Skipped != "" {
+			fmt.Printf("  skip   %s -> %s (%s)\n", item.Source, item.Destination, item.Skipped)
+			continue
+		}
+
+		toImport++
+		fmt.Printf("  import %s -> %s\n", item.Source, item.Destination)
+
+		if len(item.Changes) > 0 {
+			fmt.Printf("         %s\n", strings.Join(item.Changes, "; "))
+		}
+	}
+
+	fmt.Printf("\n%d to import, %d skipped\n", toImport, len(items)-toImport)
+	return toImport
+}
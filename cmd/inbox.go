@@ -0,0 +1,222 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/manifoldco/promptui"
+	"github.com/spf13/cobra"
+
+	"gitnote/internal/config"
+	"gitnote/internal/inbox"
+	"gitnote/internal/note"
+)
+
+const (
+	inboxActionNew    = "File into a new note"
+	inboxActionAppend = "Append to an existing note"
+	inboxActionSkip   = "Skip"
+	inboxActionDelete = "Delete"
+	inboxActionStop   = "Stop processing"
+)
+
+var inboxCmd = &cobra.Command{
+	Use:   "inbox",
+	Short: "List captured inbox items",
+	Long:  "List the items captured with 'gitnote capture' that have not been filed yet",
+	Args:  cobra.NoArgs,
+	RunE:  runInbox,
+}
+
+var inboxProcessCmd = &cobra.Command{
+	Use:   "process",
+	Short: "File inbox items into notes",
+	Long:  "Walk through inbox items and file each one into a new or existing note in a chosen category",
+	Args:  cobra.NoArgs,
+	RunE:  runInboxProcess,
+}
+
+func init() {
+	inboxCmd.AddCommand(inboxProcessCmd)
+}
+
+func newInboxManager() (*inbox.Manager, error) {
+	cfg, err := config.Load(".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	return inbox.NewManager(".", cfg.Inbox), nil
+}
+
+func runInbox(cmd *cobra.Command, args []string) error {
+	inboxManager, err := newInboxManager()
+	if err != nil {
+		return err
+	}
+
+	items, err := inboxManager.Items()
+	if err != nil {
+		return fmt.Errorf("failed to read inbox: %w", err)
+	}
+
+	if len(items) == 0 {
+		fmt.Println("Inbox is empty")
+		return nil
+	}
+
+	for _, item := range items {
+		fmt.Println(formatInboxItem(item))
+	}
+
+	return nil
+}
+
+func runInboxProcess(cmd *cobra.Command, args []string) error {
+	inboxManager, err := newInboxManager()
+	if err != nil {
+		return err
+	}
+
+	items, err := inboxManager.Items()
+	if err != nil {
+		return fmt.Errorf("failed to read inbox: %w", err)
+	}
+
+	if len(items) == 0 {
+		fmt.Println("Inbox is empty")
+		return nil
+	}
+
+	noteManager, err := newNoteManager()
+	if err != nil {
+		return err
+	}
+
+	var processed []inbox.Item
+
+	for i, item := range items {
+		fmt.Printf("\n[%d/%d] %s\n", i+1, len(items), formatInboxItem(item))
+
+		prompt := promptui.Select{
+			Label: "What should happen to this item",
+			Items: []string{inboxActionNew, inboxActionAppend, inboxActionSkip, inboxActionDelete, inboxActionStop},
+		}
+
+		_, action, err := prompt.Run()
+		if err != nil {
+			break
+		}
+
+		if action == inboxActionStop {
+			break
+		}
+
+		switch action {
+		case inboxActionNew:
+			notePath, err := fileIntoNewNote(noteManager, item)
+			if err != nil {
+				return finishInboxProcessing(inboxManager, processed, err)
+			}
+			fmt.Printf("Filed into %s\n", notePath)
+			processed = append(processed, item)
+		case inboxActionAppend:
+			notePath, err := fileIntoExistingNote(noteManager, item)
+			if err != nil {
+				return finishInboxProcessing(inboxManager, processed, err)
+			}
+			if notePath != "" {
+				fmt.Printf("Appended to %s\n", notePath)
+				processed = append(processed, item)
+			}
+		case inboxActionDelete:
+			processed = append(processed, item)
+		}
+	}
+
+	return finishInboxProcessing(inboxManager, processed, nil)
+}
+
+func finishInboxProcessing(inboxManager *inbox.Manager, processed []inbox.Item, processErr error) error {
+	if err := inboxManager.Remove(processed); err != nil {
+		return fmt.Errorf("failed to update inbox: %w", err)
+	}
+
+	if processErr != nil {
+		return processErr
+	}
+
+	fmt.Printf("\nProcessed %d inbox items\n", len(processed))
+	return nil
+}
+
+func fileIntoNewNote(noteManager *note.Manager, item inbox.Item) (string, error) {
+	categoryPath, err := selectCategory(noteManager)
+	if err != nil {
+		return "", fmt.Errorf("failed to select category: %w", err)
+	}
+
+	title, err := promptForTitle()
+	if err != nil {
+		return "", fmt.Errorf("failed to get note title: %w", err)
+	}
+
+	notePath, err := noteManager.CreateNote(categoryPath, title)
+	if err != nil {
+		return "", fmt.Errorf("failed to create note: %w", err)
+	}
+
+	if err := noteManager.AppendToNote(notePath, "\n"+item.Text); err != nil {
+		return "", fmt.Errorf("failed to file inbox item: %w", err)
+	}
+
+	return notePath, nil
+}
+
+func fileIntoExistingNote(noteManager *note.Manager, item inbox.Item) (string, error) {
+	categoryPath, err := selectCategory(noteManager)
+	if err != nil {
+		return "", fmt.Errorf("failed to select category: %w", err)
+	}
+
+	notes, err := noteManager.FindNotesInCategory(categoryPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to find notes: %w", err)
+	}
+
+	if len(notes) == 0 {
+		fmt.Println("No notes in this category, item skipped")
+		return "", nil
+	}
+
+	paths := make([]string, 0, len(notes))
+	for _, n := range notes {
+		paths = append(paths, n.Path)
+	}
+
+	prompt := promptui.Select{
+		Label: "Select note",
+		Items: paths,
+	}
+
+	_, notePath, err := prompt.Run()
+	if err != nil {
+		return "", fmt.Errorf("failed to select note: %w", err)
+	}
+
+	if err := noteManager.AppendToNote(notePath, "\n- "+strings.ReplaceAll(item.Text, "\n", "\n  ")); err != nil {
+		return "", fmt.Errorf("failed to file inbox item: %w", err)
+	}
+
+	return notePath, nil
+}
+
+func formatInboxItem(item inbox.Item) string {
+	text := strings.ReplaceAll(item.Text, "\n", " ")
+
+	if item.Time.IsZero() {
+		return text
+	}
+
+	return fmt.Sprintf("%s  %s", item.Time.Format(inbox.TimeFormat), text)
+}
@@ -4,8 +4,6 @@ import (
 	"fmt"
 
 	"github.com/spf13/cobra"
-
-	"gitnote/internal/index"
 )
 
 var indexCmd = &cobra.Command{
@@ -16,7 +14,10 @@ var indexCmd = &cobra.Command{
 }
 
 func runIndex(cmd *cobra.Command, args []string) error {
-	generator := index.NewGenerator(".")
+	generator, err := newIndexGenerator(".")
+	if err != nil {
+		return err
+	}
 	
 	upToDate, err := generator.IsReadmeUpToDate()
 	if err != nil {
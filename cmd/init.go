@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"gitnote/internal/git"
+	"gitnote/internal/index"
+	"gitnote/internal/scaffold"
+)
+
+var (
+	initClone      string
+	initCategories []string
+	initNoHooks    bool
+)
+
+var initCmd = &cobra.Command{
+	Use:               "init [dir]",
+	Short:             "Set up a new notes repository",
+	Long:              "Initialise (or clone) a git repository and add a starter .gitnote.yaml, .gitignore, journal template, readme.md index and pre-commit hook. Files that already exist are left alone, so init is safe to re-run",
+	Args:              cobra.MaximumNArgs(1),
+	PersistentPreRunE: applyGlobalFlags,
+	RunE:              runInit,
+}
+
+func init() {
+	initCmd.Flags().StringVar(&initClone, "clone", "", "Clone this remote instead of running git init")
+	initCmd.Flags().StringSliceVarP(&initCategories, "category", "c", nil, "Create a starter category directory (repeatable)")
+	initCmd.Flags().BoolVar(&initNoHooks, "no-hooks", false, "Do not install the pre-commit hook")
+}
+
+func runInit(cmd *cobra.Command, args []string) error {
+	ctx := commandContext(cmd)
+
+	dir := "."
+	if len(args) > 0 {
+		dir = args[0]
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+
+	gitManager := git.NewManager(dir)
+
+	if initClone != "" {
+		if err := gitManager.Clone(ctx, initClone); err != nil {
+			return explainGitError(err)
+		}
+		fmt.Printf("Cloned %s\n", initClone)
+	} else {
+		isRoot, err := isRepoRoot(ctx, gitManager, dir)
+		if err != nil {
+			return err
+		}
+		if !isRoot {
+			if err := gitManager.Init(ctx); err != nil {
+				return err
+			}
+			fmt.Println("Initialised git repository")
+		}
+	}
+
+	created, err := scaffold.NewScaffolder(dir).Create(initCategories)
+	if err != nil {
+		return err
+	}
+	for _, path := range created {
+		fmt.Printf("Created %s\n", path)
+	}
+
+	generator, err := newIndexGenerator(dir)
+	if err != nil {
+		return err
+	}
+	upToDate, err := generator.IsReadmeUpToDate()
+	if err != nil {
+		return fmt.Errorf("failed to check readme status: %w", err)
+	}
+	if !upToDate {
+		if err := generator.GenerateReadme(); err != nil {
+			return fmt.Errorf("failed to generate readme: %w", err)
+		}
+		created = appendMissing(created, index.ReadmeFile)
+	}
+
+	if !initNoHooks {
+		installed, err := gitManager.InstallHook(ctx, "pre-commit", scaffold.PreCommitHook)
+		if err != nil {
+			return err
+		}
+		if installed {
+			fmt.Println("Installed pre-commit hook")
+		}
+	}
+
+	if len(created) == 0 {
+		fmt.Println("Notes repository is already set up")
+		return nil
+	}
+
+	if err := gitManager.AddAll(ctx, created); err != nil {
+		return err
+	}
+	if err := gitManager.CommitPaths(ctx, "Set up gitnote", created); err != nil {
+		return err
+	}
+
+	fmt.Printf("Notes repository ready in %s\n", dir)
+	return nil
+}
+
+func isRepoRoot(ctx context.Context, gitManager *git.Manager, dir string) (bool, error) {
+	topLevel, err := gitManager.TopLevel(ctx)
+	if err != nil || topLevel == "" {
+		return false, err
+	}
+
+	root, err := realPath(topLevel)
+	if err != nil {
+		return false, err
+	}
+	target, err := realPath(dir)
+	if err != nil {
+		return false, err
+	}
+
+	return root == target, nil
+}
+
+func appendMissing(paths []string, path string) []string {
+	for _, existing := range paths {
+		if existing == path {
+			return paths
+		}
+	}
+	return append(paths, path)
+}
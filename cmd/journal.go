@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"gitnote/internal/config"
+	"gitnote/internal/journal"
+)
+
+var (
+	journalAppend string
+	journalNoEdit bool
+)
+
+var todayCmd = &cobra.Command{
+	Use:   "today",
+	Short: "Open or create today's journal note",
+	Long:  "Open today's journal note in your editor, creating it from the journal template if needed",
+	Args:  cobra.NoArgs,
+	RunE:  runToday,
+}
+
+var journalCmd = &cobra.Command{
+	Use:   "journal [date]",
+	Short: "Open or create a journal note for a date",
+	Long:  "Open the journal note for a date (yyyy-mm-dd, defaults to today) in your editor, creating it from the journal template if needed",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  runJournal,
+}
+
+func init() {
+	for _, command := range []*cobra.Command{todayCmd, journalCmd} {
+		command.Flags().StringVarP(&journalAppend, "append", "a", "", "Append text to the journal note without opening an editor")
+		command.Flags().BoolVar(&journalNoEdit, "no-edit", false, "Create the journal note without opening an editor")
+	}
+}
+
+func runToday(cmd *cobra.Command, args []string) error {
+	return openJournal(time.Now())
+}
+
+func runJournal(cmd *cobra.Command, args []string) error {
+	date := time.Now()
+
+	if len(args) == 1 {
+		parsed, err := time.ParseInLocation(journal.DateFormat, args[0], time.Local)
+		if err != nil {
+			return fmt.Errorf("invalid date %q: use yyyy-mm-dd", args[0])
+		}
+		date = parsed
+	}
+
+	return openJournal(date)
+}
+
+func openJournal(date time.Time) error {
+	cfg, err := config.Load(".")
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	journalManager := journal.NewManager(".", cfg.Journal)
+
+	if journalAppend != "" {
+		notePath, err := journalManager.Append(date, journalAppend)
+		if err != nil {
+			return fmt.Errorf("failed to append to journal: %w", err)
+		}
+
+		fmt.Printf("Appended to %s\n", notePath)
+		return nil
+	}
+
+	notePath, created, err := journalManager.Open(date)
+	if err != nil {
+		return fmt.Errorf("failed to open journal: %w", err)
+	}
+
+	if created {
+		fmt.Printf("Created note: %s\n", notePath)
+	}
+
+	if journalNoEdit {
+		if !created {
+			fmt.Println(notePath)
+		}
+		return nil
+	}
+
+	return openInEditor(notePath)
+}
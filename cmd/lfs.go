@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"gitnote/internal/config"
+	"gitnote/internal/git"
+)
+
+const gitAttributesFile = ".gitattributes"
+
+func checkLargeFiles(ctx context.Context, gitManager *git.Manager, cfg config.LFSConfig, paths []string) ([]string, error) {
+	if cfg.Threshold <= 0 || len(paths) == 0 {
+		return nil, nil
+	}
+
+	status, err := gitManager.GetStatus(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get git status: %w", err)
+	}
+
+	included := make(map[string]bool)
+	for _, path := range paths {
+		included[path] = true
+	}
+
+	var entries []git.StatusEntry
+	for _, entry := range status {
+		if entry.IsStaged() && included[entry.Path] {
+			entries = append(entries, entry)
+		}
+	}
+
+	large, err := gitManager.LargeFiles(ctx, entries, int64(cfg.Threshold))
+	if err != nil {
+		return nil, fmt.Errorf("failed to check file sizes: %w", err)
+	}
+	if len(large) == 0 {
+		return nil, nil
+	}
+
+	var total config.Size
+	for _, file := range large {
+		fmt.Printf("Warning: %s is a %s binary file\n", file.Path, config.Size(file.Size))
+		total += config.Size(file.Size)
+	}
+
+	var tracked []string
+	if cfg.Track {
+		if gitManager.LFSAvailable(ctx) {
+			if tracked, err = trackWithLFS(ctx, gitManager, large); err != nil {
+				return nil, err
+			}
+		} else {
+			fmt.Println("Git LFS is not available (it needs git-lfs and the exec backend), so these will be committed as regular files")
+		}
+	} else {
+		fmt.Println("Set lfs.track: true in .gitnote.yaml to store large attachments with Git LFS")
+	}
+
+	repoSize, err := gitManager.RepositorySize(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if tracked != nil {
+		fmt.Printf("This stores about %s in Git LFS instead of the repository (currently %s)\n", total, config.Size(repoSize))
+	} else {
+		fmt.Printf("This adds about %s to the repository (currently %s)\n", total, config.Size(repoSize))
+	}
+
+	return tracked, nil
+}
+
+func trackWithLFS(ctx context.Context, gitManager *git.Manager, large []git.LargeFile) ([]string, error) {
+	seen := make(map[string]bool)
+	var paths []string
+
+	for _, file := range large {
+		paths = append(paths, file.Path)
+
+		pattern := filepath.ToSlash(file.Path)
+		ext := filepath.Ext(file.Path)
+		if ext != "" {
+			pattern = "*" + ext
+		}
+		if seen[pattern] {
+			continue
+		}
+		seen[pattern] = true
+
+		if err := gitManager.LFSTrack(ctx, pattern); err != nil {
+			return nil, err
+		}
+		if ext != "" {
+			fmt.Printf("Tracking %s with Git LFS: every %s file in the repository will now be stored in LFS, not just %s\n", pattern, ext, file.Path)
+		} else {
+			fmt.Printf("Tracking %s with Git LFS\n", pattern)
+		}
+	}
+
+	if err := gitManager.AddAll(ctx, []string{gitAttributesFile}); err != nil {
+		return nil, fmt.Errorf("failed to add %s: %w", gitAttributesFile, err)
+	}
+	if err := gitManager.Renormalize(ctx, paths); err != nil {
+		return nil, err
+	}
+
+	return []string{gitAttributesFile}, nil
+}
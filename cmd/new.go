@@ -2,12 +2,14 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
 	"strings"
 
 	"github.com/manifoldco/promptui"
 	"github.com/spf13/cobra"
 
+	"gitnote/internal/config"
 	"gitnote/internal/note"
 )
 
@@ -19,7 +21,13 @@ var newCmd = &cobra.Command{
 }
 
 func runNew(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load(".")
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
 	noteManager := note.NewManager(".")
+	noteManager.SetAssetDir(cfg.Attachments.Dir)
 
 	categoryPath, err := selectCategory(noteManager)
 	if err != nil {
@@ -31,9 +39,9 @@ func runNew(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to get note title: %w", err)
 	}
 
-	notePath, err := noteManager.CreateNote(categoryPath, title)
+	notePath, err := createNote(noteManager, cfg, categoryPath, title)
 	if err != nil {
-		return fmt.Errorf("failed to create note: %w", err)
+		return err
 	}
 
 	fmt.Printf("Created note: %s\n", notePath)
@@ -141,3 +149,28 @@ func promptForTitle() (string, error) {
 
 	return strings.TrimSpace(result), nil
 }
+
+func createNote(noteManager *note.Manager, cfg *config.Config, categoryPath, title string) (string, error) {
+	notePath, err := noteManager.CreateNote(categoryPath, title)
+	if err != nil {
+		return "", fmt.Errorf("failed to create note: %w", err)
+	}
+
+	if !isEncryptedCategory(cfg, notePath) {
+		return notePath, nil
+	}
+
+	cipher, err := newCipher(true)
+	if err != nil {
+		os.Remove(notePath)
+		return "", err
+	}
+
+	encryptedPath, err := encryptNote(cipher, notePath)
+	if err != nil {
+		os.Remove(notePath)
+		return "", fmt.Errorf("failed to encrypt note: %w", err)
+	}
+
+	return encryptedPath, nil
+}
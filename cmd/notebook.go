@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"gitnote/internal/notebook"
+)
+
+var notebookName string
+
+var notebookCmd = &cobra.Command{
+	Use:               "notebook",
+	Short:             "Manage named notebooks",
+	Long:              "Register note repositories under a name so any command can target them with --notebook. The default notebook is used when gitnote runs outside a notes repository",
+	PersistentPreRunE: applyGlobalFlags,
+}
+
+var notebookAddCmd = &cobra.Command{
+	Use:   "add <name> <path>",
+	Short: "Register a notebook",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runNotebookAdd,
+}
+
+var notebookListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List notebooks, marking the default",
+	Args:  cobra.NoArgs,
+	RunE:  runNotebookList,
+}
+
+var notebookRemoveCmd = &cobra.Command{
+	Use:   "remove <name>",
+	Short: "Forget a notebook (its notes are left untouched)",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runNotebookRemove,
+}
+
+var notebookDefaultCmd = &cobra.Command{
+	Use:   "default <name>",
+	Short: "Set the default notebook",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runNotebookDefault,
+}
+
+func init() {
+	notebookCmd.AddCommand(notebookAddCmd)
+	notebookCmd.AddCommand(notebookListCmd)
+	notebookCmd.AddCommand(notebookRemoveCmd)
+	notebookCmd.AddCommand(notebookDefaultCmd)
+}
+
+func loadNotebooks() (*notebook.Registry, error) {
+	path, err := notebook.DefaultPath()
+	if err != nil {
+		return nil, err
+	}
+	return notebook.Load(path)
+}
+
+func runNotebookAdd(cmd *cobra.Command, args []string) error {
+	registry, err := loadNotebooks()
+	if err != nil {
+		return err
+	}
+
+	added, err := registry.Add(args[0], args[1])
+	if err != nil {
+		return err
+	}
+
+	if err := registry.Save(); err != nil {
+		return err
+	}
+
+	fmt.Printf("Added notebook %s at %s\n", added.Name, added.Path)
+	if registry.Default == added.Name {
+		fmt.Printf("%s is the default notebook\n", added.Name)
+	}
+	return nil
+}
+
+func runNotebookList(cmd *cobra.Command, args []string) error {
+	registry, err := loadNotebooks()
+	if err != nil {
+		return err
+	}
+
+	if len(registry.Notebooks) == 0 {
+		fmt.Println("No notebooks configured. Add one with 'gitnote notebook add <name> <path>'")
+		return nil
+	}
+
+	for _, nb := range registry.Notebooks {
+		marker := " "
+		if nb.Name == registry.Default {
+			marker = "*"
+		}
+		fmt.Printf("%s %s  %s\n", marker, nb.Name, nb.Path)
+	}
+
+	return nil
+}
+
+func runNotebookRemove(cmd *cobra.Command, args []string) error {
+	registry, err := loadNotebooks()
+	if err != nil {
+		return err
+	}
+
+	if err := registry.Remove(args[0]); err != nil {
+		return err
+	}
+
+	if err := registry.Save(); err != nil {
+		return err
+	}
+
+	fmt.Printf("Removed notebook %s\n", args[0])
+	return nil
+}
+
+func runNotebookDefault(cmd *cobra.Command, args []string) error {
+	registry, err := loadNotebooks()
+	if err != nil {
+		return err
+	}
+
+	if err := registry.SetDefault(args[0]); err != nil {
+		return err
+	}
+
+	if err := registry.Save(); err != nil {
+		return err
+	}
+
+	fmt.Printf("%s is now the default notebook\n", args[0])
+	return nil
+}
@@ -1,64 +1,247 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/manifoldco/promptui"
 	"github.com/spf13/cobra"
 
+	"gitnote/internal/config"
 	"gitnote/internal/git"
+	"gitnote/internal/index"
+	"gitnote/internal/note"
 )
 
 var pullCmd = &cobra.Command{
 	Use:   "pull",
 	Short: "Pull changes from remote repository",
-	Long:  "Pull changes from remote repository with merge conflict handling",
+	Long:  "Pull changes from remote repository with merge conflict handling. Local changes are stashed during the pull and restored afterwards, and the previous state can be restored with 'gitnote undo'",
 	RunE:  runPull,
 }
 
+var pullKindOrder = map[string]int{"new": 0, "updated": 1, "renamed": 2, "deleted": 3}
+
 func runPull(cmd *cobra.Command, args []string) error {
-	gitManager := git.NewManager(".")
+	ctx := commandContext(cmd)
+	gitManager, err := newGitManager()
+	if err != nil {
+		return err
+	}
 	
-	if !gitManager.IsGitRepo() {
+	if !gitManager.IsGitRepo(ctx) {
 		return fmt.Errorf("current directory is not a git repository")
 	}
 	
-	output, err := gitManager.Pull()
+	cfg, err := config.Load(".")
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	
+	if err := gitManager.SavePrePull(ctx); err != nil {
+		return fmt.Errorf("failed to record pre-pull state: %w", err)
+	}
+	
+	before, err := gitManager.Head(ctx)
+	if err != nil {
+		return err
+	}
+	
+	output, err := gitManager.PullWith(ctx, cfg.Sync.Strategy)
 	if err != nil {
-		hasConflicts, conflictErr := gitManager.HasMergeConflicts()
+		hasConflicts, conflictErr := gitManager.HasMergeConflicts(ctx)
 		if conflictErr != nil {
 			return fmt.Errorf("failed to check merge conflicts: %w", conflictErr)
 		}
 		
 		if hasConflicts {
+			resolved, err := resolveIndexConflicts(ctx, gitManager)
+			if err != nil {
+				return err
+			}
+			
+			if resolved {
+				fmt.Println("Pull completed successfully: regenerated readme.md to resolve index conflicts")
+				return printPullSummary(ctx, gitManager, before)
+			}
+			
 			fmt.Printf("Pull failed with merge conflicts:\n%s\n", output)
-			return handleMergeConflicts(gitManager)
+			return handleMergeConflicts(ctx, gitManager)
 		}
 		
-		return fmt.Errorf("pull failed: %w\nOutput: %s", err, output)
+		return explainGitError(fmt.Errorf("pull failed: %w\nOutput: %s", err, output))
 	}
 	
-	fmt.Printf("Pull completed successfully:\n%s", output)
+	fmt.Println("Pull completed successfully")
+	return printPullSummary(ctx, gitManager, before)
+}
+
+func printPullSummary(ctx context.Context, gitManager *git.Manager, before string) error {
+	after, err := gitManager.Head(ctx)
+	if err != nil {
+		return err
+	}
 	
-	if strings.Contains(output, "files changed") {
-		status, err := gitManager.GetStatus()
-		if err == nil && len(status) == 0 {
-			fmt.Println("\nRepository is up to date.")
-		}
+	if before == "" || before == after {
+		fmt.Println("Repository is up to date.")
+		return nil
 	}
 	
+	summary, err := gitManager.Changes(ctx, before, after)
+	if err != nil {
+		return err
+	}
+	
+	fmt.Print(formatPullSummary(summary))
 	return nil
 }
 
-func handleMergeConflicts(gitManager *git.Manager) error {
-	fmt.Println("\nMerge conflicts detected. Choose an option:")
+func formatPullSummary(summary git.ChangeSummary) string {
+	type noteLine struct {
+		kind  string
+		title string
+	}
+	
+	byCategory := make(map[string][]noteLine)
+	
+	for _, change := range summary.Changes {
+		if !note.IsNotePath(change.Path) || index.IsGenerated(change.Path) {
+			continue
+		}
+		
+		line := noteLine{title: noteTitle(change.Path)}
+		switch change.Status {
+		case 'A', 'C':
+			line.kind = "new"
+		case 'D':
+			line.kind = "deleted"
+		case 'R':
+			line.kind = "renamed"
+			line.title = fmt.Sprintf("%s -> %s", noteTitle(change.OrigPath), line.title)
+		default:
+			line.kind = "updated"
+		}
+		
+		category := filepath.Dir(change.Path)
+		if category == "." {
+			category = "root"
+		}
+		byCategory[category] = append(byCategory[category], line)
+	}
+	
+	if len(byCategory) == 0 {
+		return "No notes changed.\n"
+	}
+	
+	var categories []string
+	for category := range byCategory {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+	
+	var builder strings.Builder
+	
+	if len(summary.Authors) > 0 {
+		builder.WriteString(fmt.Sprintf("Changes by %s:\n", strings.Join(summary.Authors, ", ")))
+	}
+	
+	for _, category := range categories {
+		builder.WriteString(fmt.Sprintf("\n%s\n", filepath.ToSlash(category)))
+		
+		lines := byCategory[category]
+		sort.SliceStable(lines, func(i, j int) bool {
+			return pullKindOrder[lines[i].kind] < pullKindOrder[lines[j].kind]
+		})
+		
+		for _, line := range lines {
+			builder.WriteString(fmt.Sprintf("  %-8s %s\n", line.kind+":", line.title))
+		}
+	}
+	
+	return builder.String()
+}
+
+func resolveIndexConflicts(ctx context.Context, gitManager *git.Manager) (bool, error) {
+	for {
+		status, err := gitManager.GetStatus(ctx)
+		if err != nil {
+			return false, fmt.Errorf("failed to get git status: %w", err)
+		}
+		
+		var generated []string
+		conflicts := 0
+		for _, entry := range status {
+			if !entry.IsConflicted() {
+				continue
+			}
+			conflicts++
+			if index.IsGenerated(entry.Path) {
+				generated = append(generated, entry.Path)
+			}
+		}
+		
+		if conflicts == 0 {
+			return true, nil
+		}
+		
+		if len(generated) > 0 {
+			generator, err := newIndexGenerator(".")
+			if err != nil {
+				return false, err
+			}
+			
+			if err := generator.GenerateReadme(); err != nil {
+				return false, fmt.Errorf("failed to regenerate readme: %w", err)
+			}
+			
+			if err := gitManager.AddAll(ctx, generated); err != nil {
+				return false, fmt.Errorf("failed to stage readme: %w", err)
+			}
+		}
+		
+		if len(generated) < conflicts {
+			return false, nil
+		}
+		
+		if err := gitManager.Continue(ctx); err != nil {
+			hasConflicts, conflictErr := gitManager.HasMergeConflicts(ctx)
+			if conflictErr != nil || !hasConflicts {
+				return false, err
+			}
+		}
+		
+		state, err := gitManager.State(ctx)
+		if err != nil {
+			return false, err
+		}
+		
+		if state == git.StateNone {
+			return true, nil
+		}
+	}
+}
+
+func handleMergeConflicts(ctx context.Context, gitManager *git.Manager) error {
+	conflicted, err := conflictedPaths(ctx, gitManager)
+	if err != nil {
+		return err
+	}
+	
+	fmt.Println("\nMerge conflicts detected in:")
+	for _, path := range conflicted {
+		fmt.Printf("  %s\n", path)
+	}
+	fmt.Println()
 	
 	prompt := promptui.Select{
 		Label: "Select action",
 		Items: []string{
+			"Resolve each note now",
 			"Manually fix conflicts (you will need to resolve conflicts and commit)",
-			"Roll back (discard changes and reset to HEAD)",
+			"Roll back (abort the pull and restore your local changes)",
 		},
 	}
 	
@@ -68,11 +251,22 @@ func handleMergeConflicts(gitManager *git.Manager) error {
 	}
 	
 	switch result {
-	case "Roll back (discard changes and reset to HEAD)":
-		if err := gitManager.Reset(); err != nil {
-			return fmt.Errorf("failed to reset repository: %w", err)
+	case "Resolve each note now":
+		resolved, err := resolveConflictsInteractively(ctx, gitManager)
+		if err != nil {
+			return err
+		}
+		if resolved {
+			fmt.Println("All conflicts resolved and the merge has been completed")
+			return nil
+		}
+		fmt.Println("Some conflicts are still unresolved. Run 'gitnote pull' again or resolve them manually and commit")
+		return nil
+	case "Roll back (abort the pull and restore your local changes)":
+		if err := gitManager.Abort(ctx); err != nil {
+			return fmt.Errorf("failed to roll back pull: %w", err)
 		}
-		fmt.Println("Repository has been reset to previous state")
+		fmt.Println("Pull has been rolled back to the previous state")
 		return nil
 	default:
 		fmt.Println("Please resolve the merge conflicts manually and then run 'git commit' to complete the merge")
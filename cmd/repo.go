@@ -0,0 +1,239 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"gitnote/internal/config"
+	"gitnote/internal/git"
+	"gitnote/internal/index"
+	"gitnote/internal/note"
+)
+
+const repoDirEnv = "GITNOTE_DIR"
+
+var (
+	repoDir        string
+	nonInteractive bool
+	invocationDir  = "."
+)
+
+func applyGlobalFlags(cmd *cobra.Command, args []string) error {
+	if nonInteractive {
+		git.SetTerminalPrompts(false)
+	}
+	return nil
+}
+
+func enterRepoRoot(cmd *cobra.Command, args []string) error {
+	if err := applyGlobalFlags(cmd, args); err != nil {
+		return err
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	root := repoDir
+	if root == "" && notebookName != "" {
+		root, err = notebookPath(notebookName)
+		if err != nil {
+			return err
+		}
+	}
+	if root == "" {
+		root = os.Getenv(repoDirEnv)
+	}
+
+	if root == "" {
+		root, err = findRepoRoot(commandContext(cmd), cwd)
+		if err != nil {
+			return err
+		}
+	}
+	if root == "" {
+		root, err = notebookPath("")
+		if err != nil {
+			return err
+		}
+	}
+
+	if root == "" {
+		root = cwd
+	} else if info, err := os.Stat(root); err != nil || !info.IsDir() {
+		return fmt.Errorf("notes repository %s is not a directory", root)
+	}
+
+	root, err = realPath(root)
+	if err != nil {
+		return err
+	}
+	cwd, err = realPath(cwd)
+	if err != nil {
+		return err
+	}
+
+	if invocationDir, err = filepath.Rel(root, cwd); err != nil {
+		invocationDir = "."
+	}
+
+	if err := os.Chdir(root); err != nil {
+		return fmt.Errorf("failed to enter %s: %w", root, err)
+	}
+
+	return nil
+}
+
+func findRepoRoot(ctx context.Context, start string) (string, error) {
+	dir, err := realPath(start)
+	if err != nil {
+		return "", err
+	}
+
+	top, err := git.NewManager(dir).TopLevel(ctx)
+	if err != nil {
+		top = ""
+	}
+	if top != "" {
+		if top, err = realPath(top); err != nil {
+			return "", err
+		}
+	}
+
+	for current := dir; ; current = filepath.Dir(current) {
+		for _, name := range []string{config.MarkerFile, config.FileName} {
+			if _, err := os.Stat(filepath.Join(current, name)); err == nil {
+				return current, nil
+			}
+		}
+
+		if current == top || filepath.Dir(current) == current {
+			break
+		}
+	}
+
+	return top, nil
+}
+
+func notebookPath(name string) (string, error) {
+	registry, err := loadNotebooks()
+	if err != nil {
+		if name == "" && errors.Is(err, os.ErrNotExist) {
+			return "", nil
+		}
+		return "", err
+	}
+
+	if name == "" {
+		nb, _ := registry.DefaultNotebook()
+		return nb.Path, nil
+	}
+
+	nb, ok := registry.Get(name)
+	if !ok {
+		return "", fmt.Errorf("no notebook named %q: see 'gitnote notebook list'", name)
+	}
+
+	return nb.Path, nil
+}
+
+func realPath(path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", path, err)
+	}
+
+	resolved, err := filepath.EvalSymlinks(abs)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", path, err)
+	}
+
+	return resolved, nil
+}
+
+func rootPath(path string) string {
+	if filepath.IsAbs(path) {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return path
+		}
+		if rel, err := filepath.Rel(cwd, path); err == nil {
+			return rel
+		}
+		return path
+	}
+
+	return filepath.Join(invocationDir, path)
+}
+
+func rootPaths(paths []string) []string {
+	resolved := make([]string, len(paths))
+	for i, path := range paths {
+		resolved[i] = rootPath(path)
+	}
+	return resolved
+}
+
+func newNoteManager() (*note.Manager, error) {
+	cfg, err := config.Load(".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	noteManager := note.NewManager(".")
+	noteManager.SetAssetDir(cfg.Attachments.Dir)
+	return noteManager, nil
+}
+
+func newIndexGenerator(dir string) (*index.Generator, error) {
+	cfg, err := config.Load(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	generator := index.NewGenerator(dir)
+	generator.SetAssetDir(cfg.Attachments.Dir)
+	return generator, nil
+}
+
+func newGitManager() (*git.Manager, error) {
+	cfg, err := config.Load(".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	backend, err := git.NewBackend(cfg.Git.Backend, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	gitManager := git.NewManager(".")
+	gitManager.SetBackend(backend)
+	gitManager.SetTimeout(cfg.Git.Timeout)
+	return gitManager, nil
+}
+
+func commandContext(cmd *cobra.Command) context.Context {
+	if cmd == nil || cmd.Context() == nil {
+		return context.Background()
+	}
+	return cmd.Context()
+}
+
+func explainGitError(err error) error {
+	switch {
+	case errors.Is(err, git.ErrAuthRequired):
+		return fmt.Errorf("%w\nThe remote asked for credentials: set up a credential helper or an SSH key and try again", err)
+	case errors.Is(err, git.ErrNoUpstream):
+		return fmt.Errorf("%w\nThe current branch has no upstream: run 'git push -u origin <branch>' once", err)
+	case errors.Is(err, git.ErrTimeout):
+		return fmt.Errorf("%w\nRaise git.timeout in %s if the remote is slow", err, config.FileName)
+	}
+	return err
+}
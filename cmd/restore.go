@@ -0,0 +1,190 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"gitnote/internal/git"
+	"gitnote/internal/note"
+)
+
+var (
+	restoreRev   string
+	restoreForce bool
+	trashCommits int
+)
+
+var restoreCmd = &cobra.Command{
+	Use:   "restore <note>",
+	Short: "Restore a deleted or old version of a note",
+	Long:  "Write a note back to the working tree from the last commit where it existed, or from --rev. The note can be given as a path or, for deleted notes, a title",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runRestore,
+}
+
+var trashCmd = &cobra.Command{
+	Use:   "trash",
+	Short: "List recently deleted notes",
+	Long:  "List notes deleted in the last commits that have not been restored since",
+	Args:  cobra.NoArgs,
+	RunE:  runTrash,
+}
+
+func init() {
+	restoreCmd.Flags().StringVar(&restoreRev, "rev", "", "Restore the note as it was at this revision")
+	restoreCmd.Flags().BoolVarP(&restoreForce, "force", "f", false, "Overwrite the note even if it has uncommitted changes")
+	trashCmd.Flags().IntVarP(&trashCommits, "commits", "n", 50, "Number of recent commits to look through")
+}
+
+func runRestore(cmd *cobra.Command, args []string) error {
+	ctx := commandContext(cmd)
+	gitManager, err := newGitManager()
+	if err != nil {
+		return err
+	}
+
+	if !gitManager.IsGitRepo(ctx) {
+		return fmt.Errorf("current directory is not a git repository")
+	}
+
+	notePath, err := resolveRestorePath(ctx, gitManager, args[0])
+	if err != nil {
+		return err
+	}
+
+	rev, label := restoreRev, restoreRev
+	if rev == "" {
+		rev, err = gitManager.LastVersion(ctx, notePath)
+		if err != nil {
+			return err
+		}
+		label = rev[:7] + strings.TrimLeft(rev, "0123456789abcdef")
+	}
+
+	content, err := showNoteAt(ctx, gitManager, notePath, rev)
+	if err != nil {
+		return err
+	}
+
+	if !restoreForce {
+		if err := checkRestoreTarget(ctx, gitManager, notePath, content); err != nil {
+			return err
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(notePath), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	if err := os.WriteFile(notePath, content, 0644); err != nil {
+		return fmt.Errorf("failed to restore note: %w", err)
+	}
+
+	fmt.Printf("Restored %s from %s\n", notePath, label)
+	return nil
+}
+
+func checkRestoreTarget(ctx context.Context, gitManager *git.Manager, notePath string, content []byte) error {
+	current, err := os.ReadFile(notePath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", notePath, err)
+	}
+
+	if bytes.Equal(current, content) {
+		return nil
+	}
+
+	committed, err := gitManager.Show(ctx, "HEAD", notePath)
+	if err != nil || !bytes.Equal(current, committed) {
+		return fmt.Errorf("%s has uncommitted changes that restoring would overwrite; commit them first or use --force", notePath)
+	}
+
+	return nil
+}
+
+func resolveRestorePath(ctx context.Context, gitManager *git.Manager, arg string) (string, error) {
+	path := rootPath(arg)
+	if _, err := os.Stat(path); err == nil {
+		return path, nil
+	}
+
+	deleted, err := deletedNotes(ctx, gitManager, 0)
+	if err != nil {
+		return "", err
+	}
+
+	for _, deletion := range deleted {
+		title, _, _ := note.ParseFilename(filepath.Base(deletion.Path))
+		if deletion.Path == path || strings.EqualFold(title, arg) {
+			return deletion.Path, nil
+		}
+	}
+
+	if notePath, err := resolveNote(arg); err == nil {
+		return notePath, nil
+	}
+
+	return "", fmt.Errorf("no note or deleted note found matching %q", arg)
+}
+
+func runTrash(cmd *cobra.Command, args []string) error {
+	ctx := commandContext(cmd)
+	gitManager, err := newGitManager()
+	if err != nil {
+		return err
+	}
+
+	if !gitManager.IsGitRepo(ctx) {
+		return fmt.Errorf("current directory is not a git repository")
+	}
+
+	deleted, err := deletedNotes(ctx, gitManager, trashCommits)
+	if err != nil {
+		return err
+	}
+
+	if len(deleted) == 0 {
+		fmt.Println("No deleted notes")
+		return nil
+	}
+
+	for _, deletion := range deleted {
+		fmt.Printf("%s  %s  %s  (deleted by %s: %s)\n", deletion.Hash[:7], deletion.Date.Format("2006-01-02"), deletion.Path, deletion.Author, deletion.Subject)
+	}
+
+	return nil
+}
+
+func deletedNotes(ctx context.Context, gitManager *git.Manager, commits int) ([]git.Commit, error) {
+	deleted, err := gitManager.DeletedFiles(ctx, commits)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var notes []git.Commit
+
+	for _, deletion := range deleted {
+		if seen[deletion.Path] || !note.IsNotePath(deletion.Path) {
+			continue
+		}
+		seen[deletion.Path] = true
+
+		if _, err := os.Stat(deletion.Path); err == nil {
+			continue
+		}
+
+		notes = append(notes, deletion)
+	}
+
+	return notes, nil
+}
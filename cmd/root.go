@@ -10,6 +10,7 @@ var rootCmd = &cobra.Command{
 	Long: `GitNote is a command-line tool that helps you organise and manage
 markdown notes in a Git repository with automatic naming conventions
 and directory-based organisation.`,
+	PersistentPreRunE: enterRepoRoot,
 }
 
 func Execute() error {
@@ -17,9 +18,35 @@ func Execute() error {
 }
 
 func init() {
+	rootCmd.PersistentFlags().StringVarP(&repoDir, "repo", "C", "", "Notes repository to use (defaults to $GITNOTE_DIR, then the nearest .gitnote marker or git repository)")
+	rootCmd.PersistentFlags().StringVar(&notebookName, "notebook", "", "Named notebook to use (see 'gitnote notebook list')")
+	rootCmd.PersistentFlags().BoolVar(&nonInteractive, "non-interactive", false, "Never let git prompt for credentials, even in a terminal")
 	rootCmd.AddCommand(newCmd)
 	rootCmd.AddCommand(indexCmd)
 	rootCmd.AddCommand(searchCmd)
 	rootCmd.AddCommand(commitCmd)
 	rootCmd.AddCommand(pullCmd)
+	rootCmd.AddCommand(syncCmd)
+	rootCmd.AddCommand(undoCmd)
+	rootCmd.AddCommand(historyCmd)
+	rootCmd.AddCommand(diffCmd)
+	rootCmd.AddCommand(showCmd)
+	rootCmd.AddCommand(restoreCmd)
+	rootCmd.AddCommand(trashCmd)
+	rootCmd.AddCommand(serveCmd)
+	rootCmd.AddCommand(exportCmd)
+	rootCmd.AddCommand(importCmd)
+	rootCmd.AddCommand(todayCmd)
+	rootCmd.AddCommand(journalCmd)
+	rootCmd.AddCommand(captureCmd)
+	rootCmd.AddCommand(inboxCmd)
+	rootCmd.AddCommand(todoCmd)
+	rootCmd.AddCommand(notebookCmd)
+	rootCmd.AddCommand(initCmd)
+	rootCmd.AddCommand(attachCmd)
+	rootCmd.AddCommand(attachmentsCmd)
+	rootCmd.AddCommand(openCmd)
+	rootCmd.AddCommand(encryptCmd)
+	rootCmd.AddCommand(decryptCmd)
+	rootCmd.AddCommand(keygenCmd)
 }
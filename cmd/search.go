@@ -5,11 +5,15 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"gitnote/internal/config"
 	"gitnote/internal/note"
+	"gitnote/internal/notebook"
 )
 
 var (
-	searchFull bool
+	searchFull    bool
+	searchAll     bool
+	searchDecrypt bool
 )
 
 var searchCmd = &cobra.Command{
@@ -22,11 +26,29 @@ var searchCmd = &cobra.Command{
 
 func init() {
 	searchCmd.Flags().BoolVar(&searchFull, "full", false, "Search in file content as well as titles")
+	searchCmd.Flags().BoolVar(&searchAll, "all", false, "Search every notebook and label results by notebook")
+	searchCmd.Flags().BoolVar(&searchDecrypt, "decrypt", false, "Also search the content of encrypted notes, decrypted in memory (requires --full)")
 }
 
 func runSearch(cmd *cobra.Command, args []string) error {
 	query := args[0]
-	noteManager := note.NewManager(".")
+	if searchDecrypt && !searchFull {
+		return fmt.Errorf("--decrypt only applies to content search: add --full")
+	}
+	if searchAll && searchDecrypt {
+		return fmt.Errorf("--decrypt cannot be combined with --all: each notebook has its own encryption settings")
+	}
+	if searchAll {
+		return searchNotebooks(query)
+	}
+	
+	noteManager, err := newNoteManager()
+	if err != nil {
+		return err
+	}
+	if searchDecrypt {
+		noteManager.SetDecrypter(lazyDecrypter())
+	}
 	
 	results, err := noteManager.SearchNotes(query, searchFull)
 	if err != nil {
@@ -42,5 +64,43 @@ func runSearch(cmd *cobra.Command, args []string) error {
 		fmt.Println(note.Path)
 	}
 	
+	return nil
+}
+
+func openNotebook(nb notebook.Notebook) (*note.Manager, error) {
+	cfg, err := config.Load(nb.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	
+	noteManager := note.NewManager(nb.Path)
+	noteManager.SetAssetDir(cfg.Attachments.Dir)
+	return noteManager, nil
+}
+
+func searchNotebooks(query string) error {
+	registry, err := loadNotebooks()
+	if err != nil {
+		return err
+	}
+	
+	if len(registry.Notebooks) == 0 {
+		return fmt.Errorf("no notebooks configured: add one with 'gitnote notebook add <name> <path>'")
+	}
+	
+	results, err := notebook.Search(registry.Notebooks, openNotebook, query, searchFull)
+	if err != nil {
+		fmt.Printf("Warning: %v\n", err)
+	}
+	
+	if len(results) == 0 {
+		fmt.Println("No notes found matching the query")
+		return nil
+	}
+	
+	for _, result := range results {
+		fmt.Printf("%s: %s\n", result.Notebook, result.Note.Path)
+	}
+	
 	return nil
 }
\ No newline at end of file
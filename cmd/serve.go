@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/spf13/cobra"
+
+	"gitnote/internal/config"
+	"gitnote/internal/server"
+)
+
+var (
+	serveAddr     string
+	serveNoReload bool
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Preview notes in a browser",
+	Long:  "Start a local HTTP server that renders notes and category indexes, with search, a JSON API and live reload",
+	Args:  cobra.NoArgs,
+	RunE:  runServe,
+}
+
+func init() {
+	serveCmd.Flags().StringVar(&serveAddr, "addr", "localhost:8080", "Address to listen on")
+	serveCmd.Flags().BoolVar(&serveNoReload, "no-reload", false, "Disable live reload when notes change")
+}
+
+func runServe(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load(".")
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	gitManager, err := newGitManager()
+	if err != nil {
+		return err
+	}
+
+	previewServer := server.NewServer(".")
+	previewServer.SetGitManager(commandContext(cmd), gitManager)
+	previewServer.SetAssetDir(cfg.Attachments.Dir)
+	previewServer.SetLiveReload(!serveNoReload)
+
+	fmt.Printf("Serving notes at http://%s\n", serveAddr)
+
+	if err := http.ListenAndServe(serveAddr, previewServer.Handler()); err != nil {
+		return fmt.Errorf("failed to serve notes: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,255 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"gitnote/internal/config"
+	"gitnote/internal/git"
+	"gitnote/internal/index"
+	"gitnote/internal/note"
+)
+
+var syncStrategy string
+
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Commit, pull and push notes in one step",
+	Long:  "Commit local note changes, pull from the remote (merge or rebase), regenerate the index if notes changed and push. Stops with a summary on conflicts or a rejected push",
+	Args:  cobra.NoArgs,
+	RunE:  runSync,
+}
+
+func init() {
+	syncCmd.Flags().StringVar(&syncStrategy, "strategy", "", "Pull strategy: merge or rebase (defaults to sync.strategy in .gitnote.yaml)")
+}
+
+func runSync(cmd *cobra.Command, args []string) error {
+	ctx := commandContext(cmd)
+	gitManager, err := newGitManager()
+	if err != nil {
+		return err
+	}
+
+	if !gitManager.IsGitRepo(ctx) {
+		return fmt.Errorf("current directory is not a git repository")
+	}
+
+	cfg, err := config.Load(".")
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	strategy := cfg.Sync.Strategy
+	if syncStrategy != "" {
+		strategy = syncStrategy
+	}
+	if strategy != git.StrategyMerge && strategy != git.StrategyRebase {
+		return fmt.Errorf("invalid strategy %q: use merge or rebase", strategy)
+	}
+
+	var summary []string
+
+	message, err := commitAllNotes(ctx, gitManager, cfg)
+	if err != nil {
+		return err
+	}
+	if message != "" {
+		summary = append(summary, fmt.Sprintf("Committed: %s", strings.SplitN(message, "\n", 2)[0]))
+	}
+
+	before, err := gitManager.Head(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := gitManager.SavePrePull(ctx); err != nil {
+		return fmt.Errorf("failed to record pre-pull state: %w", err)
+	}
+
+	output, err := gitManager.PullWith(ctx, strategy)
+	if err != nil {
+		hasConflicts, conflictErr := gitManager.HasMergeConflicts(ctx)
+		if conflictErr != nil {
+			return fmt.Errorf("failed to check merge conflicts: %w", conflictErr)
+		}
+
+		if !hasConflicts {
+			printSyncSummary(summary)
+			return explainGitError(fmt.Errorf("sync stopped: pull failed: %w\nOutput: %s", err, output))
+		}
+
+		resolved, err := resolveIndexConflicts(ctx, gitManager)
+		if err != nil {
+			return err
+		}
+
+		if !resolved {
+			printSyncSummary(summary)
+			return syncConflictError(ctx, gitManager, strategy)
+		}
+
+		summary = append(summary, "Index: regenerated readme.md to resolve conflicts")
+	}
+
+	after, err := gitManager.Head(ctx)
+	if err != nil {
+		return err
+	}
+
+	if before != after && before != "" {
+		changes, err := gitManager.Changes(ctx, before, after)
+		if err != nil {
+			return err
+		}
+		summary = append(summary, "Pulled: "+strings.TrimRight(formatPullSummary(changes), "\n"))
+
+		if notesChanged(changes) {
+			updated, err := updateIndex(ctx, gitManager)
+			if err != nil {
+				return err
+			}
+			if updated {
+				summary = append(summary, "Index: regenerated readme.md")
+			}
+		}
+	} else {
+		summary = append(summary, "Pulled: already up to date")
+	}
+
+	if _, err := gitManager.Push(ctx); err != nil {
+		printSyncSummary(summary)
+		if errors.Is(err, git.ErrPushRejected) {
+			return fmt.Errorf("sync stopped: the remote has new commits; run 'gitnote sync' again")
+		}
+		return explainGitError(fmt.Errorf("sync stopped: %w", err))
+	}
+
+	summary = append(summary, "Pushed")
+	printSyncSummary(summary)
+	return nil
+}
+
+func commitAllNotes(ctx context.Context, gitManager *git.Manager, cfg *config.Config) (string, error) {
+	status, err := gitManager.GetStatus(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get git status: %w", err)
+	}
+
+	var entries []git.StatusEntry
+	var notePaths []string
+	for _, entry := range status {
+		if isNoteChange(entry, cfg.Attachments.Dir) {
+			entries = append(entries, entry)
+			notePaths = append(notePaths, entry.Path)
+			if entry.OrigPath != "" {
+				notePaths = append(notePaths, entry.OrigPath)
+			}
+		}
+	}
+
+	if err := checkPlainNotes(cfg, entries); err != nil {
+		return "", err
+	}
+
+	if len(notePaths) == 0 {
+		return "", nil
+	}
+
+	if err := gitManager.AddAll(ctx, unstagedPaths(entries)); err != nil {
+		return "", fmt.Errorf("failed to add files: %w", err)
+	}
+
+	tracking, err := checkLargeFiles(ctx, gitManager, cfg.LFS, notePaths)
+	if err != nil {
+		return "", err
+	}
+	notePaths = append(notePaths, tracking...)
+
+	status, err = gitManager.GetStatus(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get git status: %w", err)
+	}
+
+	changes, staged := collectNoteChanges(status, notePaths)
+	if !staged {
+		return "", nil
+	}
+
+	message := buildCommitMessage(changes)
+	if err := gitManager.CommitPaths(ctx, message, notePaths); err != nil {
+		return "", fmt.Errorf("failed to commit: %w", err)
+	}
+
+	return message, nil
+}
+
+func notesChanged(summary git.ChangeSummary) bool {
+	for _, change := range summary.Changes {
+		if note.IsNotePath(change.Path) && !index.IsGenerated(change.Path) {
+			return true
+		}
+	}
+	return false
+}
+
+func updateIndex(ctx context.Context, gitManager *git.Manager) (bool, error) {
+	generator, err := newIndexGenerator(".")
+	if err != nil {
+		return false, err
+	}
+
+	upToDate, err := generator.IsReadmeUpToDate()
+	if err != nil {
+		return false, fmt.Errorf("failed to check readme status: %w", err)
+	}
+
+	if upToDate {
+		return false, nil
+	}
+
+	if err := generator.GenerateReadme(); err != nil {
+		return false, fmt.Errorf("failed to generate readme: %w", err)
+	}
+
+	if err := gitManager.AddAll(ctx, []string{index.ReadmeFile}); err != nil {
+		return false, fmt.Errorf("failed to add readme: %w", err)
+	}
+
+	if err := gitManager.CommitPaths(ctx, "Update index", []string{index.ReadmeFile}); err != nil {
+		return false, fmt.Errorf("failed to commit readme: %w", err)
+	}
+
+	return true, nil
+}
+
+func syncConflictError(ctx context.Context, gitManager *git.Manager, strategy string) error {
+	status, err := gitManager.GetStatus(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get git status: %w", err)
+	}
+
+	var conflicted []string
+	for _, entry := range status {
+		if entry.IsConflicted() {
+			conflicted = append(conflicted, "  "+entry.Path)
+		}
+	}
+
+	next := "resolve them, commit and run 'gitnote sync' again, or run 'git merge --abort'"
+	if strategy == git.StrategyRebase {
+		next = "resolve them, run 'git rebase --continue' and then 'gitnote sync' again, or run 'git rebase --abort'"
+	}
+
+	return fmt.Errorf("sync stopped: pull has conflicts in:\n%s\n%s", strings.Join(conflicted, "\n"), next)
+}
+
+func printSyncSummary(summary []string) {
+	for _, line := range summary {
+		fmt.Println(line)
+	}
+}
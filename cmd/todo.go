@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"gitnote/internal/config"
+	"gitnote/internal/task"
+)
+
+var (
+	todoCategory string
+	todoTag      string
+	todoAssignee string
+	todoDue      string
+	todoAll      bool
+)
+
+var todoCmd = &cobra.Command{
+	Use:   "todo",
+	Short: "List open tasks across notes",
+	Long:  "List Markdown checkboxes (- [ ] ...) from all notes, ordered by due date. Tasks can carry @due(yyyy-mm-dd), @assignee and #tag markers",
+	Args:  cobra.NoArgs,
+	RunE:  runTodo,
+}
+
+var todoDoneCmd = &cobra.Command{
+	Use:   "done <id>",
+	Short: "Mark a task as done",
+	Long:  "Tick the checkbox of the task with the given id (or unique id prefix) in its note",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runTodoDone,
+}
+
+func init() {
+	todoCmd.Flags().StringVarP(&todoCategory, "category", "c", "", "Only list tasks in this category")
+	todoCmd.Flags().StringVarP(&todoTag, "tag", "t", "", "Only list tasks with this tag")
+	todoCmd.Flags().StringVar(&todoAssignee, "assignee", "", "Only list tasks assigned to this person")
+	todoCmd.Flags().StringVar(&todoDue, "due", "", "Only list tasks due by a date: today, week, overdue or yyyy-mm-dd")
+	todoCmd.Flags().BoolVar(&todoAll, "all", false, "Include completed tasks")
+	todoCmd.AddCommand(todoDoneCmd)
+}
+
+func runTodo(cmd *cobra.Command, args []string) error {
+	dueBy, err := parseDueFilter(todoDue, time.Now())
+	if err != nil {
+		return err
+	}
+
+	taskManager, err := newTaskManager()
+	if err != nil {
+		return err
+	}
+
+	tasks, err := taskManager.FindTasks()
+	if err != nil {
+		return fmt.Errorf("failed to find tasks: %w", err)
+	}
+
+	tasks = task.FilterTasks(tasks, task.Filter{
+		Category:    todoCategory,
+		Tag:         todoTag,
+		Assignee:    todoAssignee,
+		DueBy:       dueBy,
+		IncludeDone: todoAll,
+	})
+
+	if len(tasks) == 0 {
+		fmt.Println("No tasks found")
+		return nil
+	}
+
+	for _, t := range tasks {
+		fmt.Println(formatTask(t))
+	}
+
+	return nil
+}
+
+func runTodoDone(cmd *cobra.Command, args []string) error {
+	taskManager, err := newTaskManager()
+	if err != nil {
+		return err
+	}
+
+	completed, err := taskManager.Complete(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to complete task: %w", err)
+	}
+
+	fmt.Printf("Completed %s: %s\n", completed.ID, completed.Text)
+	return nil
+}
+
+func parseDueFilter(value string, now time.Time) (time.Time, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+
+	switch strings.ToLower(value) {
+	case "":
+		return time.Time{}, nil
+	case "today":
+		return today, nil
+	case "overdue":
+		return today.AddDate(0, 0, -1), nil
+	case "week":
+		return today.AddDate(0, 0, 7), nil
+	}
+
+	date, err := time.ParseInLocation(task.DueFormat, value, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid due filter %q: use today, week, overdue or yyyy-mm-dd", value)
+	}
+
+	return date, nil
+}
+
+func formatTask(t task.Task) string {
+	checkbox := "[ ]"
+	if t.Done {
+		checkbox = "[x]"
+	}
+
+	line := fmt.Sprintf("%s  %s %s", t.ID, checkbox, t.Text)
+	if !t.Due.IsZero() {
+		line += fmt.Sprintf("  (due %s)", t.Due.Format(task.DueFormat))
+	}
+
+	return fmt.Sprintf("%s  %s:%d", line, t.Path, t.Line)
+}
+
+func newTaskManager() (*task.Manager, error) {
+	cfg, err := config.Load(".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	taskManager := task.NewManager(".")
+	taskManager.SetAssetDir(cfg.Attachments.Dir)
+	return taskManager, nil
+}
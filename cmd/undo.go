@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"gitnote/internal/git"
+)
+
+var undoCmd = &cobra.Command{
+	Use:   "undo",
+	Short: "Return to the state before the last pull",
+	Long:  "Move the current branch back to the commit recorded before the last 'gitnote pull' or 'gitnote sync'. Uncommitted local changes are kept",
+	Args:  cobra.NoArgs,
+	RunE:  runUndo,
+}
+
+func runUndo(cmd *cobra.Command, args []string) error {
+	ctx := commandContext(cmd)
+	gitManager, err := newGitManager()
+	if err != nil {
+		return err
+	}
+
+	if !gitManager.IsGitRepo(ctx) {
+		return fmt.Errorf("current directory is not a git repository")
+	}
+
+	state, err := gitManager.State(ctx)
+	if err != nil {
+		return err
+	}
+
+	if state != git.StateNone {
+		return fmt.Errorf("a %s is in progress; roll it back with 'git %s --abort' first", state, state)
+	}
+
+	prePull, err := gitManager.ResolveRef(ctx, git.PrePullRef)
+	if err != nil {
+		return err
+	}
+
+	head, err := gitManager.Head(ctx)
+	if err != nil {
+		return err
+	}
+
+	if prePull == "" || prePull == head {
+		fmt.Println("Nothing to undo")
+		return nil
+	}
+
+	if err := gitManager.ResetKeep(ctx, prePull); err != nil {
+		return fmt.Errorf("failed to undo pull: %w", err)
+	}
+
+	if err := gitManager.DeleteRef(ctx, git.PrePullRef); err != nil {
+		return err
+	}
+
+	fmt.Printf("Returned to %s, the state before the last pull\n", prePull[:7])
+	return nil
+}
@@ -0,0 +1,306 @@
+package attachment
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"gitnote/internal/config"
+	"gitnote/internal/note"
+)
+
+const dateFormat = "2006-01-02-150405"
+
+var (
+	markdownLinkPattern      = regexp.MustCompile(`\]\(\s*(<[^>]*>|[^)\s]+)`)
+	markdownReferencePattern = regexp.MustCompile(`(?m)^ {0,3}\[[^\]]+\]:[ \t]*(<[^>]*>|\S+)`)
+	htmlLinkPattern          = regexp.MustCompile(`(?i)\b(?:src|href)\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s"'>]+))`)
+)
+
+var imageExtensions = map[string]bool{
+	".png":  true,
+	".jpg":  true,
+	".jpeg": true,
+	".gif":  true,
+	".svg":  true,
+	".webp": true,
+	".bmp":  true,
+}
+
+type Manager struct {
+	workingDir  string
+	config      config.AttachmentsConfig
+	noteManager *note.Manager
+}
+
+func NewManager(workingDir string, cfg config.AttachmentsConfig) *Manager {
+	if workingDir == "" {
+		workingDir = "."
+	}
+	noteManager := note.NewManager(workingDir)
+	noteManager.SetAssetDir(cfg.Dir)
+
+	return &Manager{
+		workingDir:  workingDir,
+		config:      cfg,
+		noteManager: noteManager,
+	}
+}
+
+func (m *Manager) SetDecrypter(decrypt note.Decrypter) {
+	m.noteManager.SetDecrypter(decrypt)
+}
+
+func (m *Manager) Dir(notePath string) string {
+	dir := filepath.Join(filepath.Dir(notePath), m.config.Dir)
+	if m.config.Layout == "note" {
+		base := filepath.Base(notePath)
+		dir = filepath.Join(dir, strings.TrimSuffix(base, filepath.Ext(base)))
+	}
+	return dir
+}
+
+func IsAssetPath(path, dir string) bool {
+	for _, part := range strings.Split(filepath.ToSlash(filepath.Dir(path)), "/") {
+		if part == dir {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *Manager) Attach(notePath, source string, now time.Time) (string, error) {
+	if !note.IsNotePath(notePath) {
+		return "", fmt.Errorf("%s is not a note", notePath)
+	}
+	if note.IsEncryptedPath(notePath) {
+		return "", fmt.Errorf("cannot attach to %s: %w", notePath, note.ErrEncrypted)
+	}
+	if _, err := os.Stat(filepath.Join(m.workingDir, notePath)); err != nil {
+		return "", fmt.Errorf("failed to read note: %w", err)
+	}
+
+	content, err := os.ReadFile(source)
+	if err != nil {
+		return "", fmt.Errorf("failed to read attachment: %w", err)
+	}
+
+	assetPath, err := m.place(m.Dir(notePath), strings.ToLower(filepath.Ext(source)), content, now)
+	if err != nil {
+		return "", err
+	}
+
+	link, err := filepath.Rel(filepath.Dir(notePath), assetPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to get relative path: %w", err)
+	}
+
+	if err := m.noteManager.AppendToNote(notePath, markdownLink(filepath.Base(source), link)); err != nil {
+		return "", err
+	}
+
+	return assetPath, nil
+}
+
+func (m *Manager) place(dir, ext string, content []byte, now time.Time) (string, error) {
+	var name string
+	if m.config.Naming == "date" {
+		name = now.Format(dateFormat)
+	} else {
+		sum := sha256.Sum256(content)
+		name = hex.EncodeToString(sum[:])[:12]
+	}
+
+	assetPath := filepath.Join(dir, name+ext)
+	for i := 2; ; i++ {
+		existing, err := os.ReadFile(filepath.Join(m.workingDir, assetPath))
+		if os.IsNotExist(err) {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to read %s: %w", assetPath, err)
+		}
+		if string(existing) == string(content) {
+			return assetPath, nil
+		}
+		assetPath = filepath.Join(dir, fmt.Sprintf("%s-%d%s", name, i, ext))
+	}
+
+	fullPath := filepath.Join(m.workingDir, assetPath)
+	if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+		return "", fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	if err := os.WriteFile(fullPath, content, 0644); err != nil {
+		return "", fmt.Errorf("failed to write attachment: %w", err)
+	}
+
+	return assetPath, nil
+}
+
+func markdownLink(name, target string) string {
+	target = (&url.URL{Path: filepath.ToSlash(target)}).EscapedPath()
+
+	if imageExtensions[strings.ToLower(filepath.Ext(name))] {
+		return fmt.Sprintf("![%s](%s)", strings.TrimSuffix(name, filepath.Ext(name)), target)
+	}
+	return fmt.Sprintf("[%s](%s)", name, target)
+}
+
+func (m *Manager) Unreferenced() ([]string, error) {
+	assets, err := m.assets()
+	if err != nil {
+		return nil, err
+	}
+
+	referenced, err := m.references()
+	if err != nil {
+		return nil, err
+	}
+
+	var unreferenced []string
+	for _, asset := range assets {
+		if !referenced[asset] {
+			unreferenced = append(unreferenced, asset)
+		}
+	}
+
+	return unreferenced, nil
+}
+
+func (m *Manager) Remove(assets []string) error {
+	for _, asset := range assets {
+		if err := os.Remove(filepath.Join(m.workingDir, asset)); err != nil {
+			return fmt.Errorf("failed to remove %s: %w", asset, err)
+		}
+
+		for dir := filepath.Dir(asset); dir != "." && filepath.Base(dir) != m.config.Dir; dir = filepath.Dir(dir) {
+			if os.Remove(filepath.Join(m.workingDir, dir)) != nil {
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+func (m *Manager) assets() ([]string, error) {
+	var assets []string
+
+	collect := func(fullPath string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+
+		relativePath, err := filepath.Rel(m.workingDir, fullPath)
+		if err != nil {
+			return err
+		}
+
+		assets = append(assets, relativePath)
+		return nil
+	}
+
+	err := filepath.Walk(m.workingDir, func(fullPath string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() || fullPath == m.workingDir {
+			return nil
+		}
+
+		if info.Name() == m.config.Dir {
+			if err := filepath.Walk(fullPath, collect); err != nil {
+				return err
+			}
+			return filepath.SkipDir
+		}
+
+		if strings.HasPrefix(info.Name(), ".") {
+			return filepath.SkipDir
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk directory: %w", err)
+	}
+
+	sort.Strings(assets)
+	return assets, nil
+}
+
+func (m *Manager) references() (map[string]bool, error) {
+	notes, err := m.noteManager.FindNotes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find notes: %w", err)
+	}
+
+	referenced := make(map[string]bool)
+
+	for _, n := range notes {
+		content, err := m.noteManager.ReadNote(n.Path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", n.Path, err)
+		}
+
+		for _, target := range targets(string(content)) {
+			if resolved, ok := resolve(n.Path, target); ok {
+				referenced[resolved] = true
+			}
+		}
+	}
+
+	return referenced, nil
+}
+
+func targets(content string) []string {
+	var found []string
+
+	for _, pattern := range []*regexp.Regexp{markdownLinkPattern, markdownReferencePattern} {
+		for _, match := range pattern.FindAllStringSubmatch(content, -1) {
+			found = append(found, strings.TrimSuffix(strings.TrimPrefix(match[1], "<"), ">"))
+		}
+	}
+	for _, match := range htmlLinkPattern.FindAllStringSubmatch(content, -1) {
+		found = append(found, match[1]+match[2]+match[3])
+	}
+
+	return found
+}
+
+func resolve(notePath, target string) (string, bool) {
+	if strings.Contains(target, "://") || strings.HasPrefix(target, "mailto:") || strings.HasPrefix(target, "#") {
+		return "", false
+	}
+
+	if i := strings.IndexAny(target, "?#"); i >= 0 {
+		target = target[:i]
+	}
+	if unescaped, err := url.PathUnescape(target); err == nil {
+		target = unescaped
+	}
+
+	if strings.HasPrefix(target, "/") {
+		target = path.Clean(target)[1:]
+	} else {
+		target = path.Join(filepath.ToSlash(filepath.Dir(notePath)), target)
+	}
+
+	if target == "" || target == "." || strings.HasPrefix(target, "../") {
+		return "", false
+	}
+
+	return filepath.FromSlash(target), true
+}
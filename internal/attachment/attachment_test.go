@@ -0,0 +1,187 @@
+package attachment
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gitnote/internal/config"
+)
+
+func setupNotes(t *testing.T) (string, string) {
+	tempDir := t.TempDir()
+	os.MkdirAll(filepath.Join(tempDir, "work"), 0755)
+	os.WriteFile(filepath.Join(tempDir, "work", "2025-01-05 plan.md"), []byte("# plan\n"), 0644)
+
+	source := filepath.Join(t.TempDir(), "Screen Shot.PNG")
+	os.WriteFile(source, []byte("png data"), 0644)
+
+	return tempDir, source
+}
+
+func TestAttachByHash(t *testing.T) {
+	tempDir, source := setupNotes(t)
+	notePath := filepath.Join("work", "2025-01-05 plan.md")
+	manager := NewManager(tempDir, config.Default().Attachments)
+
+	assetPath, err := manager.Attach(notePath, source, time.Now())
+	if err != nil {
+		t.Fatalf("Attach failed: %v", err)
+	}
+
+	if filepath.Dir(assetPath) != filepath.Join("work", "assets") || filepath.Ext(assetPath) != ".png" || len(filepath.Base(assetPath)) != len("0123456789ab.png") {
+		t.Errorf("Expected a content-hash name in work/assets, got %s", assetPath)
+	}
+
+	data, err := os.ReadFile(filepath.Join(tempDir, assetPath))
+	if err != nil || string(data) != "png data" {
+		t.Errorf("Expected the attachment to be copied, got %q, %v", data, err)
+	}
+
+	content, _ := os.ReadFile(filepath.Join(tempDir, notePath))
+	expected := "# plan\n![Screen Shot](assets/" + filepath.Base(assetPath) + ")\n"
+	if string(content) != expected {
+		t.Errorf("Expected %q, got %q", expected, content)
+	}
+
+	again, err := manager.Attach(notePath, source, time.Now())
+	if err != nil {
+		t.Fatalf("Second Attach failed: %v", err)
+	}
+	if again != assetPath {
+		t.Errorf("Expected identical content to reuse %s, got %s", assetPath, again)
+	}
+}
+
+func TestAttachByDatePerNote(t *testing.T) {
+	tempDir, source := setupNotes(t)
+	notePath := filepath.Join("work", "2025-01-05 plan.md")
+	manager := NewManager(tempDir, config.AttachmentsConfig{Dir: "assets", Layout: "note", Naming: "date"})
+	now := time.Date(2025, 1, 5, 9, 30, 0, 0, time.Local)
+
+	assetPath, err := manager.Attach(notePath, source, now)
+	if err != nil {
+		t.Fatalf("Attach failed: %v", err)
+	}
+	if assetPath != filepath.Join("work", "assets", "2025-01-05 plan", "2025-01-05-093000.png") {
+		t.Errorf("Unexpected asset path %s", assetPath)
+	}
+
+	other := filepath.Join(t.TempDir(), "report.pdf")
+	os.WriteFile(other, []byte("pdf data"), 0644)
+	os.WriteFile(filepath.Join(tempDir, "work", "assets", "2025-01-05 plan", "2025-01-05-093000.pdf"), []byte("older"), 0644)
+
+	assetPath, err = manager.Attach(notePath, other, now)
+	if err != nil {
+		t.Fatalf("Attach failed: %v", err)
+	}
+	if filepath.Base(assetPath) != "2025-01-05-093000-2.pdf" {
+		t.Errorf("Expected a suffix when the name is taken, got %s", assetPath)
+	}
+
+	content, _ := os.ReadFile(filepath.Join(tempDir, notePath))
+	if !strings.Contains(string(content), "[report.pdf](assets/2025-01-05%20plan/2025-01-05-093000-2.pdf)") {
+		t.Errorf("Expected an escaped file link, got %q", content)
+	}
+}
+
+func TestAttachRejectsNonNotes(t *testing.T) {
+	tempDir, source := setupNotes(t)
+	manager := NewManager(tempDir, config.Default().Attachments)
+
+	if _, err := manager.Attach("image.png", source, time.Now()); err == nil {
+		t.Error("Expected an error when attaching to a non-note")
+	}
+	if _, err := manager.Attach(filepath.Join("work", "2025-01-05 plan.md"), filepath.Join(tempDir, "missing.png"), time.Now()); err == nil {
+		t.Error("Expected an error for a missing file")
+	}
+
+	os.WriteFile(filepath.Join(tempDir, "work", "2025-01-06 secret.md.age"), []byte("age data"), 0644)
+	for _, notePath := range []string{filepath.Join("work", "2025-01-06 secret.md.age"), filepath.Join("work", "2025-01-07 missing.md")} {
+		if _, err := manager.Attach(notePath, source, time.Now()); err == nil {
+			t.Errorf("Expected an error when attaching to %s", notePath)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(tempDir, "work", "assets")); !os.IsNotExist(err) {
+		t.Errorf("Expected no asset to be written for a rejected note, got %v", err)
+	}
+}
+
+func TestUnreferencedAndRemove(t *testing.T) {
+	tempDir := t.TempDir()
+	os.MkdirAll(filepath.Join(tempDir, "work", "assets", "plan"), 0755)
+	os.MkdirAll(filepath.Join(tempDir, "assets"), 0755)
+	os.MkdirAll(filepath.Join(tempDir, ".git", "assets"), 0755)
+
+	os.WriteFile(filepath.Join(tempDir, "work", "plan.md"), []byte("# plan\n\n![used](assets/used.png)\n<img src=\"assets/plan/html.png\">\n[doc](<assets/with space.pdf>)\n"), 0644)
+	os.WriteFile(filepath.Join(tempDir, "readme.md"), []byte("[root](/assets/root.png)\n[web](https://example.com/assets/unused.png)\n"), 0644)
+
+	for _, path := range []string{
+		filepath.Join("work", "assets", "used.png"),
+		filepath.Join("work", "assets", "unused.png"),
+		filepath.Join("work", "assets", "with space.pdf"),
+		filepath.Join("work", "assets", "plan", "html.png"),
+		filepath.Join("work", "assets", "plan", "stale.png"),
+		filepath.Join("assets", "root.png"),
+		filepath.Join(".git", "assets", "ignored.png"),
+	} {
+		os.WriteFile(filepath.Join(tempDir, path), []byte(path), 0644)
+	}
+
+	manager := NewManager(tempDir, config.Default().Attachments)
+
+	unreferenced, err := manager.Unreferenced()
+	if err != nil {
+		t.Fatalf("Unreferenced failed: %v", err)
+	}
+
+	expected := []string{
+		filepath.Join("work", "assets", "plan", "stale.png"),
+		filepath.Join("work", "assets", "unused.png"),
+	}
+	if strings.Join(unreferenced, ",") != strings.Join(expected, ",") {
+		t.Fatalf("Expected %v, got %v", expected, unreferenced)
+	}
+
+	if err := manager.Remove(unreferenced); err != nil {
+		t.Fatalf("Remove failed: %v", err)
+	}
+	for _, path := range expected {
+		if _, err := os.Stat(filepath.Join(tempDir, path)); !os.IsNotExist(err) {
+			t.Errorf("Expected %s to be removed", path)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(tempDir, "work", "assets", "used.png")); err != nil {
+		t.Errorf("Expected referenced attachments to be kept: %v", err)
+	}
+
+	unreferenced, _ = manager.Unreferenced()
+	if len(unreferenced) != 0 {
+		t.Errorf("Expected nothing left to collect, got %v", unreferenced)
+	}
+}
+
+func TestUnreferencedRecognisesReferencesAndHTML(t *testing.T) {
+	tempDir := t.TempDir()
+	os.MkdirAll(filepath.Join(tempDir, "work", "assets"), 0755)
+
+	content := "# plan\n\n![logo][1] and [spec][]\n\n[1]: assets/logo.png\n  [spec]: <assets/spec sheet.pdf> \"Spec\"\n\n" +
+		"<img src='assets/single.png'>\n<img src=assets/bare.png alt=bare>\n<a HREF = \"assets/doc.pdf\">doc</a>\n"
+	os.WriteFile(filepath.Join(tempDir, "work", "plan.md"), []byte(content), 0644)
+
+	for _, name := range []string{"logo.png", "spec sheet.pdf", "single.png", "bare.png", "doc.pdf", "stale.png"} {
+		os.WriteFile(filepath.Join(tempDir, "work", "assets", name), []byte(name), 0644)
+	}
+
+	unreferenced, err := NewManager(tempDir, config.Default().Attachments).Unreferenced()
+	if err != nil {
+		t.Fatalf("Unreferenced failed: %v", err)
+	}
+
+	expected := []string{filepath.Join("work", "assets", "stale.png")}
+	if strings.Join(unreferenced, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected only %v to be unreferenced, got %v", expected, unreferenced)
+	}
+}
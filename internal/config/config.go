@@ -0,0 +1,138 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	FileName   = ".gitnote.yaml"
+	MarkerFile = ".gitnote"
+)
+
+type Config struct {
+	Journal     JournalConfig     `yaml:"journal"`
+	Inbox       InboxConfig       `yaml:"inbox"`
+	Sync        SyncConfig        `yaml:"sync"`
+	Git         GitConfig         `yaml:"git"`
+	Attachments AttachmentsConfig `yaml:"attachments"`
+	LFS         LFSConfig         `yaml:"lfs"`
+	Encryption  EncryptionConfig  `yaml:"encryption"`
+}
+
+type JournalConfig struct {
+	Category string `yaml:"category"`
+	Template string `yaml:"template"`
+}
+
+type InboxConfig struct {
+	Path string `yaml:"path"`
+}
+
+type SyncConfig struct {
+	Strategy  string `yaml:"strategy"`
+	MergeTool string `yaml:"mergetool"`
+}
+
+type AttachmentsConfig struct {
+	Dir    string `yaml:"dir"`
+	Layout string `yaml:"layout"`
+	Naming string `yaml:"naming"`
+}
+
+type LFSConfig struct {
+	Threshold Size `yaml:"threshold"`
+	Track     bool `yaml:"track"`
+}
+
+type EncryptionConfig struct {
+	Recipients []string `yaml:"recipients"`
+	Identity   string   `yaml:"identity"`
+	Categories []string `yaml:"categories"`
+}
+
+type GitConfig struct {
+	Backend string        `yaml:"backend"`
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+func Default() *Config {
+	return &Config{
+		Journal: JournalConfig{
+			Category: "journal",
+		},
+		Inbox: InboxConfig{
+			Path: "inbox.md",
+		},
+		Sync: SyncConfig{
+			Strategy: "merge",
+		},
+		Git: GitConfig{
+			Backend: "exec",
+			Timeout: 2 * time.Minute,
+		},
+		Attachments: AttachmentsConfig{
+			Dir:    "assets",
+			Layout: "category",
+			Naming: "hash",
+		},
+		LFS: LFSConfig{
+			Threshold: 5 * MB,
+		},
+	}
+}
+
+func Load(workingDir string) (*Config, error) {
+	if workingDir == "" {
+		workingDir = "."
+	}
+
+	cfg := Default()
+
+	content, err := os.ReadFile(filepath.Join(workingDir, FileName))
+	if os.IsNotExist(err) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", FileName, err)
+	}
+
+	if err := yaml.Unmarshal(content, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", FileName, err)
+	}
+
+	if cfg.Sync.Strategy != "merge" && cfg.Sync.Strategy != "rebase" {
+		return nil, fmt.Errorf("invalid sync strategy %q in %s: use merge or rebase", cfg.Sync.Strategy, FileName)
+	}
+
+	if cfg.Git.Backend != "exec" && cfg.Git.Backend != "go-git" {
+		return nil, fmt.Errorf("invalid git backend %q in %s: use exec or go-git", cfg.Git.Backend, FileName)
+	}
+
+	if cfg.Git.Timeout < 0 {
+		return nil, fmt.Errorf("invalid git timeout %s in %s: use a positive duration such as 30s, or 0 to disable", cfg.Git.Timeout, FileName)
+	}
+
+	dir := cfg.Attachments.Dir
+	if dir == "" || dir == "." || dir == ".." || filepath.Base(dir) != dir {
+		return nil, fmt.Errorf("invalid attachments dir %q in %s: use a plain directory name such as assets", dir, FileName)
+	}
+
+	if cfg.Attachments.Layout != "category" && cfg.Attachments.Layout != "note" {
+		return nil, fmt.Errorf("invalid attachments layout %q in %s: use category or note", cfg.Attachments.Layout, FileName)
+	}
+
+	if cfg.Attachments.Naming != "hash" && cfg.Attachments.Naming != "date" {
+		return nil, fmt.Errorf("invalid attachments naming %q in %s: use hash or date", cfg.Attachments.Naming, FileName)
+	}
+
+	if cfg.LFS.Threshold < 0 {
+		return nil, fmt.Errorf("invalid lfs threshold %s in %s: use a size such as 5MB, or 0 to disable", cfg.LFS.Threshold, FileName)
+	}
+
+	return cfg, nil
+}
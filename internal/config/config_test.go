@@ -0,0 +1,228 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadDefaults(t *testing.T) {
+	cfg, err := Load(t.TempDir())
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if cfg.Journal.Category != "journal" {
+		t.Errorf("Expected default journal category 'journal', got %s", cfg.Journal.Category)
+	}
+
+	if cfg.Journal.Template != "" {
+		t.Errorf("Expected no default journal template, got %s", cfg.Journal.Template)
+	}
+
+	if cfg.Inbox.Path != "inbox.md" {
+		t.Errorf("Expected default inbox path 'inbox.md', got %s", cfg.Inbox.Path)
+	}
+
+	if cfg.Sync.Strategy != "merge" {
+		t.Errorf("Expected default sync strategy 'merge', got %s", cfg.Sync.Strategy)
+	}
+
+	if cfg.Git.Backend != "exec" {
+		t.Errorf("Expected default git backend 'exec', got %s", cfg.Git.Backend)
+	}
+
+	if cfg.Git.Timeout != 2*time.Minute {
+		t.Errorf("Expected default git timeout 2m, got %s", cfg.Git.Timeout)
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	tempDir := t.TempDir()
+	os.WriteFile(filepath.Join(tempDir, FileName), []byte("journal:\n  template: templates/journal.md\n"), 0644)
+
+	cfg, err := Load(tempDir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if cfg.Journal.Template != "templates/journal.md" {
+		t.Errorf("Expected journal template from file, got %s", cfg.Journal.Template)
+	}
+
+	if cfg.Journal.Category != "journal" {
+		t.Errorf("Expected omitted settings to keep defaults, got %s", cfg.Journal.Category)
+	}
+}
+
+func TestLoadInvalidFile(t *testing.T) {
+	tempDir := t.TempDir()
+	os.WriteFile(filepath.Join(tempDir, FileName), []byte("journal: [unclosed"), 0644)
+
+	if _, err := Load(tempDir); err == nil {
+		t.Error("Expected error for invalid config file")
+	}
+}
+
+func TestLoadInvalidSyncStrategy(t *testing.T) {
+	tempDir := t.TempDir()
+	os.WriteFile(filepath.Join(tempDir, FileName), []byte("sync:\n  strategy: squash\n"), 0644)
+
+	if _, err := Load(tempDir); err == nil {
+		t.Error("Expected error for invalid sync strategy")
+	}
+
+	os.WriteFile(filepath.Join(tempDir, FileName), []byte("sync:\n  strategy: rebase\n"), 0644)
+
+	cfg, err := Load(tempDir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if cfg.Sync.Strategy != "rebase" {
+		t.Errorf("Expected sync strategy 'rebase', got %s", cfg.Sync.Strategy)
+	}
+}
+
+func TestLoadGitBackend(t *testing.T) {
+	tempDir := t.TempDir()
+	os.WriteFile(filepath.Join(tempDir, FileName), []byte("git:\n  backend: libgit2\n"), 0644)
+
+	if _, err := Load(tempDir); err == nil {
+		t.Error("Expected error for invalid git backend")
+	}
+
+	os.WriteFile(filepath.Join(tempDir, FileName), []byte("git:\n  backend: go-git\n"), 0644)
+
+	cfg, err := Load(tempDir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if cfg.Git.Backend != "go-git" {
+		t.Errorf("Expected git backend 'go-git', got %s", cfg.Git.Backend)
+	}
+}
+
+func TestLoadGitTimeout(t *testing.T) {
+	tempDir := t.TempDir()
+	os.WriteFile(filepath.Join(tempDir, FileName), []byte("git:\n  timeout: 30s\n"), 0644)
+
+	cfg, err := Load(tempDir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if cfg.Git.Timeout != 30*time.Second {
+		t.Errorf("Expected git timeout 30s, got %s", cfg.Git.Timeout)
+	}
+
+	os.WriteFile(filepath.Join(tempDir, FileName), []byte("git:\n  timeout: soon\n"), 0644)
+
+	if _, err := Load(tempDir); err == nil {
+		t.Error("Expected error for an invalid git timeout")
+	}
+
+	os.WriteFile(filepath.Join(tempDir, FileName), []byte("git:\n  timeout: -1s\n"), 0644)
+
+	if _, err := Load(tempDir); err == nil {
+		t.Error("Expected error for a negative git timeout")
+	}
+}
+
+func TestLoadAttachments(t *testing.T) {
+	tempDir := t.TempDir()
+
+	cfg, err := Load(tempDir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if cfg.Attachments.Dir != "assets" || cfg.Attachments.Layout != "category" || cfg.Attachments.Naming != "hash" {
+		t.Errorf("Expected default attachments settings, got %+v", cfg.Attachments)
+	}
+
+	os.WriteFile(filepath.Join(tempDir, FileName), []byte("attachments:\n  dir: _files\n  layout: note\n  naming: date\n"), 0644)
+
+	cfg, err = Load(tempDir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if cfg.Attachments.Dir != "_files" || cfg.Attachments.Layout != "note" || cfg.Attachments.Naming != "date" {
+		t.Errorf("Expected attachments settings from file, got %+v", cfg.Attachments)
+	}
+
+	for _, content := range []string{
+		"attachments:\n  dir: images/screens\n",
+		"attachments:\n  layout: global\n",
+		"attachments:\n  naming: original\n",
+	} {
+		os.WriteFile(filepath.Join(tempDir, FileName), []byte(content), 0644)
+
+		if _, err := Load(tempDir); err == nil {
+			t.Errorf("Expected error for %q", content)
+		}
+	}
+}
+
+func TestLoadLFS(t *testing.T) {
+	tempDir := t.TempDir()
+
+	cfg, err := Load(tempDir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if cfg.LFS.Threshold != 5*MB || cfg.LFS.Track {
+		t.Errorf("Expected a 5MB threshold without tracking by default, got %+v", cfg.LFS)
+	}
+
+	os.WriteFile(filepath.Join(tempDir, FileName), []byte("lfs:\n  threshold: 512kb\n  track: true\n"), 0644)
+
+	cfg, err = Load(tempDir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if cfg.LFS.Threshold != 512*KB || !cfg.LFS.Track {
+		t.Errorf("Expected lfs settings from file, got %+v", cfg.LFS)
+	}
+
+	for _, content := range []string{"lfs:\n  threshold: large\n", "lfs:\n  threshold: -1MB\n"} {
+		os.WriteFile(filepath.Join(tempDir, FileName), []byte(content), 0644)
+
+		if _, err := Load(tempDir); err == nil {
+			t.Errorf("Expected error for %q", content)
+		}
+	}
+}
+
+func TestSize(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Size
+		text     string
+	}{
+		{"2048", 2 * KB, "2.0 KB"},
+		{"100 B", 100, "100 B"},
+		{"1.5MB", MB + 512*KB, "1.5 MB"},
+		{"2GB", 2 * GB, "2.0 GB"},
+	}
+
+	for _, test := range tests {
+		size, err := ParseSize(test.input)
+		if err != nil {
+			t.Errorf("ParseSize(%q) failed: %v", test.input, err)
+			continue
+		}
+		if size != test.expected || size.String() != test.text {
+			t.Errorf("ParseSize(%q) = %d (%s), expected %d (%s)", test.input, size, size, test.expected, test.text)
+		}
+	}
+
+	if _, err := ParseSize("5 parsecs"); err == nil {
+		t.Error("Expected an error for an unknown unit")
+	}
+}
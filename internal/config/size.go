@@ -0,0 +1,65 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+type Size int64
+
+const (
+	KB Size = 1 << (10 * (iota + 1))
+	MB
+	GB
+)
+
+var sizeUnits = []struct {
+	suffix string
+	size   Size
+}{
+	{"GB", GB},
+	{"MB", MB},
+	{"KB", KB},
+	{"B", 1},
+}
+
+func ParseSize(value string) (Size, error) {
+	text := strings.ToUpper(strings.TrimSpace(value))
+
+	unit := Size(1)
+	for _, u := range sizeUnits {
+		if strings.HasSuffix(text, u.suffix) {
+			text = strings.TrimSpace(strings.TrimSuffix(text, u.suffix))
+			unit = u.size
+			break
+		}
+	}
+
+	number, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid size %q: use a number with an optional B, KB, MB or GB suffix", value)
+	}
+
+	return Size(number * float64(unit)), nil
+}
+
+func (s *Size) UnmarshalYAML(value *yaml.Node) error {
+	size, err := ParseSize(value.Value)
+	if err != nil {
+		return err
+	}
+	*s = size
+	return nil
+}
+
+func (s Size) String() string {
+	for _, u := range sizeUnits[:len(sizeUnits)-1] {
+		if s >= u.size || -s >= u.size {
+			return fmt.Sprintf("%.1f %s", float64(s)/float64(u.size), u.suffix)
+		}
+	}
+	return fmt.Sprintf("%d B", int64(s))
+}
@@ -0,0 +1,213 @@
+package crypt
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+
+	"gitnote/internal/config"
+)
+
+const IdentityFileName = "identity.txt"
+
+type Cipher struct {
+	recipients   []age.Recipient
+	identityPath string
+	passphrase   func() (string, error)
+	secret       string
+	identities   []age.Identity
+}
+
+func DefaultIdentityPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate user config directory: %w", err)
+	}
+	return filepath.Join(dir, "gitnote", IdentityFileName), nil
+}
+
+func New(cfg config.EncryptionConfig, passphrase func() (string, error)) (*Cipher, error) {
+	c := &Cipher{passphrase: passphrase}
+
+	for _, key := range cfg.Recipients {
+		recipient, err := age.ParseX25519Recipient(strings.TrimSpace(key))
+		if err != nil {
+			return nil, fmt.Errorf("invalid encryption recipient %q: %w", key, err)
+		}
+		c.recipients = append(c.recipients, recipient)
+	}
+
+	identityPath, err := IdentityPath(cfg)
+	if err != nil {
+		return nil, err
+	}
+	c.identityPath = identityPath
+
+	return c, nil
+}
+
+func IdentityPath(cfg config.EncryptionConfig) (string, error) {
+	if cfg.Identity != "" {
+		return expandHome(cfg.Identity), nil
+	}
+	return DefaultIdentityPath()
+}
+
+func (c *Cipher) UsesPassphrase() bool {
+	return len(c.recipients) == 0
+}
+
+func (c *Cipher) Encrypt(plaintext []byte) ([]byte, error) {
+	recipients := c.recipients
+	if c.UsesPassphrase() {
+		secret, err := c.getPassphrase()
+		if err != nil {
+			return nil, err
+		}
+		recipient, err := age.NewScryptRecipient(secret)
+		if err != nil {
+			return nil, fmt.Errorf("failed to derive key: %w", err)
+		}
+		recipients = []age.Recipient{recipient}
+	}
+
+	var buffer bytes.Buffer
+	armored := armor.NewWriter(&buffer)
+
+	w, err := age.Encrypt(armored, recipients...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt: %w", err)
+	}
+	if _, err := w.Write(plaintext); err != nil {
+		return nil, fmt.Errorf("failed to encrypt: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encrypt: %w", err)
+	}
+	if err := armored.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encrypt: %w", err)
+	}
+
+	return buffer.Bytes(), nil
+}
+
+func (c *Cipher) Decrypt(ciphertext []byte) ([]byte, error) {
+	identities, err := c.loadIdentities()
+	if err != nil {
+		return nil, err
+	}
+
+	var source io.Reader = bytes.NewReader(ciphertext)
+	if bytes.HasPrefix(bytes.TrimSpace(ciphertext), []byte(armor.Header)) {
+		source = armor.NewReader(source)
+	}
+
+	r, err := age.Decrypt(source, identities...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt: %w", err)
+	}
+
+	plaintext, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt: %w", err)
+	}
+
+	return plaintext, nil
+}
+
+func (c *Cipher) loadIdentities() ([]age.Identity, error) {
+	if c.identities != nil {
+		return c.identities, nil
+	}
+
+	if c.UsesPassphrase() {
+		secret, err := c.getPassphrase()
+		if err != nil {
+			return nil, err
+		}
+		identity, err := age.NewScryptIdentity(secret)
+		if err != nil {
+			return nil, fmt.Errorf("failed to derive key: %w", err)
+		}
+		c.identities = []age.Identity{identity}
+		return c.identities, nil
+	}
+
+	file, err := os.Open(c.identityPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open identity file: %w", err)
+	}
+	defer file.Close()
+
+	identities, err := age.ParseIdentities(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse identity file %s: %w", c.identityPath, err)
+	}
+
+	c.identities = identities
+	return identities, nil
+}
+
+func (c *Cipher) getPassphrase() (string, error) {
+	if c.secret != "" {
+		return c.secret, nil
+	}
+	if c.passphrase == nil {
+		return "", fmt.Errorf("no encryption recipients configured and no passphrase available")
+	}
+
+	secret, err := c.passphrase()
+	if err != nil {
+		return "", fmt.Errorf("failed to read passphrase: %w", err)
+	}
+	if secret == "" {
+		return "", fmt.Errorf("passphrase cannot be empty")
+	}
+
+	c.secret = secret
+	return secret, nil
+}
+
+func GenerateIdentity(path string) (string, error) {
+	if _, err := os.Stat(path); err == nil {
+		return "", fmt.Errorf("%s already exists", path)
+	}
+
+	identity, err := age.GenerateX25519Identity()
+	if err != nil {
+		return "", fmt.Errorf("failed to generate key: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return "", fmt.Errorf("failed to create directory for %s: %w", path, err)
+	}
+
+	recipient := identity.Recipient().String()
+	content := fmt.Sprintf("# created: %s\n# public key: %s\n%s\n", time.Now().Format(time.RFC3339), recipient, identity)
+
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	return recipient, nil
+}
+
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+
+	return filepath.Join(home, strings.TrimPrefix(path, "~"))
+}
@@ -0,0 +1,107 @@
+package crypt
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"filippo.io/age/armor"
+
+	"gitnote/internal/config"
+)
+
+func TestRecipientRoundTrip(t *testing.T) {
+	identityPath := filepath.Join(t.TempDir(), "keys", IdentityFileName)
+
+	recipient, err := GenerateIdentity(identityPath)
+	if err != nil {
+		t.Fatalf("GenerateIdentity failed: %v", err)
+	}
+	if !strings.HasPrefix(recipient, "age1") {
+		t.Errorf("Expected an age public key, got %s", recipient)
+	}
+
+	info, err := os.Stat(identityPath)
+	if err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("Expected a private identity file, got %v, %v", info, err)
+	}
+
+	if _, err := GenerateIdentity(identityPath); err == nil {
+		t.Error("Expected an error when the identity file already exists")
+	}
+
+	cipher, err := New(config.EncryptionConfig{Recipients: []string{recipient}, Identity: identityPath}, nil)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	ciphertext, err := cipher.Encrypt([]byte("root password: hunter2\n"))
+	if err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+	if !bytes.HasPrefix(ciphertext, []byte(armor.Header)) || bytes.Contains(ciphertext, []byte("hunter2")) {
+		t.Errorf("Expected armored ciphertext, got %q", ciphertext)
+	}
+
+	plaintext, err := cipher.Decrypt(ciphertext)
+	if err != nil {
+		t.Fatalf("Decrypt failed: %v", err)
+	}
+	if string(plaintext) != "root password: hunter2\n" {
+		t.Errorf("Unexpected plaintext %q", plaintext)
+	}
+
+	other, _ := New(config.EncryptionConfig{Recipients: []string{recipient}, Identity: filepath.Join(t.TempDir(), "missing.txt")}, nil)
+	if _, err := other.Decrypt(ciphertext); err == nil {
+		t.Error("Expected an error without the identity file")
+	}
+}
+
+func TestPassphraseRoundTrip(t *testing.T) {
+	prompts := 0
+	passphrase := func(secret string) func() (string, error) {
+		return func() (string, error) {
+			prompts++
+			return secret, nil
+		}
+	}
+
+	cipher, err := New(config.EncryptionConfig{}, passphrase("correct horse"))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if !cipher.UsesPassphrase() {
+		t.Error("Expected passphrase mode without recipients")
+	}
+
+	ciphertext, err := cipher.Encrypt([]byte("secret"))
+	if err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+
+	plaintext, err := cipher.Decrypt(ciphertext)
+	if err != nil || string(plaintext) != "secret" {
+		t.Fatalf("Expected the passphrase to decrypt, got %q, %v", plaintext, err)
+	}
+	if prompts != 1 {
+		t.Errorf("Expected the passphrase to be asked for once, got %d", prompts)
+	}
+
+	wrong, _ := New(config.EncryptionConfig{}, passphrase("battery staple"))
+	if _, err := wrong.Decrypt(ciphertext); err == nil {
+		t.Error("Expected the wrong passphrase to fail")
+	}
+
+	none, _ := New(config.EncryptionConfig{}, nil)
+	if _, err := none.Encrypt([]byte("secret")); err == nil {
+		t.Error("Expected an error without recipients or a passphrase")
+	}
+}
+
+func TestNewInvalidRecipient(t *testing.T) {
+	if _, err := New(config.EncryptionConfig{Recipients: []string{"not-a-key"}}, nil); err == nil {
+		t.Error("Expected an error for an invalid recipient")
+	}
+}
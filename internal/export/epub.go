@@ -0,0 +1,157 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha1"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+
+	"gitnote/internal/note"
+)
+
+const epubContainer = `<?xml version="1.0" encoding="UTF-8"?>
+<container version="1.0" xmlns="urn:oasis:names:tc:opendocument:xmlns:container">
+  <rootfiles>
+    <rootfile full-path="OEBPS/content.opf" media-type="application/oebps-package+xml"/>
+  </rootfiles>
+</container>
+`
+
+type epubFile struct {
+	name    string
+	content string
+}
+
+type epubChapter struct {
+	ID    string
+	File  string
+	Title string
+	Body  string
+}
+
+func (e *Exporter) WriteEPUB(w io.Writer, title string, notes []note.Note) error {
+	chapters := make([]epubChapter, 0, len(notes))
+
+	for i, n := range notes {
+		content, err := e.readNote(n)
+		if err != nil {
+			return err
+		}
+
+		var body bytes.Buffer
+		if err := e.markdown.Convert([]byte(content), &body); err != nil {
+			return fmt.Errorf("failed to render note %s: %w", n.Path, err)
+		}
+
+		chapters = append(chapters, epubChapter{
+			ID:    fmt.Sprintf("note-%03d", i+1),
+			File:  fmt.Sprintf("note-%03d.xhtml", i+1),
+			Title: n.Title,
+			Body:  body.String(),
+		})
+	}
+
+	archive := zip.NewWriter(w)
+
+	mimetype, err := archive.CreateHeader(&zip.FileHeader{Name: "mimetype", Method: zip.Store})
+	if err != nil {
+		return fmt.Errorf("failed to write epub: %w", err)
+	}
+	if _, err := io.WriteString(mimetype, "application/epub+zip"); err != nil {
+		return fmt.Errorf("failed to write epub: %w", err)
+	}
+
+	files := []epubFile{
+		{"META-INF/container.xml", epubContainer},
+		{"OEBPS/content.opf", e.epubPackage(title, chapters)},
+		{"OEBPS/nav.xhtml", epubNavigation(title, chapters)},
+	}
+
+	for _, chapter := range chapters {
+		files = append(files, epubFile{"OEBPS/" + chapter.File, epubDocument(chapter.Title, chapter.Body)})
+	}
+
+	for _, file := range files {
+		writer, err := archive.Create(file.name)
+		if err != nil {
+			return fmt.Errorf("failed to write epub: %w", err)
+		}
+		if _, err := io.WriteString(writer, file.content); err != nil {
+			return fmt.Errorf("failed to write epub: %w", err)
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return fmt.Errorf("failed to write epub: %w", err)
+	}
+
+	return nil
+}
+
+func (e *Exporter) epubPackage(title string, chapters []epubChapter) string {
+	identifier := sha1.New()
+	identifier.Write([]byte(title))
+	for _, chapter := range chapters {
+		identifier.Write([]byte(chapter.Title))
+	}
+	sum := identifier.Sum(nil)
+
+	var manifest, spine strings.Builder
+	manifest.WriteString(`    <item id="nav" href="nav.xhtml" media-type="application/xhtml+xml" properties="nav"/>` + "\n")
+
+	for _, chapter := range chapters {
+		manifest.WriteString(fmt.Sprintf(`    <item id="%s" href="%s" media-type="application/xhtml+xml"/>`+"\n", chapter.ID, chapter.File))
+		spine.WriteString(fmt.Sprintf(`    <itemref idref="%s"/>`+"\n", chapter.ID))
+	}
+
+	return fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<package xmlns="http://www.idpf.org/2007/opf" version="3.0" unique-identifier="book-id">
+  <metadata xmlns:dc="http://purl.org/dc/elements/1.1/">
+    <dc:identifier id="book-id">urn:uuid:%x-%x-%x-%x-%x</dc:identifier>
+    <dc:title>%s</dc:title>
+    <dc:language>en</dc:language>
+    <meta property="dcterms:modified">%s</meta>
+  </metadata>
+  <manifest>
+%s  </manifest>
+  <spine>
+%s  </spine>
+</package>
+`, sum[0:4], sum[4:6], sum[6:8], sum[8:10], sum[10:16], html.EscapeString(title), e.now().UTC().Format("2006-01-02T15:04:05Z"), manifest.String(), spine.String())
+}
+
+func epubNavigation(title string, chapters []epubChapter) string {
+	var items strings.Builder
+
+	for _, chapter := range chapters {
+		items.WriteString(fmt.Sprintf("      <li><a href=\"%s\">%s</a></li>\n", chapter.File, html.EscapeString(chapter.Title)))
+	}
+
+	return fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE html>
+<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops">
+<head><title>%s</title></head>
+<body>
+  <nav epub:type="toc">
+    <h1>%s</h1>
+    <ol>
+%s    </ol>
+  </nav>
+</body>
+</html>
+`, html.EscapeString(title), html.EscapeString(title), items.String())
+}
+
+func epubDocument(title, body string) string {
+	return fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE html>
+<html xmlns="http://www.w3.org/1999/xhtml">
+<head><title>%s</title></head>
+<body>
+%s</body>
+</html>
+`, html.EscapeString(title), body)
+}
@@ -0,0 +1,62 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestWriteEPUB(t *testing.T) {
+	exporter := NewExporter(setupNotes(t))
+
+	notes, _ := exporter.SelectNotes("work", "", false)
+
+	var output bytes.Buffer
+	if err := exporter.Export(&output, FormatEPUB, "work & play", notes); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+
+	reader, err := zip.NewReader(bytes.NewReader(output.Bytes()), int64(output.Len()))
+	if err != nil {
+		t.Fatalf("Failed to open epub: %v", err)
+	}
+
+	if reader.File[0].Name != "mimetype" || reader.File[0].Method != zip.Store {
+		t.Error("Expected uncompressed mimetype as the first entry")
+	}
+
+	files := make(map[string]string)
+	for _, file := range reader.File {
+		rc, err := file.Open()
+		if err != nil {
+			t.Fatalf("Failed to open %s: %v", file.Name, err)
+		}
+		content, _ := io.ReadAll(rc)
+		rc.Close()
+		files[file.Name] = string(content)
+	}
+
+	if files["mimetype"] != "application/epub+zip" {
+		t.Errorf("Unexpected mimetype: %q", files["mimetype"])
+	}
+
+	for _, name := range []string{"META-INF/container.xml", "OEBPS/content.opf", "OEBPS/nav.xhtml", "OEBPS/note-001.xhtml", "OEBPS/note-002.xhtml"} {
+		if _, ok := files[name]; !ok {
+			t.Errorf("Expected epub to contain %s", name)
+		}
+	}
+
+	if !strings.Contains(files["OEBPS/content.opf"], "<dc:title>work &amp; play</dc:title>") {
+		t.Error("Expected escaped title in package metadata")
+	}
+
+	if !strings.Contains(files["OEBPS/nav.xhtml"], `<a href="note-002.xhtml">standup</a>`) {
+		t.Error("Expected navigation to link to standup chapter")
+	}
+
+	if !strings.Contains(files["OEBPS/note-001.xhtml"], "<h2>Goals</h2>") {
+		t.Error("Expected chapter content to be rendered as XHTML")
+	}
+}
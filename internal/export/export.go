@@ -0,0 +1,244 @@
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/renderer/html"
+
+	"gitnote/internal/git"
+	"gitnote/internal/note"
+)
+
+const (
+	FormatMarkdown = "md"
+	FormatEPUB     = "epub"
+	FormatJSON     = "json"
+)
+
+type Exporter struct {
+	workingDir  string
+	noteManager *note.Manager
+	markdown    goldmark.Markdown
+	now         func() time.Time
+}
+
+type Archive struct {
+	Title    string         `json:"title"`
+	Exported time.Time      `json:"exported"`
+	Notes    []ArchivedNote `json:"notes"`
+}
+
+type ArchivedNote struct {
+	note.Note
+	Content string `json:"content"`
+}
+
+func NewExporter(workingDir string) *Exporter {
+	if workingDir == "" {
+		workingDir = "."
+	}
+
+	noteManager := note.NewManager(workingDir)
+	noteManager.SetGitHistory(context.Background(), git.NewManager(workingDir))
+
+	return &Exporter{
+		workingDir:  workingDir,
+		noteManager: noteManager,
+		markdown: goldmark.New(
+			goldmark.WithExtensions(extension.GFM),
+			goldmark.WithRendererOptions(html.WithXHTML()),
+		),
+		now: time.Now,
+	}
+}
+
+func (e *Exporter) SetGitManager(ctx context.Context, gitManager *git.Manager) {
+	e.noteManager.SetGitHistory(ctx, gitManager)
+}
+
+func (e *Exporter) SetAssetDir(dir string) {
+	e.noteManager.SetAssetDir(dir)
+}
+
+func IsValidFormat(format string) bool {
+	switch format {
+	case FormatMarkdown, FormatEPUB, FormatJSON:
+		return true
+	default:
+		return false
+	}
+}
+
+func (e *Exporter) SelectNotes(category, query string, searchContent bool) ([]note.Note, error) {
+	var notes []note.Note
+	var err error
+	if query == "" {
+		notes, err = e.noteManager.FindNotesInCategory(category)
+	} else {
+		notes, err = e.noteManager.SearchNotes(query, searchContent)
+		notes = note.FilterByCategory(notes, category)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var plain []note.Note
+	for _, n := range notes {
+		if !n.Encrypted {
+			plain = append(plain, n)
+		}
+	}
+
+	return plain, nil
+}
+
+func (e *Exporter) Export(w io.Writer, format, title string, notes []note.Note) error {
+	switch format {
+	case FormatMarkdown:
+		return e.WriteMarkdown(w, title, notes)
+	case FormatEPUB:
+		return e.WriteEPUB(w, title, notes)
+	case FormatJSON:
+		return e.WriteJSON(w, title, notes)
+	default:
+		return fmt.Errorf("unsupported export format: %s", format)
+	}
+}
+
+func (e *Exporter) WriteMarkdown(w io.Writer, title string, notes []note.Note) error {
+	var content strings.Builder
+	content.WriteString(fmt.Sprintf("# %s\n\n", title))
+	content.WriteString("## Contents\n\n")
+
+	anchors := make(map[string]int)
+	for _, n := range notes {
+		content.WriteString(fmt.Sprintf("- [%s](#%s)\n", n.Title, uniqueAnchor(anchors, n.Title)))
+	}
+	content.WriteString("\n")
+
+	for _, n := range notes {
+		body, err := e.readNote(n)
+		if err != nil {
+			return err
+		}
+
+		content.WriteString(fmt.Sprintf("## %s\n\n", n.Title))
+		content.WriteString(fmt.Sprintf("_%s · %s_\n\n", filepath.ToSlash(n.Path), n.Date.Format("2006-01-02")))
+
+		section := demoteHeadings(stripTitleHeading(body))
+		if section != "" {
+			content.WriteString(section)
+			content.WriteString("\n\n")
+		}
+	}
+
+	if _, err := io.WriteString(w, content.String()); err != nil {
+		return fmt.Errorf("failed to write markdown export: %w", err)
+	}
+
+	return nil
+}
+
+func (e *Exporter) WriteJSON(w io.Writer, title string, notes []note.Note) error {
+	archive := Archive{
+		Title:    title,
+		Exported: e.now(),
+		Notes:    make([]ArchivedNote, 0, len(notes)),
+	}
+
+	for _, n := range notes {
+		body, err := e.readNote(n)
+		if err != nil {
+			return err
+		}
+		archive.Notes = append(archive.Notes, ArchivedNote{Note: n, Content: body})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	if err := encoder.Encode(archive); err != nil {
+		return fmt.Errorf("failed to write json export: %w", err)
+	}
+
+	return nil
+}
+
+func (e *Exporter) readNote(n note.Note) (string, error) {
+	content, err := os.ReadFile(filepath.Join(e.workingDir, n.Path))
+	if err != nil {
+		return "", fmt.Errorf("failed to read note %s: %w", n.Path, err)
+	}
+
+	return string(content), nil
+}
+
+func stripTitleHeading(content string) string {
+	content = strings.TrimSpace(content)
+
+	if strings.HasPrefix(content, "# ") {
+		if index := strings.Index(content, "\n"); index >= 0 {
+			return strings.TrimSpace(content[index+1:])
+		}
+		return ""
+	}
+
+	return content
+}
+
+func demoteHeadings(content string) string {
+	var lines []string
+	inCodeBlock := false
+
+	for _, line := range strings.Split(content, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inCodeBlock = !inCodeBlock
+		}
+
+		if !inCodeBlock && strings.HasPrefix(line, "#") && !strings.HasPrefix(line, "######") {
+			line = "#" + line
+		}
+
+		lines = append(lines, line)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func uniqueAnchor(anchors map[string]int, title string) string {
+	anchor := slugify(title)
+
+	count := anchors[anchor]
+	anchors[anchor]++
+
+	if count > 0 {
+		return fmt.Sprintf("%s-%d", anchor, count)
+	}
+
+	return anchor
+}
+
+func slugify(title string) string {
+	var slug strings.Builder
+
+	for _, r := range strings.ToLower(title) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_':
+			slug.WriteRune(r)
+		case r == ' ':
+			slug.WriteRune('-')
+		}
+	}
+
+	return slug.String()
+}
@@ -0,0 +1,165 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func setupNotes(t *testing.T) string {
+	tempDir := t.TempDir()
+
+	os.MkdirAll(filepath.Join(tempDir, "work", "meetings"), 0755)
+	os.Mkdir(filepath.Join(tempDir, "personal"), 0755)
+
+	os.WriteFile(filepath.Join(tempDir, "work", "2025-01-02 project plan.md"), []byte("# project plan\n\n## Goals\n\nShip it\n\n```sh\n# not a heading\n```\n"), 0644)
+	os.WriteFile(filepath.Join(tempDir, "work", "meetings", "2025-01-03 standup.md"), []byte("# standup\n\nDiscussed the project"), 0644)
+	os.WriteFile(filepath.Join(tempDir, "personal", "2025-01-04 holiday.md"), []byte("# holiday\n\nBook flights"), 0644)
+
+	return tempDir
+}
+
+func TestNewExporter(t *testing.T) {
+	exporter := NewExporter("")
+	if exporter.workingDir != "." {
+		t.Errorf("Expected working dir to be '.', got %s", exporter.workingDir)
+	}
+}
+
+func TestIsValidFormat(t *testing.T) {
+	for _, format := range []string{FormatMarkdown, FormatEPUB, FormatJSON} {
+		if !IsValidFormat(format) {
+			t.Errorf("Expected %s to be a valid format", format)
+		}
+	}
+
+	if IsValidFormat("pdf") {
+		t.Error("Expected pdf to be an invalid format")
+	}
+}
+
+func TestSelectNotes(t *testing.T) {
+	exporter := NewExporter(setupNotes(t))
+
+	notes, err := exporter.SelectNotes("work", "", false)
+	if err != nil {
+		t.Fatalf("SelectNotes failed: %v", err)
+	}
+
+	if len(notes) != 2 {
+		t.Fatalf("Expected 2 notes in work category, got %d", len(notes))
+	}
+
+	notes, err = exporter.SelectNotes("", "project", true)
+	if err != nil {
+		t.Fatalf("SelectNotes failed: %v", err)
+	}
+
+	if len(notes) != 2 {
+		t.Fatalf("Expected 2 notes matching project, got %d", len(notes))
+	}
+
+	notes, err = exporter.SelectNotes("personal", "project", true)
+	if err != nil {
+		t.Fatalf("SelectNotes failed: %v", err)
+	}
+
+	if len(notes) != 0 {
+		t.Fatalf("Expected no personal notes matching project, got %d", len(notes))
+	}
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	tempDir := setupNotes(t)
+	os.WriteFile(filepath.Join(tempDir, "work", "2025-01-05 long.md"), []byte("# long\n\n"+strings.Repeat("x", 2*1024*1024)+"\n\n## After the long line\n"), 0644)
+	exporter := NewExporter(tempDir)
+
+	notes, _ := exporter.SelectNotes("work", "", false)
+
+	var output bytes.Buffer
+	if err := exporter.Export(&output, FormatMarkdown, "work", notes); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+
+	content := output.String()
+
+	expected := []string{
+		"# work\n",
+		"- [project plan](#project-plan)",
+		"- [standup](#standup)",
+		"## project plan\n\n_work/2025-01-02 project plan.md · 2025-01-02_",
+		"### Goals",
+		"# not a heading",
+		"Discussed the project",
+		"### After the long line",
+	}
+
+	for _, want := range expected {
+		if !strings.Contains(content, want) {
+			t.Errorf("Expected markdown export to contain %q, got:\n%s", want, content)
+		}
+	}
+
+	if strings.Contains(content, "\n# project plan") {
+		t.Error("Expected note title heading to be replaced by the section heading")
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	exporter := NewExporter(setupNotes(t))
+	exporter.now = func() time.Time { return time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC) }
+
+	notes, _ := exporter.SelectNotes("personal", "", false)
+
+	var output bytes.Buffer
+	if err := exporter.Export(&output, FormatJSON, "personal", notes); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+
+	var archive Archive
+	if err := json.Unmarshal(output.Bytes(), &archive); err != nil {
+		t.Fatalf("Failed to decode archive: %v", err)
+	}
+
+	if archive.Title != "personal" || !archive.Exported.Equal(exporter.now()) {
+		t.Errorf("Unexpected archive metadata: %+v", archive)
+	}
+
+	if len(archive.Notes) != 1 {
+		t.Fatalf("Expected 1 archived note, got %d", len(archive.Notes))
+	}
+
+	archived := archive.Notes[0]
+	if archived.Title != "holiday" || archived.Category != "personal" {
+		t.Errorf("Unexpected archived note metadata: %+v", archived.Note)
+	}
+
+	if archived.Content != "# holiday\n\nBook flights" {
+		t.Errorf("Unexpected archived note content: %q", archived.Content)
+	}
+}
+
+func TestExportUnsupportedFormat(t *testing.T) {
+	exporter := NewExporter(setupNotes(t))
+
+	var output bytes.Buffer
+	if err := exporter.Export(&output, "pdf", "notes", nil); err == nil {
+		t.Error("Expected error for unsupported format")
+	}
+}
+
+func TestUniqueAnchor(t *testing.T) {
+	anchors := make(map[string]int)
+
+	if anchor := uniqueAnchor(anchors, "Managing Expectations!"); anchor != "managing-expectations" {
+		t.Errorf("Expected managing-expectations, got %s", anchor)
+	}
+
+	if anchor := uniqueAnchor(anchors, "managing expectations"); anchor != "managing-expectations-1" {
+		t.Errorf("Expected managing-expectations-1, got %s", anchor)
+	}
+}
@@ -0,0 +1,48 @@
+package git
+
+import (
+	"context"
+	"fmt"
+)
+
+const (
+	BackendExec  = "exec"
+	BackendGoGit = "go-git"
+)
+
+type Backend interface {
+	IsRepo(ctx context.Context) bool
+	Status(ctx context.Context) ([]StatusEntry, error)
+	Add(ctx context.Context, paths []string) error
+	Commit(ctx context.Context, message string, paths []string) error
+	Pull(ctx context.Context, strategy string) (string, error)
+	Push(ctx context.Context) (string, error)
+	Log(ctx context.Context, path string) ([]Commit, error)
+	Diff(ctx context.Context, from, to string) (ChangeSummary, error)
+	Show(ctx context.Context, rev, path string) ([]byte, error)
+	ResolveRef(ctx context.Context, ref string) (string, error)
+	UpdateRef(ctx context.Context, ref, rev string) error
+	DeleteRef(ctx context.Context, ref string) error
+	ResetKeep(ctx context.Context, rev string) error
+	State(ctx context.Context) (string, error)
+	TopLevel(ctx context.Context) (string, error)
+	FileDates(ctx context.Context) (map[string]FileDates, error)
+	LFSAvailable(ctx context.Context) bool
+	LFSTracked(ctx context.Context, paths []string) (map[string]bool, error)
+	RepositorySize(ctx context.Context) (int64, error)
+}
+
+func NewBackend(name, workingDir string) (Backend, error) {
+	if workingDir == "" {
+		workingDir = "."
+	}
+
+	switch name {
+	case "", BackendExec:
+		return newExecBackend(workingDir), nil
+	case BackendGoGit:
+		return newGoGitBackend(workingDir), nil
+	}
+
+	return nil, fmt.Errorf("unknown git backend %q: use %s or %s", name, BackendExec, BackendGoGit)
+}
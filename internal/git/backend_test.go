@@ -0,0 +1,596 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func forEachBackend(t *testing.T, test func(t *testing.T, name string)) {
+	for _, name := range []string{BackendExec, BackendGoGit} {
+		t.Run(name, func(t *testing.T) {
+			test(t, name)
+		})
+	}
+}
+
+func newTestBackend(t *testing.T, name, dir string) Backend {
+	backend, err := NewBackend(name, dir)
+	if err != nil {
+		t.Fatalf("NewBackend(%q) failed: %v", name, err)
+	}
+	return backend
+}
+
+func findEntry(entries []StatusEntry, path string) (StatusEntry, bool) {
+	for _, entry := range entries {
+		if entry.Path == path {
+			return entry, true
+		}
+	}
+	return StatusEntry{}, false
+}
+
+func TestNewBackendUnknown(t *testing.T) {
+	if _, err := NewBackend("libgit2", "."); err == nil {
+		t.Error("Expected an error for an unknown backend")
+	}
+}
+
+func TestBackendIsRepo(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, name string) {
+		if newTestBackend(t, name, t.TempDir()).IsRepo(context.Background()) {
+			t.Error("Expected a plain directory not to be a repository")
+		}
+		if !newTestBackend(t, name, setupGitRepo(t)).IsRepo(context.Background()) {
+			t.Error("Expected an initialised directory to be a repository")
+		}
+	})
+}
+
+func TestBackendStatusAddAndCommit(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, name string) {
+		dir := setupGitRepo(t)
+		backend := newTestBackend(t, name, dir)
+
+		os.WriteFile(filepath.Join(dir, "first.md"), []byte("first"), 0644)
+		os.MkdirAll(filepath.Join(dir, "work"), 0755)
+		os.WriteFile(filepath.Join(dir, "work", "second.md"), []byte("second"), 0644)
+
+		status, err := backend.Status(context.Background())
+		if err != nil {
+			t.Fatalf("Status failed: %v", err)
+		}
+		entry, ok := findEntry(status, filepath.Join("work", "second.md"))
+		if !ok || !entry.IsUntracked() {
+			t.Fatalf("Expected work/second.md to be untracked, got %+v", status)
+		}
+
+		if err := backend.Add(context.Background(), []string{"first.md", filepath.Join("work", "second.md")}); err != nil {
+			t.Fatalf("Add failed: %v", err)
+		}
+		if err := backend.Commit(context.Background(), "initial", nil); err != nil {
+			t.Fatalf("Commit failed: %v", err)
+		}
+
+		status, err = backend.Status(context.Background())
+		if err != nil {
+			t.Fatalf("Status failed: %v", err)
+		}
+		if len(status) != 0 {
+			t.Errorf("Expected a clean status after commit, got %+v", status)
+		}
+
+		os.WriteFile(filepath.Join(dir, "first.md"), []byte("first changed"), 0644)
+		os.Remove(filepath.Join(dir, "work", "second.md"))
+		if err := backend.Add(context.Background(), []string{"first.md", filepath.Join("work", "second.md")}); err != nil {
+			t.Fatalf("Add failed: %v", err)
+		}
+
+		status, err = backend.Status(context.Background())
+		if err != nil {
+			t.Fatalf("Status failed: %v", err)
+		}
+		if entry, ok := findEntry(status, "first.md"); !ok || entry.Index != 'M' {
+			t.Errorf("Expected first.md to be staged as modified, got %+v", status)
+		}
+		if entry, ok := findEntry(status, filepath.Join("work", "second.md")); !ok || !entry.IsDeleted() || !entry.IsStaged() {
+			t.Errorf("Expected work/second.md to be staged as deleted, got %+v", status)
+		}
+	})
+}
+
+func TestBackendCommitPaths(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, name string) {
+		dir := setupGitRepo(t)
+		backend := newTestBackend(t, name, dir)
+
+		os.WriteFile(filepath.Join(dir, "first.md"), []byte("first"), 0644)
+		if err := backend.Add(context.Background(), []string{"first.md"}); err != nil {
+			t.Fatalf("Add failed: %v", err)
+		}
+		if err := backend.Commit(context.Background(), "initial", nil); err != nil {
+			t.Fatalf("Commit failed: %v", err)
+		}
+
+		os.WriteFile(filepath.Join(dir, "first.md"), []byte("first changed"), 0644)
+		os.WriteFile(filepath.Join(dir, "second.md"), []byte("second"), 0644)
+		if err := backend.Add(context.Background(), []string{"first.md", "second.md"}); err != nil {
+			t.Fatalf("Add failed: %v", err)
+		}
+
+		if err := backend.Commit(context.Background(), "Update first", []string{"first.md"}); err != nil {
+			t.Fatalf("Commit with paths failed: %v", err)
+		}
+
+		status, err := backend.Status(context.Background())
+		if err != nil {
+			t.Fatalf("Status failed: %v", err)
+		}
+		if _, ok := findEntry(status, "first.md"); ok {
+			t.Errorf("Expected first.md to be committed, got %+v", status)
+		}
+		if entry, ok := findEntry(status, "second.md"); !ok || entry.Index != 'A' {
+			t.Errorf("Expected second.md to stay staged, got %+v", status)
+		}
+
+		commits, err := backend.Log(context.Background(), "first.md")
+		if err != nil {
+			t.Fatalf("Log failed: %v", err)
+		}
+		if len(commits) != 2 || commits[0].Subject != "Update first" || commits[1].Subject != "initial" {
+			t.Fatalf("Unexpected history: %+v", commits)
+		}
+		if commits[0].Author != "Test User" || commits[0].Path != "first.md" || len(commits[0].Hash) != 40 {
+			t.Errorf("Unexpected commit fields: %+v", commits[0])
+		}
+
+		commits, err = backend.Log(context.Background(), "second.md")
+		if err != nil {
+			t.Fatalf("Log failed: %v", err)
+		}
+		if len(commits) != 0 {
+			t.Errorf("Expected no history for an uncommitted note, got %+v", commits)
+		}
+	})
+}
+
+func TestBackendLogEmptyRepo(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, name string) {
+		commits, err := newTestBackend(t, name, setupGitRepo(t)).Log(context.Background(), "first.md")
+		if err != nil || len(commits) != 0 {
+			t.Errorf("Expected no history and no error, got %+v, %v", commits, err)
+		}
+	})
+}
+
+func TestBackendDiff(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, name string) {
+		dir := setupGitRepo(t)
+		backend := newTestBackend(t, name, dir)
+
+		os.WriteFile(filepath.Join(dir, "keep.md"), []byte("keep"), 0644)
+		os.WriteFile(filepath.Join(dir, "move.md"), []byte("a note long enough to be detected as a rename\n"), 0644)
+		os.WriteFile(filepath.Join(dir, "drop.md"), []byte("drop"), 0644)
+		commitAt(t, dir, "initial", 1700000000)
+
+		os.WriteFile(filepath.Join(dir, "keep.md"), []byte("kept and changed"), 0644)
+		os.Rename(filepath.Join(dir, "move.md"), filepath.Join(dir, "moved.md"))
+		os.Remove(filepath.Join(dir, "drop.md"))
+		os.WriteFile(filepath.Join(dir, "new.md"), []byte("new"), 0644)
+		commitAt(t, dir, "second", 1700000100)
+
+		summary, err := backend.Diff(context.Background(), "HEAD~1", "HEAD")
+		if err != nil {
+			t.Fatalf("Diff failed: %v", err)
+		}
+
+		expected := []FileChange{
+			{Status: 'D', Path: "drop.md"},
+			{Status: 'M', Path: "keep.md"},
+			{Status: 'R', Path: "moved.md", OrigPath: "move.md"},
+			{Status: 'A', Path: "new.md"},
+		}
+		if len(summary.Changes) != len(expected) {
+			t.Fatalf("Expected %d changes, got %+v", len(expected), summary.Changes)
+		}
+		for i, change := range expected {
+			if summary.Changes[i] != change {
+				t.Errorf("Change %d: expected %+v, got %+v", i, change, summary.Changes[i])
+			}
+		}
+
+		if len(summary.Authors) != 1 || summary.Authors[0] != "Test User" {
+			t.Errorf("Expected authors [Test User], got %v", summary.Authors)
+		}
+	})
+}
+
+func TestBackendPullAndPush(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, name string) {
+		local, other := setupClones(t)
+		backend := newTestBackend(t, name, local)
+		otherBackend := newTestBackend(t, name, other)
+
+		os.WriteFile(filepath.Join(other, "second.md"), []byte("second"), 0644)
+		commitAt(t, other, "second", 1700000100)
+		if _, err := otherBackend.Push(context.Background()); err != nil {
+			t.Fatalf("Push failed: %v", err)
+		}
+
+		if _, err := backend.Pull(context.Background(), StrategyMerge); err != nil {
+			t.Fatalf("Pull failed: %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(local, "second.md")); err != nil {
+			t.Errorf("Expected second.md after pull: %v", err)
+		}
+		if _, err := backend.Pull(context.Background(), StrategyMerge); err != nil {
+			t.Errorf("Pull when up to date failed: %v", err)
+		}
+
+		os.WriteFile(filepath.Join(other, "third.md"), []byte("third"), 0644)
+		commitAt(t, other, "third", 1700000200)
+		if _, err := otherBackend.Push(context.Background()); err != nil {
+			t.Fatalf("Push failed: %v", err)
+		}
+
+		os.WriteFile(filepath.Join(local, "fourth.md"), []byte("fourth"), 0644)
+		commitAt(t, local, "fourth", 1700000300)
+		if _, err := backend.Push(context.Background()); !errors.Is(err, ErrPushRejected) {
+			t.Errorf("Expected ErrPushRejected, got %v", err)
+		}
+	})
+}
+
+func TestBackendNotARepo(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, name string) {
+		_, err := newTestBackend(t, name, t.TempDir()).Status(context.Background())
+		if !errors.Is(err, ErrNotARepo) {
+			t.Errorf("Expected ErrNotARepo, got %v", err)
+		}
+	})
+}
+
+func TestBackendLogFollowsRenames(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, name string) {
+		dir := setupGitRepo(t)
+		backend := newTestBackend(t, name, dir)
+
+		os.WriteFile(filepath.Join(dir, "draft plan.md"), []byte("a plan long enough to be detected as a rename\n"), 0644)
+		commitAt(t, dir, "Add plan", 1700000000)
+		os.MkdirAll(filepath.Join(dir, "work"), 0755)
+		os.Rename(filepath.Join(dir, "draft plan.md"), filepath.Join(dir, "work", "plan.md"))
+		commitAt(t, dir, "Move plan", 1700000100)
+		os.WriteFile(filepath.Join(dir, "work", "plan.md"), []byte("a plan long enough to be detected as a rename\nand edited\n"), 0644)
+		commitAt(t, dir, "Edit plan", 1700000200)
+
+		commits, err := backend.Log(context.Background(), filepath.Join("work", "plan.md"))
+		if err != nil {
+			t.Fatalf("Log failed: %v", err)
+		}
+
+		expected := []struct{ subject, path string }{
+			{"Edit plan", filepath.Join("work", "plan.md")},
+			{"Move plan", filepath.Join("work", "plan.md")},
+			{"Add plan", "draft plan.md"},
+		}
+		if len(commits) != len(expected) {
+			t.Fatalf("Expected %d commits through the rename, got %+v", len(expected), commits)
+		}
+		for i, want := range expected {
+			if commits[i].Subject != want.subject || commits[i].Path != want.path {
+				t.Errorf("Commit %d: expected %s at %s, got %+v", i, want.subject, want.path, commits[i])
+			}
+		}
+	})
+}
+
+func TestBackendLogFollowsMergedBranch(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, name string) {
+		dir := setupGitRepo(t)
+		backend := newTestBackend(t, name, dir)
+
+		os.WriteFile(filepath.Join(dir, "plan.md"), []byte("plan"), 0644)
+		commitAt(t, dir, "Add plan", 1700000000)
+		runGit(t, dir, "checkout", "-q", "-b", "remote")
+		os.WriteFile(filepath.Join(dir, "plan.md"), []byte("plan from elsewhere"), 0644)
+		commitAt(t, dir, "Edit plan elsewhere", 1700000100)
+		runGit(t, dir, "checkout", "-q", "-")
+		os.WriteFile(filepath.Join(dir, "other.md"), []byte("other"), 0644)
+		commitAt(t, dir, "Add other", 1700000200)
+		runGit(t, dir, "merge", "-q", "--no-edit", "remote")
+
+		commits, err := backend.Log(context.Background(), "plan.md")
+		if err != nil {
+			t.Fatalf("Log failed: %v", err)
+		}
+		if len(commits) != 2 || commits[0].Subject != "Edit plan elsewhere" || commits[1].Subject != "Add plan" {
+			t.Errorf("Expected the merged edit and the original commit, got %+v", commits)
+		}
+	})
+}
+
+func TestBackendPullRebase(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, name string) {
+		local, other := setupClones(t)
+		backend := newTestBackend(t, name, local)
+
+		os.WriteFile(filepath.Join(other, "second.md"), []byte("second"), 0644)
+		commitAt(t, other, "second", 1700000100)
+		runGit(t, other, "push")
+
+		os.WriteFile(filepath.Join(local, "third.md"), []byte("third"), 0644)
+		commitAt(t, local, "third", 1700000200)
+
+		_, err := backend.Pull(context.Background(), StrategyRebase)
+		if name == BackendGoGit {
+			if !errors.Is(err, errRebaseUnsupported) {
+				t.Errorf("Expected the go-git backend to refuse to rebase, got %v", err)
+			}
+			return
+		}
+		if err != nil {
+			t.Fatalf("Pull with rebase failed: %v", err)
+		}
+
+		commits, err := backend.Log(context.Background(), "third.md")
+		if err != nil || len(commits) != 1 {
+			t.Fatalf("Expected the local commit to be replayed, got %+v, %v", commits, err)
+		}
+		parent, err := backend.ResolveRef(context.Background(), commits[0].Hash+"^")
+		if err != nil {
+			t.Fatalf("ResolveRef failed: %v", err)
+		}
+		remote, _ := backend.ResolveRef(context.Background(), "@{upstream}")
+		if parent == "" || parent != remote {
+			t.Errorf("Expected the local commit on top of the remote, got parent %s and remote %s", parent, remote)
+		}
+	})
+}
+
+func TestBackendPullLocalChanges(t *testing.T) {
+	local, other := setupClones(t)
+	backend := newTestBackend(t, BackendGoGit, local)
+
+	os.WriteFile(filepath.Join(other, "first.md"), []byte("first from elsewhere"), 0644)
+	commitAt(t, other, "Edit first", 1700000100)
+	runGit(t, other, "push")
+
+	os.WriteFile(filepath.Join(local, "first.md"), []byte("first edited locally"), 0644)
+
+	if _, err := backend.Pull(context.Background(), StrategyMerge); !errors.Is(err, errLocalChanges) {
+		t.Errorf("Expected the go-git backend to refuse pulling over local changes, got %v", err)
+	}
+}
+
+func TestBackendRefsShowAndReset(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, name string) {
+		dir := setupGitRepo(t)
+		backend := newTestBackend(t, name, dir)
+
+		head, err := backend.ResolveRef(context.Background(), "HEAD")
+		if err != nil || head != "" {
+			t.Errorf("Expected no HEAD in an empty repository, got %q, %v", head, err)
+		}
+
+		os.WriteFile(filepath.Join(dir, "plan.md"), []byte("first"), 0644)
+		commitAt(t, dir, "first", 1700000000)
+		first, err := backend.ResolveRef(context.Background(), "HEAD")
+		if err != nil || len(first) != 40 {
+			t.Fatalf("Expected HEAD to resolve, got %q, %v", first, err)
+		}
+
+		if err := backend.UpdateRef(context.Background(), PrePullRef, first); err != nil {
+			t.Fatalf("UpdateRef failed: %v", err)
+		}
+
+		os.WriteFile(filepath.Join(dir, "plan.md"), []byte("second"), 0644)
+		commitAt(t, dir, "second", 1700000100)
+
+		content, err := backend.Show(context.Background(), PrePullRef, "plan.md")
+		if err != nil || string(content) != "first" {
+			t.Errorf("Expected the recorded version, got %q, %v", content, err)
+		}
+		if _, err := backend.Show(context.Background(), "HEAD", "missing.md"); err == nil {
+			t.Error("Expected an error for a file missing at the revision")
+		}
+
+		os.WriteFile(filepath.Join(dir, "notes.md"), []byte("untracked"), 0644)
+		if err := backend.ResetKeep(context.Background(), PrePullRef); err != nil {
+			t.Fatalf("ResetKeep failed: %v", err)
+		}
+		if head, _ := backend.ResolveRef(context.Background(), "HEAD"); head != first {
+			t.Errorf("Expected HEAD to move back to %s, got %s", first, head)
+		}
+		if content, _ := os.ReadFile(filepath.Join(dir, "plan.md")); string(content) != "first" {
+			t.Errorf("Expected the working tree to follow the reset, got %q", content)
+		}
+		if _, err := os.Stat(filepath.Join(dir, "notes.md")); err != nil {
+			t.Errorf("Expected untracked files to be kept: %v", err)
+		}
+
+		if err := backend.DeleteRef(context.Background(), PrePullRef); err != nil {
+			t.Fatalf("DeleteRef failed: %v", err)
+		}
+		if ref, err := backend.ResolveRef(context.Background(), PrePullRef); err != nil || ref != "" {
+			t.Errorf("Expected the deleted ref not to resolve, got %q, %v", ref, err)
+		}
+	})
+}
+
+func TestBackendStateAndTopLevel(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, name string) {
+		dir := setupGitRepo(t)
+		os.MkdirAll(filepath.Join(dir, "work"), 0755)
+		backend := newTestBackend(t, name, filepath.Join(dir, "work"))
+
+		top, err := backend.TopLevel(context.Background())
+		expected, _ := filepath.EvalSymlinks(dir)
+		if resolved, _ := filepath.EvalSymlinks(top); err != nil || resolved != expected {
+			t.Errorf("Expected top level %s, got %s, %v", expected, top, err)
+		}
+
+		if top, err := newTestBackend(t, name, t.TempDir()).TopLevel(context.Background()); err != nil || top != "" {
+			t.Errorf("Expected no top level outside a repository, got %q, %v", top, err)
+		}
+
+		if state, err := backend.State(context.Background()); err != nil || state != StateNone {
+			t.Errorf("Expected no merge in progress, got %q, %v", state, err)
+		}
+
+		os.WriteFile(filepath.Join(dir, ".git", "MERGE_HEAD"), []byte("0000000000000000000000000000000000000000\n"), 0644)
+		if state, err := backend.State(context.Background()); err != nil || state != StateMerge {
+			t.Errorf("Expected a merge in progress, got %q, %v", state, err)
+		}
+	})
+}
+
+func TestBackendPathsRelativeToSubdirectory(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, name string) {
+		dir := setupGitRepo(t)
+		os.MkdirAll(filepath.Join(dir, "notes"), 0755)
+		os.WriteFile(filepath.Join(dir, "top.md"), []byte("top"), 0644)
+		os.WriteFile(filepath.Join(dir, "notes", "plan.md"), []byte("a plan long enough to be detected as a rename\n"), 0644)
+		commitAt(t, dir, "initial", 1700000000)
+
+		backend := newTestBackend(t, name, filepath.Join(dir, "notes"))
+
+		os.Rename(filepath.Join(dir, "notes", "plan.md"), filepath.Join(dir, "notes", "roadmap.md"))
+		os.WriteFile(filepath.Join(dir, "top.md"), []byte("top changed"), 0644)
+		if err := backend.Add(context.Background(), []string{"plan.md", "roadmap.md"}); err != nil {
+			t.Fatalf("Add failed: %v", err)
+		}
+
+		status, err := backend.Status(context.Background())
+		if err != nil {
+			t.Fatalf("Status failed: %v", err)
+		}
+		if _, ok := findEntry(status, "roadmap.md"); !ok {
+			t.Errorf("Expected roadmap.md relative to the notes directory, got %+v", status)
+		}
+		if _, ok := findEntry(status, filepath.Join("..", "top.md")); !ok {
+			t.Errorf("Expected ../top.md for a file outside the notes directory, got %+v", status)
+		}
+
+		if err := backend.Commit(context.Background(), "Rename plan", []string{"plan.md", "roadmap.md"}); err != nil {
+			t.Fatalf("Commit failed: %v", err)
+		}
+		runGit(t, dir, "commit", "-qam", "Change top")
+
+		summary, err := backend.Diff(context.Background(), "HEAD~2", "HEAD")
+		if err != nil {
+			t.Fatalf("Diff failed: %v", err)
+		}
+		if len(summary.Changes) != 1 || summary.Changes[0] != (FileChange{Status: 'R', Path: "roadmap.md", OrigPath: "plan.md"}) {
+			t.Errorf("Expected only the rename inside the notes directory, got %+v", summary.Changes)
+		}
+
+		commits, err := backend.Log(context.Background(), "roadmap.md")
+		if err != nil || len(commits) != 2 || commits[0].Path != "roadmap.md" || commits[1].Path != "plan.md" {
+			t.Errorf("Expected history relative to the notes directory, got %+v, %v", commits, err)
+		}
+
+		content, err := backend.Show(context.Background(), "HEAD~2", "plan.md")
+		if err != nil || string(content) != "a plan long enough to be detected as a rename\n" {
+			t.Errorf("Expected to show plan.md relative to the notes directory, got %q, %v", content, err)
+		}
+	})
+}
+
+func TestBackendFileDatesAndLFSChecks(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, name string) {
+		dir := setupGitRepo(t)
+		os.MkdirAll(filepath.Join(dir, "work", "assets"), 0755)
+		os.WriteFile(filepath.Join(dir, "work", "first.md"), []byte("v1"), 0644)
+		commitAt(t, dir, "Add first note", 1700000000)
+		os.WriteFile(filepath.Join(dir, "second.md"), []byte("v1"), 0644)
+		os.WriteFile(filepath.Join(dir, "work", "first.md"), []byte("v2"), 0644)
+		commitAt(t, dir, "Update first note", 1700200000)
+
+		os.WriteFile(filepath.Join(dir, ".gitattributes"), []byte("*.bin filter=lfs diff=lfs merge=lfs -text\n"), 0644)
+		os.WriteFile(filepath.Join(dir, "work", "assets", "big.psd"), append([]byte{0}, make([]byte, 4096)...), 0644)
+		os.WriteFile(filepath.Join(dir, "work", "assets", "tracked.bin"), append([]byte{0}, make([]byte, 4096)...), 0644)
+
+		if name == BackendGoGit {
+			t.Setenv("PATH", t.TempDir())
+		}
+
+		manager := NewManager(filepath.Join(dir, "work"))
+		manager.SetBackend(newTestBackend(t, name, filepath.Join(dir, "work")))
+		ctx := context.Background()
+
+		dates, err := manager.FileDates(ctx)
+		if err != nil {
+			t.Fatalf("FileDates failed: %v", err)
+		}
+		first := dates["first.md"]
+		if len(dates) != 1 || first.Created.Unix() != 1700000000 || first.Updated.Unix() != 1700200000 {
+			t.Errorf("Expected dates for work/first.md only, got %+v", dates)
+		}
+
+		status, err := manager.GetStatus(ctx)
+		if err != nil {
+			t.Fatalf("GetStatus failed: %v", err)
+		}
+		large, err := manager.LargeFiles(ctx, status, 1024)
+		if err != nil {
+			t.Fatalf("LargeFiles failed: %v", err)
+		}
+		if len(large) != 1 || large[0].Path != filepath.Join("assets", "big.psd") {
+			t.Errorf("Expected only the file without an LFS attribute, got %+v", large)
+		}
+
+		if size, err := manager.RepositorySize(ctx); err != nil || size <= 0 {
+			t.Errorf("Expected a repository size, got %d, %v", size, err)
+		}
+
+		if name == BackendGoGit {
+			if manager.LFSAvailable(ctx) {
+				t.Error("Expected Git LFS to be unavailable with the go-git backend")
+			}
+			if _, err := manager.WordDiff(ctx, "first.md", "HEAD"); !errors.Is(err, ErrGitNotFound) {
+				t.Errorf("Expected commands without a go-git implementation to need the git binary, got %v", err)
+			}
+		}
+	})
+}
+
+func TestBackendStatusDetectsStagedRenames(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, name string) {
+		dir := setupGitRepo(t)
+		backend := newTestBackend(t, name, dir)
+		ctx := context.Background()
+
+		os.WriteFile(filepath.Join(dir, "draft.md"), []byte("# draft\n\nA fairly long body so git detects the rename.\n"), 0644)
+		os.WriteFile(filepath.Join(dir, "gone.md"), []byte("# gone\n"), 0644)
+		commitAt(t, dir, "initial", 1700000000)
+
+		os.Rename(filepath.Join(dir, "draft.md"), filepath.Join(dir, "final.md"))
+		os.Remove(filepath.Join(dir, "gone.md"))
+		os.WriteFile(filepath.Join(dir, "new.md"), []byte("# new\n"), 0644)
+		if err := backend.Add(ctx, []string{"draft.md", "final.md", "gone.md", "new.md"}); err != nil {
+			t.Fatalf("Add failed: %v", err)
+		}
+
+		status, err := backend.Status(ctx)
+		if err != nil {
+			t.Fatalf("Status failed: %v", err)
+		}
+		if len(status) != 3 {
+			t.Fatalf("Expected a rename, a deletion and an addition, got %+v", status)
+		}
+
+		renamed, ok := findEntry(status, "final.md")
+		if !ok || renamed.Index != 'R' || renamed.OrigPath != "draft.md" {
+			t.Errorf("Expected final.md to be a rename of draft.md, got %+v", status)
+		}
+		if deleted, ok := findEntry(status, "gone.md"); !ok || deleted.Index != 'D' {
+			t.Errorf("Expected gone.md to stay a deletion, got %+v", status)
+		}
+		if added, ok := findEntry(status, "new.md"); !ok || added.Index != 'A' {
+			t.Errorf("Expected new.md to stay an addition, got %+v", status)
+		}
+	})
+}
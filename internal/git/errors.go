@@ -0,0 +1,90 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrPushRejected = errors.New("push rejected by remote")
+	ErrAuthRequired = errors.New("authentication required")
+	ErrNoUpstream   = errors.New("no upstream branch configured")
+	ErrNotARepo     = errors.New("not a git repository")
+	ErrTimeout      = errors.New("git operation timed out")
+	ErrGitNotFound  = errors.New("requires the git binary, which was not found in PATH")
+)
+
+type Error struct {
+	Args   []string
+	Stderr string
+	Kind   error
+	Err    error
+}
+
+func (e *Error) Error() string {
+	op := "git"
+	if len(e.Args) > 0 {
+		op += " " + e.Args[0]
+	}
+
+	msg := fmt.Sprintf("%s failed: %v", op, e.Err)
+	if e.Kind != nil {
+		msg = fmt.Sprintf("%s failed: %v", op, e.Kind)
+	}
+	if e.Stderr != "" {
+		msg += ": " + e.Stderr
+	}
+
+	return msg
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func (e *Error) Is(target error) bool {
+	return e.Kind != nil && e.Kind == target
+}
+
+func newError(ctx context.Context, args []string, stderr string, err error) *Error {
+	gitErr := &Error{Args: args, Stderr: strings.TrimSpace(stderr), Err: err}
+
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		gitErr.Kind = ErrTimeout
+		gitErr.Err = ctx.Err()
+	case ctx.Err() != nil:
+		gitErr.Err = ctx.Err()
+	default:
+		gitErr.Kind = classifyStderr(stderr)
+	}
+
+	return gitErr
+}
+
+func classifyStderr(stderr string) error {
+	lower := strings.ToLower(stderr)
+
+	switch {
+	case strings.Contains(lower, "not a git repository"):
+		return ErrNotARepo
+	case strings.Contains(lower, "terminal prompts disabled"),
+		strings.Contains(lower, "could not read username"),
+		strings.Contains(lower, "could not read password"),
+		strings.Contains(lower, "authentication failed"),
+		strings.Contains(lower, "permission denied (publickey"):
+		return ErrAuthRequired
+	case strings.Contains(lower, "no tracking information"),
+		strings.Contains(lower, "has no upstream branch"),
+		strings.Contains(lower, "no upstream configured"),
+		strings.Contains(lower, "no configured push destination"):
+		return ErrNoUpstream
+	case strings.Contains(lower, "[rejected]"),
+		strings.Contains(lower, "non-fast-forward"):
+		return ErrPushRejected
+	}
+
+	return nil
+}
@@ -0,0 +1,133 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestClassifyStderr(t *testing.T) {
+	tests := []struct {
+		stderr   string
+		expected error
+	}{
+		{"fatal: not a git repository (or any of the parent directories): .git", ErrNotARepo},
+		{"fatal: could not read Username for 'https://example.com': terminal prompts disabled", ErrAuthRequired},
+		{"remote: HTTP Basic: Access denied\nfatal: Authentication failed for 'https://example.com/notes.git/'", ErrAuthRequired},
+		{"git@example.com: Permission denied (publickey).\nfatal: Could not read from remote repository.", ErrAuthRequired},
+		{"There is no tracking information for the current branch.", ErrNoUpstream},
+		{"fatal: The current branch main has no upstream branch.", ErrNoUpstream},
+		{" ! [rejected]        main -> main (fetch first)", ErrPushRejected},
+		{"fatal: '/tmp/missing' does not appear to be a git repository", nil},
+		{"", nil},
+	}
+
+	for _, test := range tests {
+		if got := classifyStderr(test.stderr); got != test.expected {
+			t.Errorf("classifyStderr(%q) = %v, expected %v", test.stderr, got, test.expected)
+		}
+	}
+}
+
+func TestErrorIsAndMessage(t *testing.T) {
+	err := &Error{Args: []string{"pull", "--rebase"}, Stderr: "fatal: Authentication failed", Kind: ErrAuthRequired, Err: errors.New("exit status 128")}
+
+	if !errors.Is(err, ErrAuthRequired) {
+		t.Error("Expected the error to match ErrAuthRequired")
+	}
+	if errors.Is(err, ErrNoUpstream) {
+		t.Error("Expected the error not to match ErrNoUpstream")
+	}
+
+	expected := "git pull failed: authentication required: fatal: Authentication failed"
+	if err.Error() != expected {
+		t.Errorf("Expected %q, got %q", expected, err.Error())
+	}
+
+	var gitErr *Error
+	if !errors.As(errors.Join(errors.New("sync stopped"), err), &gitErr) || gitErr.Stderr != "fatal: Authentication failed" {
+		t.Error("Expected to unwrap the git error with its stderr")
+	}
+}
+
+func TestManagerNotARepo(t *testing.T) {
+	manager := NewManager(t.TempDir())
+
+	_, err := manager.ResolveRef(context.Background(), "HEAD")
+	if !errors.Is(err, ErrNotARepo) {
+		t.Errorf("Expected ErrNotARepo, got %v", err)
+	}
+}
+
+func TestManagerNoUpstream(t *testing.T) {
+	tempDir := setupGitRepo(t)
+	os.WriteFile(filepath.Join(tempDir, "first.md"), []byte("first"), 0644)
+	commitAt(t, tempDir, "initial", 1700000000)
+
+	manager := NewManager(tempDir)
+
+	if _, err := manager.Pull(context.Background()); !errors.Is(err, ErrNoUpstream) {
+		t.Errorf("Expected ErrNoUpstream from pull, got %v", err)
+	}
+
+	runGit(t, tempDir, "remote", "add", "origin", t.TempDir())
+	if _, err := manager.Push(context.Background()); !errors.Is(err, ErrNoUpstream) {
+		t.Errorf("Expected ErrNoUpstream from push, got %v", err)
+	}
+}
+
+func TestManagerTimeout(t *testing.T) {
+	manager := NewManager(setupGitRepo(t))
+	manager.SetTimeout(time.Nanosecond)
+
+	_, err := manager.GetStatus(context.Background())
+	if !errors.Is(err, ErrTimeout) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected ErrTimeout, got %v", err)
+	}
+
+	manager.SetTimeout(0)
+	if _, err := manager.GetStatus(context.Background()); err != nil {
+		t.Errorf("Expected no timeout once disabled, got %v", err)
+	}
+}
+
+func TestManagerCancelled(t *testing.T) {
+	manager := NewManager(setupGitRepo(t))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := manager.GetStatus(ctx)
+	if !errors.Is(err, context.Canceled) || errors.Is(err, ErrTimeout) {
+		t.Errorf("Expected a cancellation error, got %v", err)
+	}
+}
+
+func TestExecGitDisablesPrompts(t *testing.T) {
+	defer SetTerminalPrompts(terminalPrompts)
+	dir := setupGitRepo(t)
+	args := []string{"-c", "alias.prompt=!echo ${GIT_TERMINAL_PROMPT-unset}", "prompt"}
+
+	SetTerminalPrompts(false)
+	output, err := execGit(context.Background(), dir, nil, args...)
+	if err != nil {
+		t.Fatalf("execGit failed: %v", err)
+	}
+	if string(output) != "0\n" {
+		t.Errorf("Expected GIT_TERMINAL_PROMPT=0 without a terminal, got %q", output)
+	}
+
+	t.Setenv("GIT_TERMINAL_PROMPT", "")
+	os.Unsetenv("GIT_TERMINAL_PROMPT")
+	SetTerminalPrompts(true)
+	output, err = execGit(context.Background(), dir, nil, args...)
+	if err != nil {
+		t.Fatalf("execGit failed: %v", err)
+	}
+	if string(output) != "unset\n" {
+		t.Errorf("Expected git to be allowed to prompt in a terminal, got %q", output)
+	}
+}
@@ -0,0 +1,381 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type execBackend struct {
+	workingDir string
+}
+
+func newExecBackend(workingDir string) *execBackend {
+	return &execBackend{workingDir: workingDir}
+}
+
+func (b *execBackend) git(ctx context.Context, args ...string) ([]byte, error) {
+	return execGit(ctx, b.workingDir, nil, args...)
+}
+
+func (b *execBackend) IsRepo(ctx context.Context) bool {
+	_, err := b.git(ctx, "rev-parse", "--git-dir")
+	return err == nil
+}
+
+func (b *execBackend) Status(ctx context.Context) ([]StatusEntry, error) {
+	output, err := b.git(ctx, "status", "--porcelain=v2", "-z", "--untracked-files=all")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get git status: %w", err)
+	}
+
+	entries, err := parseStatus(string(output))
+	if err != nil {
+		return nil, err
+	}
+
+	prefix, err := b.git(ctx, "rev-parse", "--show-prefix")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get git status: %w", err)
+	}
+
+	if prefix := filepath.FromSlash(strings.TrimSuffix(strings.TrimSpace(string(prefix)), "/")); prefix != "" {
+		for i := range entries {
+			entries[i].Path = workingPath(prefix, filepath.ToSlash(entries[i].Path))
+			if entries[i].OrigPath != "" {
+				entries[i].OrigPath = workingPath(prefix, filepath.ToSlash(entries[i].OrigPath))
+			}
+		}
+	}
+
+	return entries, nil
+}
+
+func (b *execBackend) Add(ctx context.Context, paths []string) error {
+	if len(paths) == 0 {
+		return nil
+	}
+
+	if _, err := b.git(ctx, append([]string{"add", "-A", "--"}, paths...)...); err != nil {
+		return fmt.Errorf("failed to stage files: %w", err)
+	}
+
+	return nil
+}
+
+func (b *execBackend) Commit(ctx context.Context, message string, paths []string) error {
+	args := []string{"commit", "-m", message}
+	if len(paths) > 0 {
+		args = append(append(args, "--"), paths...)
+	}
+
+	if _, err := b.git(ctx, args...); err != nil {
+		return fmt.Errorf("failed to commit: %w", err)
+	}
+
+	return nil
+}
+
+func (b *execBackend) Pull(ctx context.Context, strategy string) (string, error) {
+	args := []string{"pull", "--autostash"}
+	switch strategy {
+	case StrategyMerge:
+		args = append(args, "--no-rebase")
+	case StrategyRebase:
+		args = append(args, "--rebase")
+	}
+
+	output, err := b.git(ctx, args...)
+	if err != nil {
+		return string(output), fmt.Errorf("pull failed: %w", err)
+	}
+
+	return string(output), nil
+}
+
+func (b *execBackend) Push(ctx context.Context) (string, error) {
+	output, err := b.git(ctx, "push")
+	if err != nil {
+		return string(output), fmt.Errorf("push failed: %w", err)
+	}
+
+	return string(output), nil
+}
+
+func (b *execBackend) Diff(ctx context.Context, from, to string) (ChangeSummary, error) {
+	var summary ChangeSummary
+
+	output, err := b.git(ctx, "diff", "--name-status", "--relative", "-z", "-M", from, to)
+	if err != nil {
+		return summary, fmt.Errorf("failed to diff %s..%s: %w", from, to, err)
+	}
+
+	fields := strings.Split(string(output), "\x00")
+	for i := 0; i+1 < len(fields); i++ {
+		status := fields[i]
+		if status == "" {
+			continue
+		}
+
+		change := FileChange{Status: status[0]}
+		if change.Status == 'R' || change.Status == 'C' {
+			if i+2 >= len(fields) {
+				break
+			}
+			change.OrigPath = filepath.FromSlash(fields[i+1])
+			i++
+		}
+		i++
+		change.Path = filepath.FromSlash(fields[i])
+
+		summary.Changes = append(summary.Changes, change)
+	}
+
+	output, err = b.git(ctx, "log", "--no-merges", "--format=%an", from+".."+to)
+	if err != nil {
+		return summary, fmt.Errorf("failed to list authors: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	for _, author := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if author != "" && !seen[author] {
+			seen[author] = true
+			summary.Authors = append(summary.Authors, author)
+		}
+	}
+	sort.Strings(summary.Authors)
+
+	return summary, nil
+}
+
+func (b *execBackend) Log(ctx context.Context, path string) ([]Commit, error) {
+	head, err := b.ResolveRef(ctx, "HEAD")
+	if err != nil || head == "" {
+		return nil, err
+	}
+
+	output, err := b.git(ctx, "log", "--follow", "--relative", "--format=%x1e"+commitFormat, "--name-only", "-z", "--", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history of %s: %w", path, err)
+	}
+
+	var commits []Commit
+
+	for _, entry := range strings.Split(string(output), "\x1e") {
+		fields := strings.Split(entry, "\x00")
+		commit, ok := parseCommitHeader(fields[0])
+		if !ok {
+			continue
+		}
+		commit.Path = path
+
+		for _, name := range fields[1:] {
+			if name = strings.TrimPrefix(name, "\n"); name != "" {
+				commit.Path = filepath.FromSlash(name)
+				break
+			}
+		}
+
+		commits = append(commits, commit)
+	}
+
+	return commits, nil
+}
+
+func (b *execBackend) Show(ctx context.Context, rev, path string) ([]byte, error) {
+	output, err := b.git(ctx, "cat-file", "blob", revisionPath(rev, path))
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%s does not exist at %s", path, rev)
+	}
+
+	return output, nil
+}
+
+func (b *execBackend) ResolveRef(ctx context.Context, ref string) (string, error) {
+	output, err := b.git(ctx, "rev-parse", "--verify", "--quiet", ref)
+	if err != nil {
+		if exitCode(err) == 1 && !errors.Is(err, ErrNotARepo) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to resolve %s: %w", ref, err)
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+func (b *execBackend) UpdateRef(ctx context.Context, ref, rev string) error {
+	if _, err := b.git(ctx, "update-ref", ref, rev); err != nil {
+		return fmt.Errorf("failed to update %s: %w", ref, err)
+	}
+
+	return nil
+}
+
+func (b *execBackend) DeleteRef(ctx context.Context, ref string) error {
+	if _, err := b.git(ctx, "update-ref", "-d", ref); err != nil {
+		return fmt.Errorf("failed to delete %s: %w", ref, err)
+	}
+
+	return nil
+}
+
+func (b *execBackend) ResetKeep(ctx context.Context, rev string) error {
+	if _, err := b.git(ctx, "reset", "--keep", rev); err != nil {
+		return fmt.Errorf("failed to reset to %s: %w", rev, err)
+	}
+
+	return nil
+}
+
+func (b *execBackend) State(ctx context.Context) (string, error) {
+	for _, candidate := range []struct {
+		name  string
+		state string
+	}{
+		{"rebase-merge", StateRebase},
+		{"rebase-apply", StateRebase},
+		{"MERGE_HEAD", StateMerge},
+	} {
+		path, err := gitPath(ctx, b.workingDir, candidate.name)
+		if err != nil {
+			return StateNone, err
+		}
+
+		if _, err := os.Stat(path); err == nil {
+			return candidate.state, nil
+		}
+	}
+
+	return StateNone, nil
+}
+
+func (b *execBackend) TopLevel(ctx context.Context) (string, error) {
+	output, err := b.git(ctx, "rev-parse", "--show-toplevel")
+	if err != nil {
+		if errors.Is(err, ErrNotARepo) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to find the repository root: %w", err)
+	}
+
+	return filepath.FromSlash(strings.TrimSpace(string(output))), nil
+}
+
+func (b *execBackend) FileDates(ctx context.Context) (map[string]FileDates, error) {
+	dates := make(map[string]FileDates)
+
+	head, err := b.ResolveRef(ctx, "HEAD")
+	if err != nil || head == "" {
+		return dates, err
+	}
+
+	output, err := b.git(ctx, "log", "--format=%x1e%ct", "--name-only", "--relative", "-z")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read git log: %w", err)
+	}
+
+	for _, entry := range strings.Split(string(output), "\x1e") {
+		fields := strings.Split(entry, "\x00")
+		if len(fields) < 2 {
+			continue
+		}
+
+		timestamp, err := strconv.ParseInt(strings.TrimSpace(fields[0]), 10, 64)
+		if err != nil {
+			continue
+		}
+		commitTime := time.Unix(timestamp, 0)
+
+		for _, name := range fields[1:] {
+			name = strings.TrimPrefix(name, "\n")
+			if name == "" {
+				continue
+			}
+
+			path := filepath.FromSlash(name)
+			fileDates, seen := dates[path]
+			if !seen || commitTime.Before(fileDates.Created) {
+				fileDates.Created = commitTime
+			}
+			if !seen || commitTime.After(fileDates.Updated) {
+				fileDates.Updated = commitTime
+			}
+			dates[path] = fileDates
+		}
+	}
+
+	return dates, nil
+}
+
+func (b *execBackend) LFSAvailable(ctx context.Context) bool {
+	_, err := b.git(ctx, "lfs", "version")
+	return err == nil
+}
+
+func (b *execBackend) LFSTracked(ctx context.Context, paths []string) (map[string]bool, error) {
+	tracked := make(map[string]bool)
+
+	output, err := b.git(ctx, append([]string{"check-attr", "-z", "filter", "--"}, paths...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read attributes: %w", err)
+	}
+
+	fields := strings.Split(string(output), "\x00")
+	for i := 0; i+2 < len(fields); i += 3 {
+		if fields[i+2] == "lfs" {
+			tracked[filepath.FromSlash(fields[i])] = true
+		}
+	}
+
+	return tracked, nil
+}
+
+func (b *execBackend) RepositorySize(ctx context.Context) (int64, error) {
+	output, err := b.git(ctx, "count-objects", "-v")
+	if err != nil {
+		return 0, fmt.Errorf("failed to measure repository: %w", err)
+	}
+
+	var size int64
+	for _, line := range strings.Split(string(output), "\n") {
+		key, value, ok := strings.Cut(line, ": ")
+		if !ok || (key != "size" && key != "size-pack") {
+			continue
+		}
+
+		kib, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("failed to parse %s: %w", key, err)
+		}
+		size += kib * 1024
+	}
+
+	return size, nil
+}
+
+func revisionPath(rev, path string) string {
+	return rev + ":./" + filepath.ToSlash(path)
+}
+
+func gitPath(ctx context.Context, dir, name string) (string, error) {
+	output, err := execGit(ctx, dir, nil, "rev-parse", "--git-path", name)
+	if err != nil {
+		return "", fmt.Errorf("failed to locate %s: %w", name, err)
+	}
+
+	path := strings.TrimSpace(string(output))
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+
+	return path, nil
+}
@@ -1,99 +1,323 @@
 package git
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"os"
 	"os/exec"
+	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 )
 
+const (
+	StrategyMerge  = "merge"
+	StrategyRebase = "rebase"
+)
+
+const (
+	StageOurs   = 2
+	StageTheirs = 3
+)
+
+const (
+	StateNone   = ""
+	StateMerge  = "merge"
+	StateRebase = "rebase"
+)
+
+const PrePullRef = "refs/gitnote/pre-pull"
+
+const commitFormat = "%H%x1f%an%x1f%at%x1f%s"
+
 type Manager struct {
 	workingDir string
+	backend    Backend
+	timeout    time.Duration
+}
+
+type StatusEntry struct {
+	Index    byte
+	Worktree byte
+	Path     string
+	OrigPath string
+}
+
+type FileChange struct {
+	Status   byte
+	Path     string
+	OrigPath string
+}
+
+type ChangeSummary struct {
+	Changes []FileChange
+	Authors []string
+}
+
+type Commit struct {
+	Hash    string
+	Author  string
+	Date    time.Time
+	Subject string
+	Path    string
+}
+
+type FileDates struct {
+	Created time.Time
+	Updated time.Time
 }
 
 func NewManager(workingDir string) *Manager {
 	if workingDir == "" {
 		workingDir = "."
 	}
-	return &Manager{workingDir: workingDir}
+	return &Manager{workingDir: workingDir, backend: newExecBackend(workingDir)}
 }
 
-func (g *Manager) IsGitRepo() bool {
-	cmd := exec.Command("git", "rev-parse", "--git-dir")
-	cmd.Dir = g.workingDir
-	return cmd.Run() == nil
+func (g *Manager) SetBackend(backend Backend) {
+	g.backend = backend
 }
 
-func (g *Manager) GetStatus() ([]string, error) {
-	cmd := exec.Command("git", "status", "--porcelain")
-	cmd.Dir = g.workingDir
+func (g *Manager) SetTimeout(timeout time.Duration) {
+	g.timeout = timeout
+}
+
+func (g *Manager) git(ctx context.Context, args ...string) ([]byte, error) {
+	ctx, cancel := withTimeout(ctx, g.timeout)
+	defer cancel()
 	
-	output, err := cmd.Output()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get git status: %w", err)
+	return execGit(ctx, g.workingDir, nil, args...)
+}
+
+func (g *Manager) IsGitRepo(ctx context.Context) bool {
+	ctx, cancel := withTimeout(ctx, g.timeout)
+	defer cancel()
+	
+	return g.backend.IsRepo(ctx)
+}
+
+func (g *Manager) GetStatus(ctx context.Context) ([]StatusEntry, error) {
+	ctx, cancel := withTimeout(ctx, g.timeout)
+	defer cancel()
+	
+	return g.backend.Status(ctx)
+}
+
+func (g *Manager) AddFiles(ctx context.Context, files []string) error {
+	ctx, cancel := withTimeout(ctx, g.timeout)
+	defer cancel()
+	
+	return g.backend.Add(ctx, files)
+}
+
+func (g *Manager) AddAll(ctx context.Context, paths []string) error {
+	ctx, cancel := withTimeout(ctx, g.timeout)
+	defer cancel()
+	
+	return g.backend.Add(ctx, paths)
+}
+
+func (g *Manager) Commit(ctx context.Context, message string) error {
+	ctx, cancel := withTimeout(ctx, g.timeout)
+	defer cancel()
+	
+	return g.backend.Commit(ctx, message, nil)
+}
+
+func (g *Manager) CommitPaths(ctx context.Context, message string, paths []string) error {
+	ctx, cancel := withTimeout(ctx, g.timeout)
+	defer cancel()
+	
+	return g.backend.Commit(ctx, message, paths)
+}
+
+func (g *Manager) Amend(ctx context.Context, message string, paths []string) error {
+	args := []string{"commit", "--amend"}
+	if message == "" {
+		args = append(args, "--no-edit")
+	} else {
+		args = append(args, "-m", message)
+	}
+	if len(paths) > 0 {
+		args = append(append(args, "--"), paths...)
 	}
 	
-	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
-	if len(lines) == 1 && lines[0] == "" {
-		return []string{}, nil
+	if _, err := g.git(ctx, args...); err != nil {
+		return fmt.Errorf("failed to amend commit: %w", err)
 	}
 	
-	return lines, nil
+	return nil
 }
 
-func (g *Manager) AddFiles(files []string) error {
-	if len(files) == 0 {
-		return nil
+func (g *Manager) SavePrePull(ctx context.Context) error {
+	head, err := g.Head(ctx)
+	if err != nil || head == "" {
+		return err
 	}
 	
-	args := append([]string{"add"}, files...)
-	cmd := exec.Command("git", args...)
-	cmd.Dir = g.workingDir
+	return g.UpdateRef(ctx, PrePullRef, head)
+}
+
+func (g *Manager) Pull(ctx context.Context) (string, error) {
+	ctx, cancel := withTimeout(ctx, g.timeout)
+	defer cancel()
 	
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to add files: %w", err)
+	return g.backend.Pull(ctx, "")
+}
+
+func (g *Manager) PullWith(ctx context.Context, strategy string) (string, error) {
+	ctx, cancel := withTimeout(ctx, g.timeout)
+	defer cancel()
+	
+	return g.backend.Pull(ctx, strategy)
+}
+
+func (g *Manager) Push(ctx context.Context) (string, error) {
+	ctx, cancel := withTimeout(ctx, g.timeout)
+	defer cancel()
+	
+	return g.backend.Push(ctx)
+}
+
+func (g *Manager) Head(ctx context.Context) (string, error) {
+	return g.ResolveRef(ctx, "HEAD")
+}
+
+func (g *Manager) Changes(ctx context.Context, from, to string) (ChangeSummary, error) {
+	ctx, cancel := withTimeout(ctx, g.timeout)
+	defer cancel()
+	
+	return g.backend.Diff(ctx, from, to)
+}
+
+func (g *Manager) History(ctx context.Context, path string) ([]Commit, error) {
+	ctx, cancel := withTimeout(ctx, g.timeout)
+	defer cancel()
+	
+	return g.backend.Log(ctx, path)
+}
+
+func (g *Manager) DeletedFiles(ctx context.Context, limit int) ([]Commit, error) {
+	head, err := g.Head(ctx)
+	if err != nil || head == "" {
+		return nil, err
 	}
 	
-	return nil
+	args := []string{"log", "--format=%x1e" + commitFormat, "--name-status", "--find-renames", "--relative", "-z"}
+	if limit > 0 {
+		args = append(args, fmt.Sprintf("-n%d", limit))
+	}
+	
+	output, err := g.git(ctx, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read git log: %w", err)
+	}
+	
+	var deleted []Commit
+	
+	for _, entry := range strings.Split(string(output), "\x1e") {
+		fields := strings.Split(entry, "\x00")
+		commit, ok := parseCommitHeader(fields[0])
+		if !ok {
+			continue
+		}
+		
+		for i := 1; i+1 < len(fields); i += 2 {
+			status := strings.TrimPrefix(fields[i], "\n")
+			path := filepath.FromSlash(fields[i+1])
+			
+			if strings.HasPrefix(status, "R") || strings.HasPrefix(status, "C") {
+				if i+2 >= len(fields) {
+					break
+				}
+				target := filepath.FromSlash(fields[i+2])
+				i++
+				if status[0] == 'C' {
+					continue
+				}
+				if _, err := os.Stat(filepath.Join(g.workingDir, target)); err == nil {
+					continue
+				}
+			} else if status != "D" {
+				continue
+			}
+			
+			deletion := commit
+			deletion.Path = path
+			deleted = append(deleted, deletion)
+		}
+	}
+	
+	return deleted, nil
 }
 
-func (g *Manager) Commit(message string) error {
-	cmd := exec.Command("git", "commit", "-m", message)
-	cmd.Dir = g.workingDir
+func (g *Manager) LastVersion(ctx context.Context, path string) (string, error) {
+	output, err := g.git(ctx, "rev-list", "-n", "1", "HEAD", "--", path)
+	if err != nil {
+		return "", fmt.Errorf("failed to find %s in history: %w", path, err)
+	}
 	
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to commit: %w", err)
+	rev := strings.TrimSpace(string(output))
+	if rev == "" {
+		return "", fmt.Errorf("%s was never committed", path)
 	}
 	
-	return nil
+	if _, err := g.git(ctx, "cat-file", "-e", revisionPath(rev, path)); err != nil {
+		rev += "^"
+	}
+	
+	return rev, nil
 }
 
-func (g *Manager) Pull() (string, error) {
-	cmd := exec.Command("git", "pull")
-	cmd.Dir = g.workingDir
+func (g *Manager) WordDiff(ctx context.Context, path, rev string) (string, error) {
+	if rev == "" {
+		rev = "HEAD"
+	}
 	
-	output, err := cmd.CombinedOutput()
+	output, err := g.git(ctx, "diff", "--no-color", "--word-diff=plain", rev, "--", path)
 	if err != nil {
-		return string(output), fmt.Errorf("pull failed: %w", err)
+		return "", fmt.Errorf("failed to diff %s: %w", path, err)
 	}
 	
 	return string(output), nil
 }
 
-func (g *Manager) HasMergeConflicts() (bool, error) {
-	cmd := exec.Command("git", "status", "--porcelain")
-	cmd.Dir = g.workingDir
+func (g *Manager) Show(ctx context.Context, rev, path string) ([]byte, error) {
+	ctx, cancel := withTimeout(ctx, g.timeout)
+	defer cancel()
+	
+	return g.backend.Show(ctx, rev, path)
+}
+
+func parseCommitHeader(header string) (Commit, bool) {
+	parts := strings.SplitN(strings.TrimSpace(header), "\x1f", 4)
+	if len(parts) != 4 {
+		return Commit{}, false
+	}
 	
-	output, err := cmd.Output()
+	timestamp, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		return Commit{}, false
+	}
+	
+	return Commit{
+		Hash:    parts[0],
+		Author:  parts[1],
+		Date:    time.Unix(timestamp, 0),
+		Subject: parts[3],
+	}, true
+}
+
+func (g *Manager) HasMergeConflicts(ctx context.Context) (bool, error) {
+	status, err := g.GetStatus(ctx)
 	if err != nil {
 		return false, fmt.Errorf("failed to check merge conflicts: %w", err)
 	}
 	
-	lines := strings.Split(string(output), "\n")
-	for _, line := range lines {
-		if strings.HasPrefix(line, "UU ") || strings.HasPrefix(line, "AA ") || 
-		   strings.HasPrefix(line, "DD ") || strings.HasPrefix(line, "AU ") ||
-		   strings.HasPrefix(line, "UA ") || strings.HasPrefix(line, "DU ") ||
-		   strings.HasPrefix(line, "UD ") {
+	for _, entry := range status {
+		if entry.IsConflicted() {
 			return true, nil
 		}
 	}
@@ -101,13 +325,187 @@ func (g *Manager) HasMergeConflicts() (bool, error) {
 	return false, nil
 }
 
-func (g *Manager) Reset() error {
-	cmd := exec.Command("git", "reset", "--hard", "HEAD")
+func (g *Manager) State(ctx context.Context) (string, error) {
+	ctx, cancel := withTimeout(ctx, g.timeout)
+	defer cancel()
+	
+	return g.backend.State(ctx)
+}
+
+func (g *Manager) Continue(ctx context.Context) error {
+	state, err := g.State(ctx)
+	if err != nil {
+		return err
+	}
+	
+	var args []string
+	switch state {
+	case StateMerge:
+		args = []string{"commit", "--no-edit"}
+	case StateRebase:
+		args = []string{"rebase", "--continue"}
+	default:
+		return fmt.Errorf("no merge or rebase in progress")
+	}
+	
+	ctx, cancel := withTimeout(ctx, g.timeout)
+	defer cancel()
+	
+	if _, err := execGit(ctx, g.workingDir, []string{"GIT_EDITOR=true"}, args...); err != nil {
+		return fmt.Errorf("failed to continue %s: %w", state, err)
+	}
+	
+	return nil
+}
+
+func (g *Manager) ShowStage(ctx context.Context, path string, stage int) ([]byte, bool, error) {
+	output, err := g.git(ctx, "ls-files", "-u", "-z", "--", path)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to list conflict stages: %w", err)
+	}
+	
+	found := false
+	for _, record := range strings.Split(string(output), "\x00") {
+		fields := strings.Fields(strings.SplitN(record, "\t", 2)[0])
+		if len(fields) == 3 && fields[2] == strconv.Itoa(stage) {
+			found = true
+		}
+	}
+	
+	if !found {
+		return nil, false, nil
+	}
+	
+	content, err := g.git(ctx, "cat-file", "blob", revisionPath(fmt.Sprintf(":%d", stage), path))
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read stage %d of %s: %w", stage, path, err)
+	}
+	
+	return content, true, nil
+}
+
+func (g *Manager) MergeTool(ctx context.Context, path, tool string) error {
+	args := []string{"mergetool", "--no-prompt"}
+	if tool != "" {
+		args = append(args, "--tool="+tool)
+	}
+	args = append(args, "--", path)
+	
+	cmd := exec.CommandContext(ctx, "git", args...)
+	if errors.Is(cmd.Err, exec.ErrNotFound) {
+		return fmt.Errorf("git mergetool %w", ErrGitNotFound)
+	}
 	cmd.Dir = g.workingDir
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
 	
 	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to reset: %w", err)
+		return fmt.Errorf("merge tool failed: %w", err)
 	}
 	
 	return nil
+}
+
+func (g *Manager) Init(ctx context.Context) error {
+	if _, err := g.git(ctx, "init"); err != nil {
+		return fmt.Errorf("failed to initialise repository: %w", err)
+	}
+	
+	return nil
+}
+
+func (g *Manager) Clone(ctx context.Context, url string) error {
+	if _, err := g.git(ctx, "clone", url, "."); err != nil {
+		return fmt.Errorf("failed to clone %s: %w", url, err)
+	}
+	
+	return nil
+}
+
+func (g *Manager) InstallHook(ctx context.Context, name, script string) (bool, error) {
+	hooksDir, err := gitPath(ctx, g.workingDir, "hooks")
+	if err != nil {
+		return false, err
+	}
+	
+	hookPath := filepath.Join(hooksDir, name)
+	if _, err := os.Stat(hookPath); err == nil {
+		return false, nil
+	}
+	
+	if err := os.MkdirAll(hooksDir, 0755); err != nil {
+		return false, fmt.Errorf("failed to create hooks directory: %w", err)
+	}
+	
+	if err := os.WriteFile(hookPath, []byte(script), 0755); err != nil {
+		return false, fmt.Errorf("failed to install %s hook: %w", name, err)
+	}
+	
+	return true, nil
+}
+
+func (g *Manager) TopLevel(ctx context.Context) (string, error) {
+	ctx, cancel := withTimeout(ctx, g.timeout)
+	defer cancel()
+	
+	return g.backend.TopLevel(ctx)
+}
+
+func (g *Manager) Abort(ctx context.Context) error {
+	state, err := g.State(ctx)
+	if err != nil {
+		return err
+	}
+	
+	var args []string
+	switch state {
+	case StateMerge:
+		args = []string{"merge", "--abort"}
+	case StateRebase:
+		args = []string{"rebase", "--abort"}
+	default:
+		return fmt.Errorf("no merge or rebase in progress")
+	}
+	
+	if _, err := g.git(ctx, args...); err != nil {
+		return fmt.Errorf("failed to abort %s: %w", state, err)
+	}
+	
+	return nil
+}
+
+func (g *Manager) UpdateRef(ctx context.Context, ref, rev string) error {
+	ctx, cancel := withTimeout(ctx, g.timeout)
+	defer cancel()
+	
+	return g.backend.UpdateRef(ctx, ref, rev)
+}
+
+func (g *Manager) ResolveRef(ctx context.Context, ref string) (string, error) {
+	ctx, cancel := withTimeout(ctx, g.timeout)
+	defer cancel()
+	
+	return g.backend.ResolveRef(ctx, ref)
+}
+
+func (g *Manager) DeleteRef(ctx context.Context, ref string) error {
+	ctx, cancel := withTimeout(ctx, g.timeout)
+	defer cancel()
+	
+	return g.backend.DeleteRef(ctx, ref)
+}
+
+func (g *Manager) ResetKeep(ctx context.Context, rev string) error {
+	ctx, cancel := withTimeout(ctx, g.timeout)
+	defer cancel()
+	
+	return g.backend.ResetKeep(ctx, rev)
+}
+
+func (g *Manager) FileDates(ctx context.Context) (map[string]FileDates, error) {
+	ctx, cancel := withTimeout(ctx, g.timeout)
+	defer cancel()
+	
+	return g.backend.FileDates(ctx)
 }
\ No newline at end of file
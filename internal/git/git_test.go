@@ -1,6 +1,9 @@
 package git
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -48,14 +51,14 @@ func TestIsGitRepo(t *testing.T) {
 	tempDir := setupGitRepo(t)
 	manager := NewManager(tempDir)
 	
-	if !manager.IsGitRepo() {
+	if !manager.IsGitRepo(context.Background()) {
 		t.Error("Expected directory to be recognized as git repo")
 	}
 	
 	nonGitDir := t.TempDir()
 	manager = NewManager(nonGitDir)
 	
-	if manager.IsGitRepo() {
+	if manager.IsGitRepo(context.Background()) {
 		t.Error("Expected directory to not be recognized as git repo")
 	}
 }
@@ -64,7 +67,7 @@ func TestGetStatus(t *testing.T) {
 	tempDir := setupGitRepo(t)
 	manager := NewManager(tempDir)
 	
-	status, err := manager.GetStatus()
+	status, err := manager.GetStatus(context.Background())
 	if err != nil {
 		t.Fatalf("GetStatus failed: %v", err)
 	}
@@ -76,7 +79,7 @@ func TestGetStatus(t *testing.T) {
 	testFile := filepath.Join(tempDir, "test.md")
 	os.WriteFile(testFile, []byte("test content"), 0644)
 	
-	status, err = manager.GetStatus()
+	status, err = manager.GetStatus(context.Background())
 	if err != nil {
 		t.Fatalf("GetStatus failed after adding file: %v", err)
 	}
@@ -85,8 +88,8 @@ func TestGetStatus(t *testing.T) {
 		t.Fatalf("Expected 1 item in status, got %d", len(status))
 	}
 	
-	if !strings.Contains(status[0], "test.md") {
-		t.Errorf("Expected status to contain test.md, got %s", status[0])
+	if status[0].Path != "test.md" || !status[0].IsUntracked() {
+		t.Errorf("Expected untracked test.md, got %s", status[0])
 	}
 }
 
@@ -97,11 +100,11 @@ func TestAddFiles(t *testing.T) {
 	testFile := filepath.Join(tempDir, "test.md")
 	os.WriteFile(testFile, []byte("test content"), 0644)
 	
-	if err := manager.AddFiles([]string{"test.md"}); err != nil {
+	if err := manager.AddFiles(context.Background(), []string{"test.md"}); err != nil {
 		t.Fatalf("AddFiles failed: %v", err)
 	}
 	
-	status, err := manager.GetStatus()
+	status, err := manager.GetStatus(context.Background())
 	if err != nil {
 		t.Fatalf("GetStatus failed: %v", err)
 	}
@@ -110,7 +113,7 @@ func TestAddFiles(t *testing.T) {
 		t.Fatalf("Expected 1 item in status after add, got %d", len(status))
 	}
 	
-	if !strings.HasPrefix(status[0], "A ") {
+	if status[0].Index != 'A' || status[0].Worktree != ' ' {
 		t.Errorf("Expected file to be staged (A), got status: %s", status[0])
 	}
 }
@@ -122,15 +125,15 @@ func TestCommit(t *testing.T) {
 	testFile := filepath.Join(tempDir, "test.md")
 	os.WriteFile(testFile, []byte("test content"), 0644)
 	
-	if err := manager.AddFiles([]string{"test.md"}); err != nil {
+	if err := manager.AddFiles(context.Background(), []string{"test.md"}); err != nil {
 		t.Fatalf("AddFiles failed: %v", err)
 	}
 	
-	if err := manager.Commit("Test commit"); err != nil {
+	if err := manager.Commit(context.Background(), "Test commit"); err != nil {
 		t.Fatalf("Commit failed: %v", err)
 	}
 	
-	status, err := manager.GetStatus()
+	status, err := manager.GetStatus(context.Background())
 	if err != nil {
 		t.Fatalf("GetStatus failed: %v", err)
 	}
@@ -140,20 +143,89 @@ func TestCommit(t *testing.T) {
 	}
 }
 
+func TestCommitPathsAndAmend(t *testing.T) {
+	tempDir := setupGitRepo(t)
+	manager := NewManager(tempDir)
+	
+	os.WriteFile(filepath.Join(tempDir, "a.md"), []byte("a"), 0644)
+	os.WriteFile(filepath.Join(tempDir, "b.md"), []byte("b"), 0644)
+	manager.AddFiles(context.Background(), []string{"a.md", "b.md"})
+	
+	if err := manager.CommitPaths(context.Background(), "Add a", []string{"a.md"}); err != nil {
+		t.Fatalf("CommitPaths failed: %v", err)
+	}
+	
+	status, _ := manager.GetStatus(context.Background())
+	if len(status) != 1 || status[0].Path != "b.md" || status[0].Index != 'A' {
+		t.Fatalf("Expected b.md to stay staged, got %v", status)
+	}
+	
+	if err := manager.Amend(context.Background(), "Add a and b", []string{"b.md"}); err != nil {
+		t.Fatalf("Amend failed: %v", err)
+	}
+	
+	if err := manager.Amend(context.Background(), "", nil); err != nil {
+		t.Fatalf("Amend without message failed: %v", err)
+	}
+	
+	cmd := exec.Command("git", "log", "--format=%s")
+	cmd.Dir = tempDir
+	output, _ := cmd.Output()
+	if string(output) != "Add a and b\n" {
+		t.Errorf("Expected a single amended commit, got %q", string(output))
+	}
+	
+	status, _ = manager.GetStatus(context.Background())
+	if len(status) != 0 {
+		t.Errorf("Expected clean status after amend, got %v", status)
+	}
+}
+
 func TestAddFilesEmpty(t *testing.T) {
 	tempDir := setupGitRepo(t)
 	manager := NewManager(tempDir)
 	
-	if err := manager.AddFiles([]string{}); err != nil {
+	if err := manager.AddFiles(context.Background(), []string{}); err != nil {
 		t.Errorf("AddFiles with empty slice should not fail: %v", err)
 	}
 }
 
+func TestAddAll(t *testing.T) {
+	tempDir := setupGitRepo(t)
+	manager := NewManager(tempDir)
+	
+	os.WriteFile(filepath.Join(tempDir, "keep.md"), []byte("keep"), 0644)
+	os.WriteFile(filepath.Join(tempDir, "gone.md"), []byte("gone"), 0644)
+	commitAt(t, tempDir, "initial", 1700000000)
+	
+	os.Remove(filepath.Join(tempDir, "gone.md"))
+	os.WriteFile(filepath.Join(tempDir, "keep.md"), []byte("changed"), 0644)
+	os.WriteFile(filepath.Join(tempDir, "other.txt"), []byte("other"), 0644)
+	
+	if err := manager.AddAll(context.Background(), []string{"gone.md", "keep.md"}); err != nil {
+		t.Fatalf("AddAll failed: %v", err)
+	}
+	
+	status, err := manager.GetStatus(context.Background())
+	if err != nil {
+		t.Fatalf("GetStatus failed: %v", err)
+	}
+	
+	staged := make(map[string]byte)
+	for _, entry := range status {
+		staged[entry.Path] = entry.Index
+	}
+	
+	if staged["gone.md"] != 'D' || staged["keep.md"] != 'M' || staged["other.txt"] != '?' {
+		t.Errorf("Expected only the given paths to be staged, got %v", status)
+	}
+}
+
 func TestHasMergeConflicts(t *testing.T) {
 	tempDir := setupGitRepo(t)
 	manager := NewManager(tempDir)
 	
-	hasConflicts, err := manager.HasMergeConflicts()
+	hasConflicts, err := manager.HasMergeConflicts(context.Background())
 	if err != nil {
 		t.Fatalf("HasMergeConflicts failed: %v", err)
 	}
@@ -161,4 +233,461 @@ func TestHasMergeConflicts(t *testing.T) {
 	if hasConflicts {
 		t.Error("Expected no merge conflicts in clean repo")
 	}
+}
+
+func commitAt(t *testing.T, dir, message string, timestamp int64) {
+	cmd := exec.Command("git", "add", "-A")
+	cmd.Dir = dir
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("Failed to add files: %v", err)
+	}
+	
+	date := fmt.Sprintf("@%d +0000", timestamp)
+	cmd = exec.Command("git", "commit", "-m", message)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GIT_AUTHOR_DATE="+date, "GIT_COMMITTER_DATE="+date)
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("Failed to commit: %v", err)
+	}
+}
+
+func TestFileDates(t *testing.T) {
+	tempDir := setupGitRepo(t)
+	manager := NewManager(tempDir)
+	
+	dates, err := manager.FileDates(context.Background())
+	if err != nil {
+		t.Fatalf("FileDates failed on empty repo: %v", err)
+	}
+	
+	if len(dates) != 0 {
+		t.Errorf("Expected no dates for empty repo, got %d", len(dates))
+	}
+	
+	os.Mkdir(filepath.Join(tempDir, "work"), 0755)
+	os.WriteFile(filepath.Join(tempDir, "work", "2025-01-01 first note.md"), []byte("v1"), 0644)
+	commitAt(t, tempDir, "Add first note", 1700000000)
+	
+	os.WriteFile(filepath.Join(tempDir, "second.md"), []byte("v1"), 0644)
+	commitAt(t, tempDir, "Add second note", 1700100000)
+	
+	os.WriteFile(filepath.Join(tempDir, "work", "2025-01-01 first note.md"), []byte("v2"), 0644)
+	commitAt(t, tempDir, "Update first note", 1700200000)
+	
+	dates, err = manager.FileDates(context.Background())
+	if err != nil {
+		t.Fatalf("FileDates failed: %v", err)
+	}
+	
+	first := dates[filepath.Join("work", "2025-01-01 first note.md")]
+	if first.Created.Unix() != 1700000000 || first.Updated.Unix() != 1700200000 {
+		t.Errorf("Unexpected dates for first note: %+v", first)
+	}
+	
+	second := dates["second.md"]
+	if second.Created.Unix() != 1700100000 || second.Updated.Unix() != 1700100000 {
+		t.Errorf("Unexpected dates for second note: %+v", second)
+	}
+	
+	relative, err := NewManager(filepath.Join(tempDir, "work")).FileDates(context.Background())
+	if err != nil {
+		t.Fatalf("FileDates failed in subdirectory: %v", err)
+	}
+	
+	if _, ok := relative["2025-01-01 first note.md"]; !ok || len(relative) != 1 {
+		t.Errorf("Expected paths relative to the working directory, got %v", relative)
+	}
+}
+
+func runGit(t *testing.T, dir string, args ...string) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v failed: %v\n%s", args, err, output)
+	}
+}
+
+func setupClones(t *testing.T) (string, string) {
+	remote := t.TempDir()
+	runGit(t, remote, "init", "--bare")
+	
+	local := setupGitRepo(t)
+	os.WriteFile(filepath.Join(local, "first.md"), []byte("first"), 0644)
+	commitAt(t, local, "initial", 1700000000)
+	runGit(t, local, "remote", "add", "origin", remote)
+	runGit(t, local, "push", "-u", "origin", "HEAD")
+	
+	other := t.TempDir()
+	runGit(t, other, "clone", remote, ".")
+	runGit(t, other, "config", "user.email", "other@example.com")
+	runGit(t, other, "config", "user.name", "Other User")
+	
+	return local, other
+}
+
+func TestPullWithAndPush(t *testing.T) {
+	local, other := setupClones(t)
+	manager := NewManager(local)
+	otherManager := NewManager(other)
+	
+	os.WriteFile(filepath.Join(other, "second.md"), []byte("second"), 0644)
+	commitAt(t, other, "second", 1700000100)
+	if _, err := otherManager.Push(context.Background()); err != nil {
+		t.Fatalf("Push failed: %v", err)
+	}
+	
+	os.WriteFile(filepath.Join(local, "third.md"), []byte("third"), 0644)
+	commitAt(t, local, "third", 1700000200)
+	
+	if _, err := manager.Push(context.Background()); !errors.Is(err, ErrPushRejected) {
+		t.Fatalf("Expected ErrPushRejected, got %v", err)
+	}
+	
+	before, err := manager.Head(context.Background())
+	if err != nil || before == "" {
+		t.Fatalf("Head failed: %q %v", before, err)
+	}
+	
+	if output, err := manager.PullWith(context.Background(), StrategyRebase); err != nil {
+		t.Fatalf("PullWith failed: %v\n%s", err, output)
+	}
+	
+	after, _ := manager.Head(context.Background())
+	summary, err := manager.Changes(context.Background(), before, after)
+	if err != nil {
+		t.Fatalf("Changes failed: %v", err)
+	}
+	
+	if len(summary.Changes) != 1 || summary.Changes[0].Path != "second.md" || summary.Changes[0].Status != 'A' {
+		t.Errorf("Expected second.md to be added, got %v", summary.Changes)
+	}
+	
+	if len(summary.Authors) != 2 || summary.Authors[0] != "Other User" {
+		t.Errorf("Expected both authors of rebased commits, got %v", summary.Authors)
+	}
+	
+	if _, err := manager.Push(context.Background()); err != nil {
+		t.Errorf("Push after pull failed: %v", err)
+	}
+}
+
+func TestHeadEmptyRepo(t *testing.T) {
+	head, err := NewManager(setupGitRepo(t)).Head(context.Background())
+	if err != nil || head != "" {
+		t.Errorf("Expected empty head for new repo, got %q %v", head, err)
+	}
+}
+
+func TestStateAndContinue(t *testing.T) {
+	tempDir := setupGitRepo(t)
+	manager := NewManager(tempDir)
+	
+	os.WriteFile(filepath.Join(tempDir, "readme.md"), []byte("base\n"), 0644)
+	commitAt(t, tempDir, "base", 1700000000)
+	runGit(t, tempDir, "checkout", "-b", "other")
+	os.WriteFile(filepath.Join(tempDir, "readme.md"), []byte("other\n"), 0644)
+	commitAt(t, tempDir, "other", 1700000100)
+	runGit(t, tempDir, "checkout", "-")
+	os.WriteFile(filepath.Join(tempDir, "readme.md"), []byte("main\n"), 0644)
+	commitAt(t, tempDir, "main", 1700000200)
+	
+	if state, err := manager.State(context.Background()); err != nil || state != StateNone {
+		t.Fatalf("Expected no state before merge, got %q %v", state, err)
+	}
+	
+	cmd := exec.Command("git", "merge", "other")
+	cmd.Dir = tempDir
+	if err := cmd.Run(); err == nil {
+		t.Fatal("Expected merge to conflict")
+	}
+	
+	if state, err := manager.State(context.Background()); err != nil || state != StateMerge {
+		t.Fatalf("Expected merge state, got %q %v", state, err)
+	}
+	
+	ours, exists, err := manager.ShowStage(context.Background(), "readme.md", StageOurs)
+	if err != nil || !exists || string(ours) != "main\n" {
+		t.Errorf("Expected our stage to be 'main', got %q %v %v", string(ours), exists, err)
+	}
+	
+	theirs, _, _ := manager.ShowStage(context.Background(), "readme.md", StageTheirs)
+	if string(theirs) != "other\n" {
+		t.Errorf("Expected their stage to be 'other', got %q", string(theirs))
+	}
+	
+	if _, exists, _ := manager.ShowStage(context.Background(), "missing.md", StageOurs); exists {
+		t.Error("Expected no stage for a path without conflicts")
+	}
+	
+	os.WriteFile(filepath.Join(tempDir, "readme.md"), []byte("resolved\n"), 0644)
+	manager.AddAll(context.Background(), []string{"readme.md"})
+	
+	if err := manager.Continue(context.Background()); err != nil {
+		t.Fatalf("Continue failed: %v", err)
+	}
+	
+	if state, _ := manager.State(context.Background()); state != StateNone {
+		t.Errorf("Expected merge to be finished, got %q", state)
+	}
+	
+	if err := manager.Continue(context.Background()); err == nil {
+		t.Error("Expected error when nothing is in progress")
+	}
+}
+
+func TestAbortRestoresLocalChanges(t *testing.T) {
+	local, other := setupClones(t)
+	manager := NewManager(local)
+	
+	os.WriteFile(filepath.Join(other, "first.md"), []byte("theirs"), 0644)
+	commitAt(t, other, "theirs", 1700000100)
+	runGit(t, other, "push")
+	
+	os.WriteFile(filepath.Join(local, "first.md"), []byte("mine"), 0644)
+	commitAt(t, local, "mine", 1700000200)
+	os.WriteFile(filepath.Join(local, "draft.md"), []byte("work in progress"), 0644)
+	runGit(t, local, "add", "draft.md")
+	runGit(t, local, "config", "pull.rebase", "false")
+	
+	if err := manager.SavePrePull(context.Background()); err != nil {
+		t.Fatalf("SavePrePull failed: %v", err)
+	}
+	before, _ := manager.Head(context.Background())
+	
+	if _, err := manager.Pull(context.Background()); err == nil {
+		t.Fatal("Expected pull to conflict")
+	}
+	
+	if err := manager.Abort(context.Background()); err != nil {
+		t.Fatalf("Abort failed: %v", err)
+	}
+	
+	content, err := os.ReadFile(filepath.Join(local, "draft.md"))
+	if err != nil || string(content) != "work in progress" {
+		t.Errorf("Expected local changes to survive abort, got %q %v", string(content), err)
+	}
+	
+	if after, _ := manager.Head(context.Background()); after != before {
+		t.Errorf("Expected HEAD to be unchanged after abort")
+	}
+	
+	if prePull, _ := manager.ResolveRef(context.Background(), PrePullRef); prePull != before {
+		t.Errorf("Expected pre-pull ref %s, got %s", before, prePull)
+	}
+	
+	if err := manager.Abort(context.Background()); err == nil {
+		t.Error("Expected error when nothing is in progress")
+	}
+}
+
+func TestChangesRenamesAndDeletions(t *testing.T) {
+	tempDir := setupGitRepo(t)
+	manager := NewManager(tempDir)
+	
+	os.Mkdir(filepath.Join(tempDir, "work"), 0755)
+	os.WriteFile(filepath.Join(tempDir, "work", "old name.md"), []byte("a body long enough to be detected as a rename\n"), 0644)
+	os.WriteFile(filepath.Join(tempDir, "doomed.md"), []byte("bye\n"), 0644)
+	os.WriteFile(filepath.Join(tempDir, "edited.md"), []byte("v1\n"), 0644)
+	commitAt(t, tempDir, "initial", 1700000000)
+	before, _ := manager.Head(context.Background())
+	
+	runGit(t, tempDir, "mv", filepath.Join("work", "old name.md"), filepath.Join("work", "new name.md"))
+	os.Remove(filepath.Join(tempDir, "doomed.md"))
+	os.WriteFile(filepath.Join(tempDir, "edited.md"), []byte("v2\n"), 0644)
+	commitAt(t, tempDir, "changes", 1700000100)
+	after, _ := manager.Head(context.Background())
+	
+	summary, err := manager.Changes(context.Background(), before, after)
+	if err != nil {
+		t.Fatalf("Changes failed: %v", err)
+	}
+	
+	expected := []FileChange{
+		{Status: 'D', Path: "doomed.md"},
+		{Status: 'M', Path: "edited.md"},
+		{Status: 'R', Path: filepath.Join("work", "new name.md"), OrigPath: filepath.Join("work", "old name.md")},
+	}
+	
+	if fmt.Sprint(summary.Changes) != fmt.Sprint(expected) {
+		t.Errorf("Expected changes %v, got %v", expected, summary.Changes)
+	}
+	
+	if len(summary.Authors) != 1 || summary.Authors[0] != "Test User" {
+		t.Errorf("Expected a single author, got %v", summary.Authors)
+	}
+}
+
+func TestHistoryWordDiffAndShow(t *testing.T) {
+	tempDir := setupGitRepo(t)
+	manager := NewManager(tempDir)
+	
+	if history, err := manager.History(context.Background(), "idea.md"); err != nil || len(history) != 0 {
+		t.Fatalf("Expected empty history in new repo, got %v %v", history, err)
+	}
+	
+	os.WriteFile(filepath.Join(tempDir, "draft.md"), []byte("the quick brown fox jumps over the lazy dog\n"), 0644)
+	commitAt(t, tempDir, "Add draft", 1700000000)
+	runGit(t, tempDir, "mv", "draft.md", "idea.md")
+	commitAt(t, tempDir, "Rename draft", 1700000100)
+	os.WriteFile(filepath.Join(tempDir, "idea.md"), []byte("the quick red fox jumps over the lazy dog\n"), 0644)
+	commitAt(t, tempDir, "Edit idea", 1700000200)
+	
+	history, err := manager.History(context.Background(), "idea.md")
+	if err != nil {
+		t.Fatalf("History failed: %v", err)
+	}
+	
+	if len(history) != 3 {
+		t.Fatalf("Expected 3 commits, got %d", len(history))
+	}
+	
+	if history[0].Subject != "Edit idea" || history[0].Author != "Test User" || history[0].Date.Unix() != 1700000200 {
+		t.Errorf("Unexpected latest commit: %+v", history[0])
+	}
+	
+	if history[2].Path != "draft.md" || history[1].Path != "idea.md" {
+		t.Errorf("Expected history to follow the rename, got %+v", history)
+	}
+	
+	os.WriteFile(filepath.Join(tempDir, "idea.md"), []byte("the quick red cat jumps over the lazy dog\n"), 0644)
+	
+	diff, err := manager.WordDiff(context.Background(), "idea.md", "")
+	if err != nil {
+		t.Fatalf("WordDiff failed: %v", err)
+	}
+	
+	if !strings.Contains(diff, "[-fox-]{+cat+}") {
+		t.Errorf("Expected word diff against HEAD, got %q", diff)
+	}
+	
+	diff, _ = manager.WordDiff(context.Background(), "idea.md", "HEAD~1")
+	if !strings.Contains(diff, "[-brown fox-]{+red cat+}") {
+		t.Errorf("Expected word diff against HEAD~1, got %q", diff)
+	}
+	
+	content, err := manager.Show(context.Background(), history[2].Hash, "draft.md")
+	if err != nil || string(content) != "the quick brown fox jumps over the lazy dog\n" {
+		t.Errorf("Unexpected old content: %q %v", string(content), err)
+	}
+	
+	if _, err := manager.Show(context.Background(), "HEAD", "draft.md"); err == nil {
+		t.Error("Expected error for a path missing at the revision")
+	}
+}
+
+func TestDeletedFilesAndLastVersion(t *testing.T) {
+	tempDir := setupGitRepo(t)
+	manager := NewManager(tempDir)
+	
+	if deleted, err := manager.DeletedFiles(context.Background(), 10); err != nil || len(deleted) != 0 {
+		t.Fatalf("Expected no deletions in new repo, got %v %v", deleted, err)
+	}
+	
+	os.Mkdir(filepath.Join(tempDir, "work"), 0755)
+	os.WriteFile(filepath.Join(tempDir, "work", "gone.md"), []byte("v1\n"), 0644)
+	os.WriteFile(filepath.Join(tempDir, "kept.md"), []byte("v1\n"), 0644)
+	commitAt(t, tempDir, "Add notes", 1700000000)
+	os.WriteFile(filepath.Join(tempDir, "work", "gone.md"), []byte("v2\n"), 0644)
+	commitAt(t, tempDir, "Edit gone", 1700000100)
+	os.Remove(filepath.Join(tempDir, "work", "gone.md"))
+	commitAt(t, tempDir, "Delete gone", 1700000200)
+	os.WriteFile(filepath.Join(tempDir, "moved.md"), []byte("a note that will be renamed\n"), 0644)
+	commitAt(t, tempDir, "Add moved", 1700000250)
+	os.Rename(filepath.Join(tempDir, "moved.md"), filepath.Join(tempDir, "renamed.md"))
+	commitAt(t, tempDir, "Rename moved", 1700000260)
+	os.WriteFile(filepath.Join(tempDir, "kept.md"), []byte("v2\n"), 0644)
+	commitAt(t, tempDir, "Edit kept", 1700000300)
+	
+	deleted, err := manager.DeletedFiles(context.Background(), 10)
+	if err != nil {
+		t.Fatalf("DeletedFiles failed: %v", err)
+	}
+	
+	if len(deleted) != 1 || deleted[0].Path != filepath.Join("work", "gone.md") || deleted[0].Subject != "Delete gone" {
+		t.Fatalf("Unexpected deletions: %+v", deleted)
+	}
+	
+	os.Remove(filepath.Join(tempDir, "renamed.md"))
+	if deleted, _ := manager.DeletedFiles(context.Background(), 10); len(deleted) != 2 || deleted[0].Path != "moved.md" {
+		t.Errorf("Expected the rename source to count once its target is gone, got %+v", deleted)
+	}
+	
+	if deleted, _ := manager.DeletedFiles(context.Background(), 1); len(deleted) != 0 {
+		t.Errorf("Expected deletion outside the last commit to be skipped, got %v", deleted)
+	}
+	
+	rev, err := manager.LastVersion(context.Background(), filepath.Join("work", "gone.md"))
+	if err != nil {
+		t.Fatalf("LastVersion failed: %v", err)
+	}
+	
+	content, err := manager.Show(context.Background(), rev, filepath.Join("work", "gone.md"))
+	if err != nil || string(content) != "v2\n" {
+		t.Errorf("Expected last version v2, got %q %v", string(content), err)
+	}
+	
+	rev, _ = manager.LastVersion(context.Background(), "kept.md")
+	if content, _ := manager.Show(context.Background(), rev, "kept.md"); string(content) != "v2\n" {
+		t.Errorf("Expected last version of existing note, got %q", string(content))
+	}
+	
+	if _, err := manager.LastVersion(context.Background(), "never.md"); err == nil {
+		t.Error("Expected error for a note that was never committed")
+	}
+}
+
+func TestTopLevel(t *testing.T) {
+	tempDir := setupGitRepo(t)
+	root, _ := filepath.EvalSymlinks(tempDir)
+	os.MkdirAll(filepath.Join(tempDir, "work"), 0755)
+	
+	top, err := NewManager(filepath.Join(tempDir, "work")).TopLevel(context.Background())
+	if err != nil {
+		t.Fatalf("TopLevel failed: %v", err)
+	}
+	if top != root {
+		t.Errorf("Expected %s, got %s", root, top)
+	}
+	
+	top, err = NewManager(t.TempDir()).TopLevel(context.Background())
+	if err != nil || top != "" {
+		t.Errorf("Expected no toplevel outside a repository, got %q, %v", top, err)
+	}
+}
+
+func TestInitCloneAndInstallHook(t *testing.T) {
+	tempDir := t.TempDir()
+	manager := NewManager(tempDir)
+	
+	if err := manager.Init(context.Background()); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	if !manager.IsGitRepo(context.Background()) {
+		t.Fatal("Expected a repository after Init")
+	}
+	
+	installed, err := manager.InstallHook(context.Background(), "pre-commit", "#!/bin/sh\nexit 0\n")
+	if err != nil || !installed {
+		t.Fatalf("Expected the hook to be installed, got %v, %v", installed, err)
+	}
+	
+	info, err := os.Stat(filepath.Join(tempDir, ".git", "hooks", "pre-commit"))
+	if err != nil || info.Mode().Perm()&0100 == 0 {
+		t.Errorf("Expected an executable pre-commit hook, got %v, %v", info, err)
+	}
+	
+	installed, err = manager.InstallHook(context.Background(), "pre-commit", "#!/bin/sh\nexit 1\n")
+	if err != nil || installed {
+		t.Errorf("Expected an existing hook to be left alone, got %v, %v", installed, err)
+	}
+	
+	local, _ := setupClones(t)
+	clone := t.TempDir()
+	
+	if err := NewManager(clone).Clone(context.Background(), filepath.Join(local, ".git")); err != nil {
+		t.Fatalf("Clone failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(clone, "first.md")); err != nil {
+		t.Errorf("Expected first.md in the clone: %v", err)
+	}
 }
\ No newline at end of file
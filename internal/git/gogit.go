@@ -0,0 +1,932 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	gogit "github.com/go-git/go-git/v5"
+	gogitconfig "github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/format/gitattributes"
+	"github.com/go-git/go-git/v5/plumbing/format/index"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/storage/filesystem"
+	"github.com/go-git/go-git/v5/utils/merkletrie"
+)
+
+var (
+	errNonFastForward    = errors.New("the local and remote branches have diverged; the go-git backend can only fast-forward, use the exec backend to merge or rebase")
+	errRebaseUnsupported = errors.New("the go-git backend cannot rebase; use the merge strategy or the exec backend")
+	errLocalChanges      = errors.New("local changes would be overwritten; the go-git backend cannot stash them, commit them first or use the exec backend")
+)
+
+type goGitBackend struct {
+	workingDir string
+}
+
+func newGoGitBackend(workingDir string) *goGitBackend {
+	return &goGitBackend{workingDir: workingDir}
+}
+
+func (b *goGitBackend) open(ctx context.Context) (*gogit.Repository, *gogit.Worktree, error) {
+	repo, err := gogit.PlainOpenWithOptions(b.workingDir, &gogit.PlainOpenOptions{DetectDotGit: true})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open repository: %w", goGitError(ctx, "open", err))
+	}
+
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open worktree: %w", err)
+	}
+
+	return repo, worktree, nil
+}
+
+func (b *goGitBackend) prefix(worktree *gogit.Worktree) string {
+	root, err := filepath.EvalSymlinks(worktree.Filesystem.Root())
+	if err != nil {
+		return ""
+	}
+	dir, err := filepath.Abs(b.workingDir)
+	if err != nil {
+		return ""
+	}
+	if dir, err = filepath.EvalSymlinks(dir); err != nil {
+		return ""
+	}
+
+	rel, err := filepath.Rel(root, dir)
+	if err != nil || rel == "." {
+		return ""
+	}
+	return rel
+}
+
+func (b *goGitBackend) IsRepo(ctx context.Context) bool {
+	_, err := gogit.PlainOpenWithOptions(b.workingDir, &gogit.PlainOpenOptions{DetectDotGit: true})
+	return err == nil
+}
+
+func (b *goGitBackend) Status(ctx context.Context) ([]StatusEntry, error) {
+	repo, worktree, err := b.open(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := worktree.Status()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get git status: %w", err)
+	}
+
+	if err := detectRenames(repo, status); err != nil {
+		return nil, err
+	}
+
+	prefix := b.prefix(worktree)
+
+	var entries []StatusEntry
+	for path, fileStatus := range status {
+		if fileStatus.Staging == gogit.Unmodified && fileStatus.Worktree == gogit.Unmodified {
+			continue
+		}
+
+		entry := StatusEntry{Index: byte(fileStatus.Staging), Worktree: byte(fileStatus.Worktree), Path: workingPath(prefix, path)}
+		if fileStatus.Staging == gogit.Renamed && fileStatus.Extra != "" {
+			entry.OrigPath = workingPath(prefix, fileStatus.Extra)
+		}
+		entries = append(entries, entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Path < entries[j].Path
+	})
+
+	return entries, nil
+}
+
+func detectRenames(repo *gogit.Repository, status gogit.Status) error {
+	var added, deleted []string
+	for path, fileStatus := range status {
+		switch {
+		case fileStatus.Staging == gogit.Added:
+			added = append(added, path)
+		case fileStatus.Staging == gogit.Deleted && fileStatus.Worktree == gogit.Unmodified:
+			deleted = append(deleted, path)
+		}
+	}
+	if len(added) == 0 || len(deleted) == 0 {
+		return nil
+	}
+	sort.Strings(added)
+	sort.Strings(deleted)
+
+	head, err := resolveCommit(repo, "HEAD")
+	if err != nil {
+		return err
+	}
+	idx, err := repo.Storer.Index()
+	if err != nil {
+		return fmt.Errorf("failed to read the index: %w", err)
+	}
+
+	sources := make(map[plumbing.Hash][]string)
+	for _, path := range deleted {
+		hash, err := blobHash(head, path)
+		if err != nil {
+			return err
+		}
+		if !hash.IsZero() {
+			sources[hash] = append(sources[hash], path)
+		}
+	}
+
+	for _, path := range added {
+		entry, err := idx.Entry(path)
+		if err != nil {
+			continue
+		}
+		candidates := sources[entry.Hash]
+		if len(candidates) == 0 {
+			continue
+		}
+
+		status[path].Staging = gogit.Renamed
+		status[path].Extra = candidates[0]
+		delete(status, candidates[0])
+		sources[entry.Hash] = candidates[1:]
+	}
+
+	return nil
+}
+
+func (b *goGitBackend) Add(ctx context.Context, paths []string) error {
+	_, worktree, err := b.open(ctx)
+	if err != nil {
+		return err
+	}
+
+	prefix := b.prefix(worktree)
+
+	for _, path := range paths {
+		if _, err := os.Stat(filepath.Join(b.workingDir, path)); err == nil {
+			if _, err := worktree.Add(repoPath(prefix, path)); err != nil {
+				return fmt.Errorf("failed to stage %s: %w", path, err)
+			}
+			continue
+		}
+
+		if _, err := worktree.Remove(repoPath(prefix, path)); err != nil && !errors.Is(err, index.ErrEntryNotFound) {
+			return fmt.Errorf("failed to stage removal of %s: %w", path, err)
+		}
+	}
+
+	return nil
+}
+
+func (b *goGitBackend) Commit(ctx context.Context, message string, paths []string) error {
+	repo, worktree, err := b.open(ctx)
+	if err != nil {
+		return err
+	}
+
+	var unselected []string
+	if len(paths) > 0 {
+		if err := b.Add(ctx, paths); err != nil {
+			return err
+		}
+
+		unselected, err = b.stagedOutside(ctx, paths)
+		if err != nil {
+			return err
+		}
+
+		if len(unselected) > 0 {
+			head, err := repo.Head()
+			if err != nil {
+				return fmt.Errorf("failed to commit: only committing some paths needs an existing commit: %w", err)
+			}
+			files := make([]string, len(unselected))
+			for i, path := range unselected {
+				files[i] = repoPath(b.prefix(worktree), path)
+			}
+			if err := worktree.Reset(&gogit.ResetOptions{Commit: head.Hash(), Mode: gogit.MixedReset, Files: files}); err != nil {
+				return fmt.Errorf("failed to unstage other files: %w", err)
+			}
+		}
+	}
+
+	_, err = worktree.Commit(message, &gogit.CommitOptions{})
+	if len(unselected) > 0 {
+		if addErr := b.Add(ctx, unselected); addErr != nil && err == nil {
+			err = addErr
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to commit: %w", err)
+	}
+
+	return nil
+}
+
+func (b *goGitBackend) stagedOutside(ctx context.Context, paths []string) ([]string, error) {
+	selected := make(map[string]bool)
+	for _, path := range paths {
+		selected[filepath.Clean(path)] = true
+	}
+
+	status, err := b.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var staged []string
+	for _, entry := range status {
+		if !entry.IsStaged() || selected[entry.Path] {
+			continue
+		}
+		staged = append(staged, entry.Path)
+		if entry.OrigPath != "" && !selected[entry.OrigPath] {
+			staged = append(staged, entry.OrigPath)
+		}
+	}
+
+	return staged, nil
+}
+
+func (b *goGitBackend) Pull(ctx context.Context, strategy string) (string, error) {
+	if strategy == StrategyRebase {
+		return "", fmt.Errorf("pull failed: %w", errRebaseUnsupported)
+	}
+
+	_, worktree, err := b.open(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	err = worktree.PullContext(ctx, &gogit.PullOptions{})
+	switch {
+	case err == nil:
+		return "", nil
+	case errors.Is(err, gogit.NoErrAlreadyUpToDate):
+		return "Already up to date.\n", nil
+	case errors.Is(err, gogit.ErrNonFastForwardUpdate):
+		return "", fmt.Errorf("pull failed: %w", errNonFastForward)
+	case errors.Is(err, gogit.ErrUnstagedChanges):
+		return "", fmt.Errorf("pull failed: %w", errLocalChanges)
+	}
+
+	return "", fmt.Errorf("pull failed: %w", goGitError(ctx, "pull", err))
+}
+
+func (b *goGitBackend) Push(ctx context.Context) (string, error) {
+	repo, _, err := b.open(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		return "", fmt.Errorf("push failed: %w", err)
+	}
+
+	refSpec := gogitconfig.RefSpec(fmt.Sprintf("%s:%s", head.Name(), head.Name()))
+	err = repo.PushContext(ctx, &gogit.PushOptions{RefSpecs: []gogitconfig.RefSpec{refSpec}})
+	switch {
+	case err == nil:
+		return "", nil
+	case errors.Is(err, gogit.NoErrAlreadyUpToDate):
+		return "Everything up-to-date\n", nil
+	}
+
+	return "", fmt.Errorf("push failed: %w", goGitError(ctx, "push", err))
+}
+
+func (b *goGitBackend) Log(ctx context.Context, path string) ([]Commit, error) {
+	repo, worktree, err := b.open(ctx)
+	if err != nil {
+		return nil, err
+	}
+	prefix := b.prefix(worktree)
+
+	head, err := repo.Head()
+	if err != nil {
+		return nil, nil
+	}
+
+	start, err := repo.CommitObject(head.Hash())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history of %s: %w", path, err)
+	}
+
+	type pending struct {
+		commit *object.Commit
+		name   string
+	}
+
+	queue := []pending{{start, repoPath(prefix, path)}}
+	seen := make(map[plumbing.Hash]bool)
+	var commits []Commit
+
+	for len(queue) > 0 {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		latest := 0
+		for i := range queue {
+			if queue[i].commit.Committer.When.After(queue[latest].commit.Committer.When) {
+				latest = i
+			}
+		}
+		current := queue[latest]
+		queue = append(queue[:latest], queue[latest+1:]...)
+
+		if seen[current.commit.Hash] {
+			continue
+		}
+		seen[current.commit.Hash] = true
+
+		blob, err := blobHash(current.commit, current.name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read history of %s: %w", path, err)
+		}
+
+		parents, err := parentCommits(current.commit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read history of %s: %w", path, err)
+		}
+
+		changed := true
+		var next []pending
+		for _, parent := range parents {
+			parentBlob, err := blobHash(parent, current.name)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read history of %s: %w", path, err)
+			}
+
+			if parentBlob == blob {
+				changed = false
+				next = []pending{{parent, current.name}}
+				break
+			}
+
+			name := current.name
+			if parentBlob.IsZero() {
+				if name, err = renamedFrom(ctx, parent, current.commit, current.name); err != nil {
+					return nil, fmt.Errorf("failed to read history of %s: %w", path, err)
+				}
+			}
+			next = append(next, pending{parent, name})
+		}
+
+		if changed && (!blob.IsZero() || len(parents) > 0) {
+			commits = append(commits, Commit{
+				Hash:    current.commit.Hash.String(),
+				Author:  current.commit.Author.Name,
+				Date:    current.commit.Author.When,
+				Subject: strings.SplitN(current.commit.Message, "\n", 2)[0],
+				Path:    workingPath(prefix, current.name),
+			})
+		}
+
+		queue = append(queue, next...)
+	}
+
+	return commits, nil
+}
+
+func parentCommits(commit *object.Commit) ([]*object.Commit, error) {
+	var parents []*object.Commit
+	err := commit.Parents().ForEach(func(parent *object.Commit) error {
+		parents = append(parents, parent)
+		return nil
+	})
+	return parents, err
+}
+
+func blobHash(commit *object.Commit, name string) (plumbing.Hash, error) {
+	tree, err := commit.Tree()
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+
+	entry, err := tree.FindEntry(name)
+	if errors.Is(err, object.ErrEntryNotFound) || errors.Is(err, object.ErrDirectoryNotFound) {
+		return plumbing.ZeroHash, nil
+	}
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+
+	return entry.Hash, nil
+}
+
+func renamedFrom(ctx context.Context, parent, commit *object.Commit, name string) (string, error) {
+	fromTree, err := parent.Tree()
+	if err != nil {
+		return "", err
+	}
+	toTree, err := commit.Tree()
+	if err != nil {
+		return "", err
+	}
+
+	changes, err := object.DiffTreeWithOptions(ctx, fromTree, toTree, object.DefaultDiffTreeOptions)
+	if err != nil {
+		return "", err
+	}
+
+	for _, change := range changes {
+		if change.To.Name == name && change.From.Name != "" {
+			return change.From.Name, nil
+		}
+	}
+
+	return name, nil
+}
+
+func (b *goGitBackend) Diff(ctx context.Context, from, to string) (ChangeSummary, error) {
+	var summary ChangeSummary
+
+	repo, worktree, err := b.open(ctx)
+	if err != nil {
+		return summary, err
+	}
+	prefix := b.prefix(worktree)
+
+	fromCommit, err := resolveCommit(repo, from)
+	if err != nil {
+		return summary, err
+	}
+	toCommit, err := resolveCommit(repo, to)
+	if err != nil {
+		return summary, err
+	}
+
+	fromTree, err := fromCommit.Tree()
+	if err != nil {
+		return summary, fmt.Errorf("failed to read tree of %s: %w", from, err)
+	}
+	toTree, err := toCommit.Tree()
+	if err != nil {
+		return summary, fmt.Errorf("failed to read tree of %s: %w", to, err)
+	}
+
+	changes, err := object.DiffTreeWithOptions(ctx, fromTree, toTree, object.DefaultDiffTreeOptions)
+	if err != nil {
+		return summary, fmt.Errorf("failed to diff %s..%s: %w", from, to, err)
+	}
+
+	for _, change := range changes {
+		action, err := change.Action()
+		if err != nil {
+			return summary, fmt.Errorf("failed to diff %s..%s: %w", from, to, err)
+		}
+
+		from, to := workingPath(prefix, change.From.Name), workingPath(prefix, change.To.Name)
+
+		var fileChange FileChange
+		switch {
+		case action == merkletrie.Insert:
+			fileChange = FileChange{Status: 'A', Path: to}
+		case action == merkletrie.Delete:
+			fileChange = FileChange{Status: 'D', Path: from}
+		case change.From.Name != change.To.Name && outside(from):
+			fileChange = FileChange{Status: 'A', Path: to}
+		case change.From.Name != change.To.Name && outside(to):
+			fileChange = FileChange{Status: 'D', Path: from}
+		case change.From.Name != change.To.Name:
+			fileChange = FileChange{Status: 'R', Path: to, OrigPath: from}
+		default:
+			fileChange = FileChange{Status: 'M', Path: to}
+		}
+
+		if !outside(fileChange.Path) {
+			summary.Changes = append(summary.Changes, fileChange)
+		}
+	}
+	sort.Slice(summary.Changes, func(i, j int) bool {
+		return summary.Changes[i].Path < summary.Changes[j].Path
+	})
+
+	reachable := make(map[plumbing.Hash]bool)
+	iter, err := repo.Log(&gogit.LogOptions{From: fromCommit.Hash})
+	if err != nil {
+		return summary, fmt.Errorf("failed to list authors: %w", err)
+	}
+	if err := iter.ForEach(func(c *object.Commit) error {
+		reachable[c.Hash] = true
+		return nil
+	}); err != nil {
+		return summary, fmt.Errorf("failed to list authors: %w", err)
+	}
+
+	iter, err = repo.Log(&gogit.LogOptions{From: toCommit.Hash})
+	if err != nil {
+		return summary, fmt.Errorf("failed to list authors: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	err = iter.ForEach(func(c *object.Commit) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if reachable[c.Hash] || c.NumParents() > 1 || seen[c.Author.Name] {
+			return nil
+		}
+		seen[c.Author.Name] = true
+		summary.Authors = append(summary.Authors, c.Author.Name)
+		return nil
+	})
+	if err != nil {
+		return summary, fmt.Errorf("failed to list authors: %w", err)
+	}
+	sort.Strings(summary.Authors)
+
+	return summary, nil
+}
+
+func (b *goGitBackend) Show(ctx context.Context, rev, path string) ([]byte, error) {
+	repo, worktree, err := b.open(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	commit, err := resolveCommit(repo, rev)
+	if err != nil {
+		return nil, fmt.Errorf("%s does not exist at %s", path, rev)
+	}
+
+	file, err := commit.File(repoPath(b.prefix(worktree), path))
+	if err != nil {
+		return nil, fmt.Errorf("%s does not exist at %s", path, rev)
+	}
+
+	content, err := file.Contents()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s at %s: %w", path, rev, err)
+	}
+
+	return []byte(content), nil
+}
+
+func (b *goGitBackend) ResolveRef(ctx context.Context, ref string) (string, error) {
+	repo, _, err := b.open(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", ref, err)
+	}
+
+	hash, err := repo.ResolveRevision(plumbing.Revision(ref))
+	if errors.Is(err, plumbing.ErrReferenceNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", ref, err)
+	}
+
+	return hash.String(), nil
+}
+
+func (b *goGitBackend) UpdateRef(ctx context.Context, ref, rev string) error {
+	repo, _, err := b.open(ctx)
+	if err != nil {
+		return err
+	}
+
+	hash, err := repo.ResolveRevision(plumbing.Revision(rev))
+	if err != nil {
+		return fmt.Errorf("failed to update %s: %w", ref, err)
+	}
+
+	if err := repo.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(ref), *hash)); err != nil {
+		return fmt.Errorf("failed to update %s: %w", ref, err)
+	}
+
+	return nil
+}
+
+func (b *goGitBackend) DeleteRef(ctx context.Context, ref string) error {
+	repo, _, err := b.open(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := repo.Storer.RemoveReference(plumbing.ReferenceName(ref)); err != nil {
+		return fmt.Errorf("failed to delete %s: %w", ref, err)
+	}
+
+	return nil
+}
+
+func (b *goGitBackend) ResetKeep(ctx context.Context, rev string) error {
+	repo, worktree, err := b.open(ctx)
+	if err != nil {
+		return err
+	}
+
+	target, err := resolveCommit(repo, rev)
+	if err != nil {
+		return fmt.Errorf("failed to reset to %s: %w", rev, err)
+	}
+	current, err := resolveCommit(repo, "HEAD")
+	if err != nil {
+		return fmt.Errorf("failed to reset to %s: %w", rev, err)
+	}
+
+	status, err := worktree.Status()
+	if err != nil {
+		return fmt.Errorf("failed to get git status: %w", err)
+	}
+	untracked := make(map[string]bool)
+	for name, fileStatus := range status {
+		if fileStatus.Worktree == gogit.Untracked {
+			untracked[name] = true
+		} else if fileStatus.Staging != gogit.Unmodified || fileStatus.Worktree != gogit.Unmodified {
+			return fmt.Errorf("failed to reset to %s: %w", rev, errLocalChanges)
+		}
+	}
+
+	currentTree, err := current.Tree()
+	if err != nil {
+		return fmt.Errorf("failed to reset to %s: %w", rev, err)
+	}
+	targetTree, err := target.Tree()
+	if err != nil {
+		return fmt.Errorf("failed to reset to %s: %w", rev, err)
+	}
+
+	changes, err := object.DiffTreeWithOptions(ctx, currentTree, targetTree, nil)
+	if err != nil {
+		return fmt.Errorf("failed to reset to %s: %w", rev, err)
+	}
+	for _, change := range changes {
+		if change.From.Name == "" && untracked[change.To.Name] {
+			return fmt.Errorf("failed to reset to %s: untracked file %s would be overwritten", rev, change.To.Name)
+		}
+	}
+
+	if err := worktree.Reset(&gogit.ResetOptions{Commit: target.Hash, Mode: gogit.MixedReset}); err != nil {
+		return fmt.Errorf("failed to reset to %s: %w", rev, err)
+	}
+
+	root := worktree.Filesystem.Root()
+	for _, change := range changes {
+		if change.To.Name == "" {
+			if err := os.Remove(filepath.Join(root, filepath.FromSlash(change.From.Name))); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("failed to reset %s: %w", change.From.Name, err)
+			}
+			continue
+		}
+
+		if err := writeTreeFile(targetTree, root, change.To.Name); err != nil {
+			return fmt.Errorf("failed to reset %s: %w", change.To.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func writeTreeFile(tree *object.Tree, root, name string) error {
+	file, err := tree.File(name)
+	if err != nil {
+		return err
+	}
+
+	content, err := file.Contents()
+	if err != nil {
+		return err
+	}
+
+	mode, err := file.Mode.ToOSFileMode()
+	if err != nil {
+		return err
+	}
+
+	fullPath := filepath.Join(root, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(fullPath, []byte(content), mode.Perm())
+}
+
+func (b *goGitBackend) State(ctx context.Context) (string, error) {
+	repo, _, err := b.open(ctx)
+	if err != nil {
+		return StateNone, err
+	}
+
+	storage, ok := repo.Storer.(*filesystem.Storage)
+	if !ok {
+		return StateNone, nil
+	}
+	gitDir := storage.Filesystem().Root()
+
+	for _, candidate := range []struct {
+		name  string
+		state string
+	}{
+		{"rebase-merge", StateRebase},
+		{"rebase-apply", StateRebase},
+		{"MERGE_HEAD", StateMerge},
+	} {
+		if _, err := os.Stat(filepath.Join(gitDir, candidate.name)); err == nil {
+			return candidate.state, nil
+		}
+	}
+
+	return StateNone, nil
+}
+
+func (b *goGitBackend) TopLevel(ctx context.Context) (string, error) {
+	_, worktree, err := b.open(ctx)
+	if errors.Is(err, ErrNotARepo) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to find the repository root: %w", err)
+	}
+
+	return worktree.Filesystem.Root(), nil
+}
+
+func (b *goGitBackend) FileDates(ctx context.Context) (map[string]FileDates, error) {
+	repo, worktree, err := b.open(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	dates := make(map[string]FileDates)
+
+	head, err := repo.Head()
+	if errors.Is(err, plumbing.ErrReferenceNotFound) {
+		return dates, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve HEAD: %w", err)
+	}
+
+	commits, err := repo.Log(&gogit.LogOptions{From: head.Hash()})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read git log: %w", goGitError(ctx, "log", err))
+	}
+
+	prefix := b.prefix(worktree)
+
+	err = commits.ForEach(func(commit *object.Commit) error {
+		if commit.NumParents() > 1 {
+			return nil
+		}
+
+		tree, err := commit.Tree()
+		if err != nil {
+			return err
+		}
+
+		var parentTree *object.Tree
+		if commit.NumParents() == 1 {
+			parent, err := commit.Parent(0)
+			if err != nil {
+				return err
+			}
+			if parentTree, err = parent.Tree(); err != nil {
+				return err
+			}
+		}
+
+		changes, err := object.DiffTreeWithOptions(ctx, parentTree, tree, nil)
+		if err != nil {
+			return err
+		}
+
+		commitTime := commit.Committer.When
+		for _, change := range changes {
+			name := change.To.Name
+			if name == "" {
+				name = change.From.Name
+			}
+
+			path := workingPath(prefix, name)
+			if outside(path) {
+				continue
+			}
+
+			fileDates, seen := dates[path]
+			if !seen || commitTime.Before(fileDates.Created) {
+				fileDates.Created = commitTime
+			}
+			if !seen || commitTime.After(fileDates.Updated) {
+				fileDates.Updated = commitTime
+			}
+			dates[path] = fileDates
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read git log: %w", goGitError(ctx, "log", err))
+	}
+
+	return dates, nil
+}
+
+func (b *goGitBackend) LFSAvailable(ctx context.Context) bool {
+	return false
+}
+
+func (b *goGitBackend) LFSTracked(ctx context.Context, paths []string) (map[string]bool, error) {
+	_, worktree, err := b.open(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	patterns, err := gitattributes.ReadPatterns(worktree.Filesystem, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read attributes: %w", err)
+	}
+	matcher := gitattributes.NewMatcher(patterns)
+
+	prefix := b.prefix(worktree)
+	tracked := make(map[string]bool)
+
+	for _, path := range paths {
+		attributes, _ := matcher.Match(strings.Split(repoPath(prefix, path), "/"), []string{"filter"})
+		if filter, ok := attributes["filter"]; ok && filter.Value() == "lfs" {
+			tracked[path] = true
+		}
+	}
+
+	return tracked, nil
+}
+
+func (b *goGitBackend) RepositorySize(ctx context.Context) (int64, error) {
+	repo, _, err := b.open(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	storage, ok := repo.Storer.(*filesystem.Storage)
+	if !ok {
+		return 0, nil
+	}
+
+	var size int64
+	err = filepath.WalkDir(filepath.Join(storage.Filesystem().Root(), "objects"), func(path string, entry os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.Type().IsRegular() {
+			info, err := entry.Info()
+			if err != nil {
+				return err
+			}
+			size += info.Size()
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to measure repository: %w", err)
+	}
+
+	return size, nil
+}
+
+func resolveCommit(repo *gogit.Repository, rev string) (*object.Commit, error) {
+	hash, err := repo.ResolveRevision(plumbing.Revision(rev))
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", rev, err)
+	}
+
+	commit, err := repo.CommitObject(*hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read commit %s: %w", rev, err)
+	}
+
+	return commit, nil
+}
+
+func goGitError(ctx context.Context, op string, err error) error {
+	gitErr := &Error{Args: []string{op}, Err: err}
+
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		gitErr.Kind = ErrTimeout
+		gitErr.Err = ctx.Err()
+	case errors.Is(err, gogit.ErrRepositoryNotExists):
+		gitErr.Kind = ErrNotARepo
+	case errors.Is(err, transport.ErrAuthenticationRequired), errors.Is(err, transport.ErrAuthorizationFailed):
+		gitErr.Kind = ErrAuthRequired
+	case errors.Is(err, gogit.ErrRemoteNotFound), errors.Is(err, plumbing.ErrReferenceNotFound):
+		gitErr.Kind = ErrNoUpstream
+	case errors.Is(err, gogit.ErrForceNeeded), errors.Is(err, gogit.ErrNonFastForwardUpdate), strings.Contains(err.Error(), "non-fast-forward"):
+		gitErr.Kind = ErrPushRejected
+	}
+
+	return gitErr
+}
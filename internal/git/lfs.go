@@ -0,0 +1,127 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+const binarySniffLength = 8000
+
+type LargeFile struct {
+	Path string
+	Size int64
+}
+
+func (g *Manager) LFSAvailable(ctx context.Context) bool {
+	ctx, cancel := withTimeout(ctx, g.timeout)
+	defer cancel()
+
+	return g.backend.LFSAvailable(ctx)
+}
+
+func (g *Manager) LFSTracked(ctx context.Context, paths []string) (map[string]bool, error) {
+	if len(paths) == 0 {
+		return make(map[string]bool), nil
+	}
+
+	ctx, cancel := withTimeout(ctx, g.timeout)
+	defer cancel()
+
+	return g.backend.LFSTracked(ctx, paths)
+}
+
+func (g *Manager) RepositorySize(ctx context.Context) (int64, error) {
+	ctx, cancel := withTimeout(ctx, g.timeout)
+	defer cancel()
+
+	return g.backend.RepositorySize(ctx)
+}
+
+func (g *Manager) LFSTrack(ctx context.Context, pattern string) error {
+	if _, err := g.git(ctx, "lfs", "track", pattern); err != nil {
+		return fmt.Errorf("failed to track %s with git lfs: %w", pattern, err)
+	}
+
+	return nil
+}
+
+func (g *Manager) Renormalize(ctx context.Context, paths []string) error {
+	if len(paths) == 0 {
+		return nil
+	}
+
+	if _, err := g.git(ctx, append([]string{"add", "--renormalize", "--"}, paths...)...); err != nil {
+		return fmt.Errorf("failed to restage files: %w", err)
+	}
+
+	return nil
+}
+
+func (g *Manager) LargeFiles(ctx context.Context, entries []StatusEntry, threshold int64) ([]LargeFile, error) {
+	if threshold <= 0 {
+		return nil, nil
+	}
+
+	var candidates []LargeFile
+	for _, entry := range entries {
+		if entry.IsDeleted() {
+			continue
+		}
+
+		info, err := os.Stat(filepath.Join(g.workingDir, entry.Path))
+		if err != nil || info.IsDir() || info.Size() < threshold {
+			continue
+		}
+
+		binary, err := isBinaryFile(filepath.Join(g.workingDir, entry.Path))
+		if err != nil {
+			return nil, err
+		}
+		if binary {
+			candidates = append(candidates, LargeFile{Path: entry.Path, Size: info.Size()})
+		}
+	}
+
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	paths := make([]string, len(candidates))
+	for i, file := range candidates {
+		paths[i] = file.Path
+	}
+
+	tracked, err := g.LFSTracked(ctx, paths)
+	if err != nil {
+		return nil, err
+	}
+
+	var large []LargeFile
+	for _, file := range candidates {
+		if !tracked[file.Path] {
+			large = append(large, file)
+		}
+	}
+
+	return large, nil
+}
+
+func isBinaryFile(path string) (bool, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return false, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer file.Close()
+
+	buffer := make([]byte, binarySniffLength)
+	n, err := io.ReadFull(file, buffer)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return false, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	return bytes.IndexByte(buffer[:n], 0) >= 0, nil
+}
@@ -0,0 +1,122 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const fakeLFS = `#!/bin/sh
+case "$1" in
+version) echo "git-lfs/3.4.0 (fake)" ;;
+track) echo "$2 filter=lfs diff=lfs merge=lfs -text" >> .gitattributes ;;
+*) exit 1 ;;
+esac
+`
+
+func withLFS(t *testing.T, installed bool) {
+	gitPath, err := exec.LookPath("git")
+	if err != nil {
+		t.Fatalf("git not found: %v", err)
+	}
+
+	binDir := t.TempDir()
+	if err := os.Symlink(gitPath, filepath.Join(binDir, "git")); err != nil {
+		t.Fatalf("Failed to link git: %v", err)
+	}
+	if installed {
+		os.WriteFile(filepath.Join(binDir, "git-lfs"), []byte(fakeLFS), 0755)
+	}
+
+	t.Setenv("PATH", binDir)
+}
+
+func TestLargeFiles(t *testing.T) {
+	tempDir := setupGitRepo(t)
+	manager := NewManager(tempDir)
+
+	os.MkdirAll(filepath.Join(tempDir, "work", "assets"), 0755)
+	os.WriteFile(filepath.Join(tempDir, "work", "assets", "big.psd"), append([]byte{0}, bytes.Repeat([]byte("x"), 4096)...), 0644)
+	os.WriteFile(filepath.Join(tempDir, "work", "assets", "small.png"), []byte{0, 1, 2}, 0644)
+	os.WriteFile(filepath.Join(tempDir, "work", "long.md"), bytes.Repeat([]byte("text "), 1024), 0644)
+	os.WriteFile(filepath.Join(tempDir, "work", "assets", "tracked.bin"), append([]byte{0}, bytes.Repeat([]byte("y"), 4096)...), 0644)
+	os.WriteFile(filepath.Join(tempDir, ".gitattributes"), []byte("*.bin filter=lfs diff=lfs merge=lfs -text\n"), 0644)
+
+	status, err := manager.GetStatus(context.Background())
+	if err != nil {
+		t.Fatalf("GetStatus failed: %v", err)
+	}
+
+	large, err := manager.LargeFiles(context.Background(), status, 1024)
+	if err != nil {
+		t.Fatalf("LargeFiles failed: %v", err)
+	}
+
+	if len(large) != 1 || large[0].Path != filepath.Join("work", "assets", "big.psd") || large[0].Size != 4097 {
+		t.Errorf("Expected only the untracked large binary, got %+v", large)
+	}
+
+	large, err = manager.LargeFiles(context.Background(), status, 0)
+	if err != nil || len(large) != 0 {
+		t.Errorf("Expected a zero threshold to disable the check, got %+v, %v", large, err)
+	}
+}
+
+func TestRepositorySize(t *testing.T) {
+	tempDir := setupGitRepo(t)
+	manager := NewManager(tempDir)
+
+	empty, err := manager.RepositorySize(context.Background())
+	if err != nil {
+		t.Fatalf("RepositorySize failed: %v", err)
+	}
+
+	os.WriteFile(filepath.Join(tempDir, "first.md"), bytes.Repeat([]byte("note "), 2048), 0644)
+	commitAt(t, tempDir, "initial", 1700000000)
+
+	size, err := manager.RepositorySize(context.Background())
+	if err != nil {
+		t.Fatalf("RepositorySize failed: %v", err)
+	}
+	if size <= empty {
+		t.Errorf("Expected the repository to grow after a commit, got %d then %d", empty, size)
+	}
+}
+
+func TestLFSTrack(t *testing.T) {
+	tempDir := setupGitRepo(t)
+	manager := NewManager(tempDir)
+
+	withLFS(t, false)
+	if manager.LFSAvailable(context.Background()) {
+		t.Fatal("Expected git lfs to be unavailable")
+	}
+	if err := manager.LFSTrack(context.Background(), "*.psd"); err == nil {
+		t.Error("Expected LFSTrack to fail without git lfs")
+	}
+
+	withLFS(t, true)
+	if !manager.LFSAvailable(context.Background()) {
+		t.Fatal("Expected git lfs to be available")
+	}
+	if err := manager.LFSTrack(context.Background(), "*.psd"); err != nil {
+		t.Fatalf("LFSTrack failed: %v", err)
+	}
+
+	attributes, _ := os.ReadFile(filepath.Join(tempDir, ".gitattributes"))
+	if !strings.Contains(string(attributes), "*.psd filter=lfs") {
+		t.Errorf("Expected *.psd in .gitattributes, got %q", attributes)
+	}
+
+	tracked, err := manager.LFSTracked(context.Background(), []string{filepath.Join("work", "mock.psd"), "notes.md"})
+	if err != nil {
+		t.Fatalf("LFSTracked failed: %v", err)
+	}
+	if !tracked[filepath.Join("work", "mock.psd")] || tracked["notes.md"] {
+		t.Errorf("Unexpected tracked files: %v", tracked)
+	}
+}
@@ -0,0 +1,116 @@
+package git
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+func (e StatusEntry) IsUntracked() bool {
+	return e.Index == '?'
+}
+
+func (e StatusEntry) IsConflicted() bool {
+	return e.Index == 'U' || e.Worktree == 'U' ||
+		(e.Index == 'A' && e.Worktree == 'A') ||
+		(e.Index == 'D' && e.Worktree == 'D')
+}
+
+func (e StatusEntry) IsRenamed() bool {
+	return e.Index == 'R' || e.Worktree == 'R'
+}
+
+func (e StatusEntry) IsDeleted() bool {
+	return e.Index == 'D' || e.Worktree == 'D'
+}
+
+func (e StatusEntry) IsStaged() bool {
+	return e.Index != ' ' && e.Index != '?' && !e.IsConflicted()
+}
+
+func (e StatusEntry) HasWorktreeChanges() bool {
+	return e.Worktree != ' '
+}
+
+func (e StatusEntry) String() string {
+	if e.OrigPath != "" {
+		return fmt.Sprintf("%c%c %s -> %s", e.Index, e.Worktree, e.OrigPath, e.Path)
+	}
+	return fmt.Sprintf("%c%c %s", e.Index, e.Worktree, e.Path)
+}
+
+func parseStatus(output string) ([]StatusEntry, error) {
+	entries := []StatusEntry{}
+	records := strings.Split(output, "\x00")
+
+	for i := 0; i < len(records); i++ {
+		record := records[i]
+		if record == "" {
+			continue
+		}
+
+		var entry StatusEntry
+
+		switch record[0] {
+		case '1':
+			fields := strings.SplitN(record, " ", 9)
+			if len(fields) != 9 {
+				return nil, fmt.Errorf("malformed status entry: %q", record)
+			}
+			entry = newStatusEntry(fields[1], fields[8])
+		case '2':
+			fields := strings.SplitN(record, " ", 10)
+			if len(fields) != 10 || i+1 >= len(records) {
+				return nil, fmt.Errorf("malformed status entry: %q", record)
+			}
+			entry = newStatusEntry(fields[1], fields[9])
+			i++
+			entry.OrigPath = filepath.FromSlash(records[i])
+		case 'u':
+			fields := strings.SplitN(record, " ", 11)
+			if len(fields) != 11 {
+				return nil, fmt.Errorf("malformed status entry: %q", record)
+			}
+			entry = newStatusEntry(fields[1], fields[10])
+		case '?':
+			entry = StatusEntry{Index: '?', Worktree: '?', Path: filepath.FromSlash(strings.TrimPrefix(record, "? "))}
+		case '!', '#':
+			continue
+		default:
+			return nil, fmt.Errorf("unknown status entry: %q", record)
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+func newStatusEntry(states, path string) StatusEntry {
+	entry := StatusEntry{Index: ' ', Worktree: ' ', Path: filepath.FromSlash(path)}
+	if len(states) == 2 {
+		if states[0] != '.' {
+			entry.Index = states[0]
+		}
+		if states[1] != '.' {
+			entry.Worktree = states[1]
+		}
+	}
+	return entry
+}
+
+func repoPath(prefix, path string) string {
+	return filepath.ToSlash(filepath.Join(prefix, path))
+}
+
+func workingPath(prefix, name string) string {
+	rel, err := filepath.Rel(filepath.Join(".", prefix), filepath.FromSlash(name))
+	if err != nil {
+		return filepath.FromSlash(name)
+	}
+	return rel
+}
+
+func outside(path string) bool {
+	return path == ".." || strings.HasPrefix(path, ".."+string(filepath.Separator))
+}
@@ -0,0 +1,83 @@
+package git
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseStatus(t *testing.T) {
+	output := "1 .M N... 100644 100644 100644 abc abc work/2024-01-01 my note.md\x00" +
+		"2 R. N... 100644 100644 100644 abc abc R100 new name.md\x00old name.md\x00" +
+		"u UU N... 100644 100644 100644 100644 abc def 012 readme.md\x00" +
+		"1 D. N... 100644 000000 000000 abc 000 gone.md\x00" +
+		"? café.md\x00"
+
+	entries, err := parseStatus(output)
+	if err != nil {
+		t.Fatalf("parseStatus failed: %v", err)
+	}
+
+	if len(entries) != 5 {
+		t.Fatalf("Expected 5 entries, got %d", len(entries))
+	}
+
+	if entries[0].Index != ' ' || entries[0].Worktree != 'M' || entries[0].Path != filepath.Join("work", "2024-01-01 my note.md") {
+		t.Errorf("Unexpected modified entry: %s", entries[0])
+	}
+
+	if !entries[1].IsRenamed() || entries[1].Path != "new name.md" || entries[1].OrigPath != "old name.md" {
+		t.Errorf("Unexpected renamed entry: %s", entries[1])
+	}
+
+	if !entries[2].IsConflicted() || entries[2].IsStaged() {
+		t.Errorf("Expected conflicted entry, got %s", entries[2])
+	}
+
+	if !entries[3].IsDeleted() || !entries[3].IsStaged() || entries[3].HasWorktreeChanges() {
+		t.Errorf("Unexpected deleted entry: %s", entries[3])
+	}
+
+	if !entries[4].IsUntracked() || entries[4].Path != "café.md" {
+		t.Errorf("Unexpected untracked entry: %s", entries[4])
+	}
+
+	if _, err := parseStatus("1 .M N...\x00"); err == nil {
+		t.Error("Expected error for malformed entry")
+	}
+}
+
+func TestGetStatusRenamesAndDeletions(t *testing.T) {
+	tempDir := setupGitRepo(t)
+	manager := NewManager(tempDir)
+
+	os.WriteFile(filepath.Join(tempDir, "old note.md"), []byte("content that is long enough to be detected as a rename\n"), 0644)
+	os.WriteFile(filepath.Join(tempDir, "doomed.md"), []byte("bye\n"), 0644)
+	commitAt(t, tempDir, "initial", 1700000000)
+
+	cmd := exec.Command("git", "mv", "old note.md", "new note.md")
+	cmd.Dir = tempDir
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("Failed to rename: %v", err)
+	}
+	os.Remove(filepath.Join(tempDir, "doomed.md"))
+
+	status, err := manager.GetStatus(context.Background())
+	if err != nil {
+		t.Fatalf("GetStatus failed: %v", err)
+	}
+
+	if len(status) != 2 {
+		t.Fatalf("Expected 2 entries, got %v", status)
+	}
+
+	if status[0].Path != "doomed.md" || status[0].Worktree != 'D' {
+		t.Errorf("Expected deleted doomed.md, got %s", status[0])
+	}
+
+	if status[1].Path != "new note.md" || status[1].OrigPath != "old note.md" || status[1].Index != 'R' {
+		t.Errorf("Expected rename of old note.md, got %s", status[1])
+	}
+}
@@ -0,0 +1,248 @@
+package importer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gitnote/internal/note"
+)
+
+const (
+	SourceObsidian = "obsidian"
+	SourceJoplin   = "joplin"
+	SourceFolder   = "folder"
+)
+
+type Item struct {
+	Source      string
+	Destination string
+	Title       string
+	Category    string
+	Date        time.Time
+	Tags        []string
+	Content     string
+	Changes     []string
+	Skipped     string
+}
+
+type Importer struct {
+	workingDir string
+	category   string
+}
+
+type sourceNote struct {
+	source      string
+	name        string
+	title       string
+	category    string
+	date        time.Time
+	tags        []string
+	frontMatter note.FrontMatter
+	body        string
+}
+
+func NewImporter(workingDir string) *Importer {
+	if workingDir == "" {
+		workingDir = "."
+	}
+	return &Importer{workingDir: workingDir}
+}
+
+func (i *Importer) SetCategory(category string) {
+	i.category = category
+}
+
+func IsValidSource(source string) bool {
+	switch source {
+	case SourceObsidian, SourceJoplin, SourceFolder:
+		return true
+	default:
+		return false
+	}
+}
+
+func DetectSource(path string) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read import source: %w", err)
+	}
+
+	if !info.IsDir() {
+		if strings.EqualFold(filepath.Ext(path), ".jex") {
+			return SourceJoplin, nil
+		}
+		return "", fmt.Errorf("import source must be a directory or a .jex file")
+	}
+
+	if _, err := os.Stat(filepath.Join(path, ".obsidian")); err == nil {
+		return SourceObsidian, nil
+	}
+
+	if isJoplinDirectory(path) {
+		return SourceJoplin, nil
+	}
+
+	return SourceFolder, nil
+}
+
+func (i *Importer) Plan(path, source string) ([]Item, error) {
+	var notes []sourceNote
+	var err error
+
+	switch source {
+	case SourceObsidian:
+		notes, err = readMarkdownFolder(path, true)
+	case SourceFolder:
+		notes, err = readMarkdownFolder(path, false)
+	case SourceJoplin:
+		notes, err = readJoplin(path)
+	default:
+		return nil, fmt.Errorf("unsupported import source: %s", source)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]Item, 0, len(notes))
+	planned := make(map[string]bool)
+
+	for _, source := range notes {
+		items = append(items, i.planItem(source, planned))
+	}
+
+	return items, nil
+}
+
+func (i *Importer) planItem(source sourceNote, planned map[string]bool) Item {
+	title := sanitizeName(source.title)
+
+	var categoryParts []string
+	if i.category != "" {
+		categoryParts = append(categoryParts, i.category)
+	}
+	for _, part := range strings.Split(filepath.ToSlash(source.category), "/") {
+		if part != "" {
+			categoryParts = append(categoryParts, sanitizeName(part))
+		}
+	}
+	category := filepath.Join(categoryParts...)
+
+	item := Item{
+		Source:   source.source,
+		Title:    title,
+		Category: category,
+		Date:     source.date,
+	}
+
+	destination := filepath.Join(category, note.Filename(source.date, title))
+	for n := 2; planned[strings.ToLower(destination)]; n++ {
+		destination = filepath.Join(category, note.Filename(source.date, fmt.Sprintf("%s (%d)", title, n)))
+	}
+	planned[strings.ToLower(destination)] = true
+	item.Destination = destination
+
+	if _, err := os.Stat(filepath.Join(i.workingDir, destination)); err == nil {
+		item.Skipped = "already exists"
+		return item
+	}
+
+	frontMatter := source.frontMatter
+	var addedTags []string
+	for _, tag := range source.tags {
+		if !frontMatter.HasTag(tag) {
+			frontMatter.Tags = append(frontMatter.Tags, tag)
+			addedTags = append(addedTags, tag)
+		}
+	}
+	item.Tags = frontMatter.Tags
+
+	body := strings.TrimSpace(source.body)
+	if !strings.HasPrefix(body, "# ") {
+		body = strings.TrimSpace(fmt.Sprintf("# %s\n\n%s", source.title, body))
+	}
+	item.Content = frontMatter.String() + body + "\n"
+
+	if source.name != filepath.Base(destination) {
+		item.Changes = append(item.Changes, fmt.Sprintf("renamed from %q", source.name))
+	}
+	if category != "" {
+		item.Changes = append(item.Changes, fmt.Sprintf("filed under %s", category))
+	}
+	if len(addedTags) > 0 {
+		item.Changes = append(item.Changes, fmt.Sprintf("tags added to front matter: %s", strings.Join(addedTags, ", ")))
+	}
+
+	return item
+}
+
+func (i *Importer) Apply(items []Item) (int, error) {
+	imported := 0
+
+	for _, item := range items {
+		if item.Skipped != "" {
+			continue
+		}
+
+		fullPath := filepath.Join(i.workingDir, item.Destination)
+
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+			return imported, fmt.Errorf("failed to create directory: %w", err)
+		}
+
+		file, err := os.OpenFile(fullPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if err != nil {
+			return imported, fmt.Errorf("failed to create note %s: %w", item.Destination, err)
+		}
+
+		_, err = file.WriteString(item.Content)
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return imported, fmt.Errorf("failed to write note %s: %w", item.Destination, err)
+		}
+
+		if !item.Date.IsZero() {
+			os.Chtimes(fullPath, item.Date, item.Date)
+		}
+
+		imported++
+	}
+
+	return imported, nil
+}
+
+func sanitizeName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune("/\\:*?\"<>|", r) || r < ' ' {
+			return '-'
+		}
+		return r
+	}, name)
+
+	name = strings.Trim(strings.TrimSpace(name), ".")
+	if name == "" {
+		return "untitled"
+	}
+
+	return name
+}
+
+func parseDate(value interface{}) (time.Time, bool) {
+	switch v := value.(type) {
+	case time.Time:
+		return v, true
+	case string:
+		for _, layout := range []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02 15:04", "2006-01-02"} {
+			if date, err := time.ParseInLocation(layout, strings.TrimSpace(v), time.Local); err == nil {
+				return date, true
+			}
+		}
+	}
+
+	return time.Time{}, false
+}
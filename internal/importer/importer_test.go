@@ -0,0 +1,173 @@
+package importer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewImporter(t *testing.T) {
+	importer := NewImporter("")
+	if importer.workingDir != "." {
+		t.Errorf("Expected working dir to be '.', got %s", importer.workingDir)
+	}
+}
+
+func TestIsValidSource(t *testing.T) {
+	for _, source := range []string{SourceObsidian, SourceJoplin, SourceFolder} {
+		if !IsValidSource(source) {
+			t.Errorf("Expected %s to be a valid source", source)
+		}
+	}
+
+	if IsValidSource("evernote") {
+		t.Error("Expected evernote to be an invalid source")
+	}
+}
+
+func TestDetectSource(t *testing.T) {
+	vault := t.TempDir()
+	os.Mkdir(filepath.Join(vault, ".obsidian"), 0755)
+
+	joplin := t.TempDir()
+	os.WriteFile(filepath.Join(joplin, "0123.md"), []byte("Folder\n\nid: 0123\ntype_: 2"), 0644)
+
+	folder := t.TempDir()
+	os.WriteFile(filepath.Join(folder, "note.md"), []byte("# note"), 0644)
+
+	jex := filepath.Join(t.TempDir(), "export.jex")
+	os.WriteFile(jex, []byte{}, 0644)
+
+	tests := map[string]string{
+		vault:  SourceObsidian,
+		joplin: SourceJoplin,
+		folder: SourceFolder,
+		jex:    SourceJoplin,
+	}
+
+	for path, expected := range tests {
+		source, err := DetectSource(path)
+		if err != nil {
+			t.Fatalf("DetectSource failed for %s: %v", path, err)
+		}
+		if source != expected {
+			t.Errorf("Expected %s for %s, got %s", expected, path, source)
+		}
+	}
+
+	if _, err := DetectSource(filepath.Join(folder, "note.md")); err == nil {
+		t.Error("Expected error for a plain file source")
+	}
+}
+
+func TestPlanAndApply(t *testing.T) {
+	source := t.TempDir()
+	os.MkdirAll(filepath.Join(source, "Work", "Meetings"), 0755)
+	os.WriteFile(filepath.Join(source, "Work", "Meetings", "Standup.md"), []byte("Discussed #roadmap"), 0644)
+	os.WriteFile(filepath.Join(source, "Work", "2024-03-01 plan.md"), []byte("# plan\n"), 0644)
+
+	created := time.Date(2024, 2, 1, 9, 0, 0, 0, time.Local)
+	os.Chtimes(filepath.Join(source, "Work", "Meetings", "Standup.md"), created, created)
+
+	workingDir := t.TempDir()
+	os.MkdirAll(filepath.Join(workingDir, "imported", "Work"), 0755)
+	os.WriteFile(filepath.Join(workingDir, "imported", "Work", "2024-03-01 plan.md"), []byte("# existing"), 0644)
+
+	importer := NewImporter(workingDir)
+	importer.SetCategory("imported")
+
+	items, err := importer.Plan(source, SourceObsidian)
+	if err != nil {
+		t.Fatalf("Plan failed: %v", err)
+	}
+
+	if len(items) != 2 {
+		t.Fatalf("Expected 2 planned items, got %d", len(items))
+	}
+
+	standup := items[1]
+	expectedPath := filepath.Join("imported", "Work", "Meetings", "2024-02-01 Standup.md")
+	if standup.Destination != expectedPath {
+		t.Errorf("Expected destination %s, got %s", expectedPath, standup.Destination)
+	}
+
+	if standup.Content != "---\ntags: [roadmap]\n---\n# Standup\n\nDiscussed #roadmap\n" {
+		t.Errorf("Unexpected planned content: %q", standup.Content)
+	}
+
+	if !strings.Contains(strings.Join(standup.Changes, "; "), `renamed from "Standup.md"`) {
+		t.Errorf("Expected rename to be reported, got %v", standup.Changes)
+	}
+
+	if items[0].Skipped != "already exists" {
+		t.Errorf("Expected existing note to be skipped, got %q", items[0].Skipped)
+	}
+
+	if _, err := os.Stat(filepath.Join(workingDir, standup.Destination)); !os.IsNotExist(err) {
+		t.Fatal("Expected Plan not to write any files")
+	}
+
+	imported, err := importer.Apply(items)
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	if imported != 1 {
+		t.Errorf("Expected 1 imported note, got %d", imported)
+	}
+
+	content, err := os.ReadFile(filepath.Join(workingDir, standup.Destination))
+	if err != nil {
+		t.Fatalf("Failed to read imported note: %v", err)
+	}
+
+	if string(content) != standup.Content {
+		t.Errorf("Expected imported content %q, got %q", standup.Content, content)
+	}
+
+	existing, _ := os.ReadFile(filepath.Join(workingDir, "imported", "Work", "2024-03-01 plan.md"))
+	if string(existing) != "# existing" {
+		t.Error("Expected existing note not to be overwritten")
+	}
+}
+
+func TestPlanDeduplicatesDestinations(t *testing.T) {
+	source := t.TempDir()
+	os.WriteFile(filepath.Join(source, "2024-01-01 idea.md"), []byte("# idea"), 0644)
+	os.WriteFile(filepath.Join(source, "idea.md"), []byte("---\ndate: 2024-01-01\n---\n# idea"), 0644)
+
+	items, err := NewImporter(t.TempDir()).Plan(source, SourceFolder)
+	if err != nil {
+		t.Fatalf("Plan failed: %v", err)
+	}
+
+	if items[0].Destination != "2024-01-01 idea.md" {
+		t.Errorf("Unexpected first destination: %s", items[0].Destination)
+	}
+
+	if items[1].Destination != "2024-01-01 idea (2).md" {
+		t.Errorf("Unexpected second destination: %s", items[1].Destination)
+	}
+}
+
+func TestPlanUnsupportedSource(t *testing.T) {
+	if _, err := NewImporter(t.TempDir()).Plan(t.TempDir(), "evernote"); err == nil {
+		t.Error("Expected error for unsupported source")
+	}
+}
+
+func TestSanitizeName(t *testing.T) {
+	tests := map[string]string{
+		"a/b: c?":  "a-b- c-",
+		"  ..  ":   "untitled",
+		"Meetings": "Meetings",
+	}
+
+	for input, expected := range tests {
+		if result := sanitizeName(input); result != expected {
+			t.Errorf("Expected sanitizeName(%q) to be %q, got %q", input, expected, result)
+		}
+	}
+}
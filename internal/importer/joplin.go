@@ -0,0 +1,244 @@
+package importer
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	joplinTypeNote    = "1"
+	joplinTypeFolder  = "2"
+	joplinTypeTag     = "5"
+	joplinTypeNoteTag = "6"
+)
+
+var joplinMetadataPattern = regexp.MustCompile(`^([a-z_]+):(?: (.*))?$`)
+
+type joplinItem struct {
+	source   string
+	title    string
+	body     string
+	metadata map[string]string
+}
+
+func isJoplinDirectory(root string) bool {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return false
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".md") {
+			continue
+		}
+
+		content, err := os.ReadFile(filepath.Join(root, entry.Name()))
+		if err != nil {
+			return false
+		}
+
+		_, ok := parseJoplinItem(entry.Name(), string(content))
+		return ok
+	}
+
+	return false
+}
+
+func readJoplin(source string) ([]sourceNote, error) {
+	var files map[string]string
+	var err error
+
+	if strings.EqualFold(filepath.Ext(source), ".jex") {
+		files, err = readJoplinArchive(source)
+	} else {
+		files, err = readJoplinDirectory(source)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	items := make(map[string]joplinItem)
+	var noteIDs []string
+
+	for _, name := range names {
+		item, ok := parseJoplinItem(name, files[name])
+		if !ok {
+			continue
+		}
+
+		id := item.metadata["id"]
+		items[id] = item
+
+		if item.metadata["type_"] == joplinTypeNote {
+			noteIDs = append(noteIDs, id)
+		}
+	}
+
+	noteTags := make(map[string][]string)
+	for _, item := range items {
+		if item.metadata["type_"] != joplinTypeNoteTag {
+			continue
+		}
+
+		if tag, ok := items[item.metadata["tag_id"]]; ok {
+			noteTags[item.metadata["note_id"]] = append(noteTags[item.metadata["note_id"]], tag.title)
+		}
+	}
+
+	notes := make([]sourceNote, 0, len(noteIDs))
+
+	for _, id := range noteIDs {
+		item := items[id]
+
+		tags := noteTags[id]
+		sort.Strings(tags)
+
+		notes = append(notes, sourceNote{
+			source:   item.source,
+			name:     path.Base(item.source),
+			title:    item.title,
+			category: joplinFolderPath(items, item.metadata["parent_id"]),
+			date:     joplinCreated(item),
+			tags:     tags,
+			body:     item.body,
+		})
+	}
+
+	return notes, nil
+}
+
+func readJoplinDirectory(root string) (map[string]string, error) {
+	files := make(map[string]string)
+
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read joplin export: %w", err)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".md") {
+			continue
+		}
+
+		content, err := os.ReadFile(filepath.Join(root, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", entry.Name(), err)
+		}
+
+		files[entry.Name()] = string(content)
+	}
+
+	return files, nil
+}
+
+func readJoplinArchive(archivePath string) (map[string]string, error) {
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open joplin archive: %w", err)
+	}
+	defer file.Close()
+
+	files := make(map[string]string)
+	reader := tar.NewReader(file)
+
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read joplin archive: %w", err)
+		}
+
+		if header.Typeflag != tar.TypeReg || path.Dir(header.Name) != "." || !strings.HasSuffix(header.Name, ".md") {
+			continue
+		}
+
+		content, err := io.ReadAll(reader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s from joplin archive: %w", header.Name, err)
+		}
+
+		files[header.Name] = string(content)
+	}
+
+	return files, nil
+}
+
+func parseJoplinItem(source, content string) (joplinItem, bool) {
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+
+	end := len(lines)
+	for end > 0 && strings.TrimSpace(lines[end-1]) == "" {
+		end--
+	}
+
+	start := end
+	for start > 0 && joplinMetadataPattern.MatchString(lines[start-1]) {
+		start--
+	}
+
+	metadata := make(map[string]string)
+	for _, line := range lines[start:end] {
+		match := joplinMetadataPattern.FindStringSubmatch(line)
+		metadata[match[1]] = match[2]
+	}
+
+	if metadata["id"] == "" || metadata["type_"] == "" {
+		return joplinItem{}, false
+	}
+
+	item := joplinItem{source: source, metadata: metadata}
+
+	header := lines[:start]
+	if len(header) > 0 {
+		item.title = strings.TrimSpace(header[0])
+		item.body = strings.TrimSpace(strings.Join(header[1:], "\n"))
+	}
+
+	return item, true
+}
+
+func joplinFolderPath(items map[string]joplinItem, parentID string) string {
+	var parts []string
+	seen := make(map[string]bool)
+
+	for parentID != "" && !seen[parentID] {
+		seen[parentID] = true
+
+		folder, ok := items[parentID]
+		if !ok || folder.metadata["type_"] != joplinTypeFolder {
+			break
+		}
+
+		parts = append([]string{sanitizeName(folder.title)}, parts...)
+		parentID = folder.metadata["parent_id"]
+	}
+
+	return filepath.Join(parts...)
+}
+
+func joplinCreated(item joplinItem) time.Time {
+	for _, key := range []string{"user_created_time", "created_time"} {
+		if date, ok := parseDate(item.metadata[key]); ok {
+			return date.Local()
+		}
+	}
+
+	return time.Now()
+}
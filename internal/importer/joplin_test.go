@@ -0,0 +1,110 @@
+package importer
+
+import (
+	"archive/tar"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var joplinExport = map[string]string{
+	"f1.md": "Work\n\nid: f1\nparent_id: \ncreated_time: 2023-01-01T10:00:00.000Z\ntype_: 2",
+	"f2.md": "Meetings\n\nid: f2\nparent_id: f1\ncreated_time: 2023-01-01T10:00:00.000Z\ntype_: 2",
+	"n1.md": "Weekly sync\n\nAgenda items\n\nstatus: draft is part of the body\n\nid: n1\nparent_id: f2\ncreated_time: 2023-02-03T10:00:00.000Z\nuser_created_time: 2023-02-04T10:00:00.000Z\nis_todo: 0\ntype_: 1",
+	"t1.md": "urgent\n\nid: t1\ntype_: 5",
+	"nt.md": "id: nt\nnote_id: n1\ntag_id: t1\ntype_: 6",
+	"r1.md": "photo.png\n\nid: r1\nmime: image/png\ntype_: 4",
+}
+
+func TestParseJoplinItem(t *testing.T) {
+	item, ok := parseJoplinItem("n1.md", joplinExport["n1.md"])
+	if !ok {
+		t.Fatal("Expected joplin item to be parsed")
+	}
+
+	if item.title != "Weekly sync" {
+		t.Errorf("Expected title 'Weekly sync', got %s", item.title)
+	}
+
+	if item.body != "Agenda items\n\nstatus: draft is part of the body" {
+		t.Errorf("Unexpected body: %q", item.body)
+	}
+
+	if item.metadata["parent_id"] != "f2" || item.metadata["type_"] != joplinTypeNote {
+		t.Errorf("Unexpected metadata: %v", item.metadata)
+	}
+
+	if _, ok := parseJoplinItem("plain.md", "# just a note\n\nwith text"); ok {
+		t.Error("Expected plain markdown not to be parsed as a joplin item")
+	}
+}
+
+func TestReadJoplinDirectory(t *testing.T) {
+	exportDir := t.TempDir()
+	for name, content := range joplinExport {
+		os.WriteFile(filepath.Join(exportDir, name), []byte(content), 0644)
+	}
+
+	assertJoplinNotes(t, exportDir)
+}
+
+func TestReadJoplinArchive(t *testing.T) {
+	archivePath := filepath.Join(t.TempDir(), "export.jex")
+
+	file, err := os.Create(archivePath)
+	if err != nil {
+		t.Fatalf("Failed to create archive: %v", err)
+	}
+
+	writer := tar.NewWriter(file)
+	for name, content := range joplinExport {
+		writer.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg})
+		writer.Write([]byte(content))
+	}
+	writer.WriteHeader(&tar.Header{Name: "resources/r1.png", Mode: 0644, Size: 0, Typeflag: tar.TypeReg})
+	writer.Close()
+	file.Close()
+
+	assertJoplinNotes(t, archivePath)
+}
+
+func assertJoplinNotes(t *testing.T, source string) {
+	t.Helper()
+
+	notes, err := readJoplin(source)
+	if err != nil {
+		t.Fatalf("readJoplin failed: %v", err)
+	}
+
+	if len(notes) != 1 {
+		t.Fatalf("Expected 1 note, got %d", len(notes))
+	}
+
+	sync := notes[0]
+	if sync.title != "Weekly sync" {
+		t.Errorf("Expected title 'Weekly sync', got %s", sync.title)
+	}
+
+	if sync.category != filepath.Join("Work", "Meetings") {
+		t.Errorf("Expected category from notebooks, got %s", sync.category)
+	}
+
+	if sync.date.UTC().Format("2006-01-02") != "2023-02-04" {
+		t.Errorf("Expected user created date 2023-02-04, got %s", sync.date.UTC().Format("2006-01-02"))
+	}
+
+	if !reflect.DeepEqual(sync.tags, []string{"urgent"}) {
+		t.Errorf("Expected tags [urgent], got %v", sync.tags)
+	}
+
+	items, err := NewImporter(t.TempDir()).Plan(source, SourceJoplin)
+	if err != nil {
+		t.Fatalf("Plan failed: %v", err)
+	}
+
+	if !strings.HasPrefix(items[0].Content, "---\ntags: [urgent]\n---\n# Weekly sync\n\nAgenda items") {
+		t.Errorf("Unexpected planned content: %q", items[0].Content)
+	}
+}
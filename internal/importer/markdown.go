@@ -0,0 +1,136 @@
+package importer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"gitnote/internal/note"
+)
+
+var inlineTagPattern = regexp.MustCompile(`(?:^|\s)#([\p{L}\p{N}_/-]*[\p{L}_/-][\p{L}\p{N}_/-]*)`)
+
+func readMarkdownFolder(root string, inlineTags bool) ([]sourceNote, error) {
+	var notes []sourceNote
+
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			if path != root && strings.HasPrefix(info.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if !strings.HasSuffix(strings.ToLower(info.Name()), ".md") {
+			return nil
+		}
+
+		relativePath, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", relativePath, err)
+		}
+
+		frontMatter, body, err := note.ParseFrontMatter(string(content))
+		if err != nil {
+			frontMatter, body = note.FrontMatter{}, string(content)
+		}
+
+		source := sourceNote{
+			source:      relativePath,
+			name:        info.Name(),
+			frontMatter: frontMatter,
+			body:        body,
+			date:        info.ModTime(),
+		}
+
+		if dir := filepath.Dir(relativePath); dir != "." {
+			source.category = dir
+		}
+
+		title, date, hasDate := note.ParseFilename(info.Name())
+		source.title = title
+		if hasDate {
+			source.date = date
+		}
+
+		if frontMatter.Title != "" {
+			source.title = frontMatter.Title
+		}
+
+		if date, ok := frontMatterDate(frontMatter); ok {
+			source.date = date
+		}
+
+		if inlineTags {
+			source.tags = findInlineTags(body)
+		}
+
+		notes = append(notes, source)
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to read import folder: %w", err)
+	}
+
+	return notes, nil
+}
+
+func frontMatterDate(frontMatter note.FrontMatter) (time.Time, bool) {
+	if frontMatter.Date != "" {
+		if date, ok := parseDate(frontMatter.Date); ok {
+			return date, true
+		}
+	}
+
+	for _, key := range []string{"created", "created_at", "creation_date"} {
+		if value, ok := frontMatter.Extra[key]; ok {
+			if date, ok := parseDate(value); ok {
+				return date, true
+			}
+		}
+	}
+
+	return time.Time{}, false
+}
+
+func findInlineTags(body string) []string {
+	seen := make(map[string]bool)
+	var tags []string
+	inCodeBlock := false
+
+	for _, line := range strings.Split(body, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inCodeBlock = !inCodeBlock
+			continue
+		}
+
+		if inCodeBlock {
+			continue
+		}
+
+		for _, match := range inlineTagPattern.FindAllStringSubmatch(line, -1) {
+			tag := match[1]
+			if !seen[strings.ToLower(tag)] {
+				seen[strings.ToLower(tag)] = true
+				tags = append(tags, tag)
+			}
+		}
+	}
+
+	sort.Strings(tags)
+	return tags
+}
@@ -0,0 +1,62 @@
+package importer
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestReadMarkdownFolder(t *testing.T) {
+	vault := t.TempDir()
+	os.MkdirAll(filepath.Join(vault, ".obsidian"), 0755)
+	os.MkdirAll(filepath.Join(vault, "Projects"), 0755)
+
+	os.WriteFile(filepath.Join(vault, ".obsidian", "workspace.md"), []byte("ignored"), 0644)
+	os.WriteFile(filepath.Join(vault, "Projects", "Roadmap.md"), []byte("---\ntitle: Product roadmap\ncreated: 2023-05-06\ntags: [planning]\n---\nWork on #q3/goals and #planning\n\n```\n#notatag\n```\n"), 0644)
+	os.WriteFile(filepath.Join(vault, "image.png"), []byte{}, 0644)
+
+	notes, err := readMarkdownFolder(vault, true)
+	if err != nil {
+		t.Fatalf("readMarkdownFolder failed: %v", err)
+	}
+
+	if len(notes) != 1 {
+		t.Fatalf("Expected 1 note, got %d", len(notes))
+	}
+
+	roadmap := notes[0]
+	if roadmap.title != "Product roadmap" {
+		t.Errorf("Expected front matter title, got %s", roadmap.title)
+	}
+
+	if roadmap.category != "Projects" {
+		t.Errorf("Expected category Projects, got %s", roadmap.category)
+	}
+
+	if roadmap.date.Format("2006-01-02") != "2023-05-06" {
+		t.Errorf("Expected created date 2023-05-06, got %s", roadmap.date.Format("2006-01-02"))
+	}
+
+	if !reflect.DeepEqual(roadmap.tags, []string{"planning", "q3/goals"}) {
+		t.Errorf("Expected inline tags [planning q3/goals], got %v", roadmap.tags)
+	}
+
+	notes, err = readMarkdownFolder(vault, false)
+	if err != nil {
+		t.Fatalf("readMarkdownFolder failed: %v", err)
+	}
+
+	if len(notes[0].tags) != 0 {
+		t.Errorf("Expected inline tags to be ignored for plain folders, got %v", notes[0].tags)
+	}
+}
+
+func TestFindInlineTags(t *testing.T) {
+	body := "# Heading\nIssue #123 and #bug plus #Bug, email a#b\n"
+
+	tags := findInlineTags(body)
+	if !reflect.DeepEqual(tags, []string{"bug"}) {
+		t.Errorf("Expected [bug], got %v", tags)
+	}
+}
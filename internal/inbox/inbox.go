@@ -0,0 +1,148 @@
+package inbox
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gitnote/internal/config"
+	"gitnote/internal/note"
+)
+
+const TimeFormat = "2006-01-02 15:04"
+
+type Item struct {
+	Time      time.Time
+	Text      string
+	startLine int
+	endLine   int
+}
+
+type Manager struct {
+	workingDir  string
+	path        string
+	noteManager *note.Manager
+}
+
+func NewManager(workingDir string, cfg config.InboxConfig) *Manager {
+	if workingDir == "" {
+		workingDir = "."
+	}
+	return &Manager{
+		workingDir:  workingDir,
+		path:        cfg.Path,
+		noteManager: note.NewManager(workingDir),
+	}
+}
+
+func (m *Manager) Path() string {
+	return m.path
+}
+
+func (m *Manager) Capture(text string, now time.Time) error {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return fmt.Errorf("nothing to capture")
+	}
+
+	fullPath := filepath.Join(m.workingDir, m.path)
+
+	if _, err := os.Stat(fullPath); os.IsNotExist(err) {
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+			return fmt.Errorf("failed to create directory: %w", err)
+		}
+		if err := os.WriteFile(fullPath, []byte("# Inbox\n\n"), 0644); err != nil {
+			return fmt.Errorf("failed to create inbox: %w", err)
+		}
+	}
+
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	entry := fmt.Sprintf("- %s %s", now.Format(TimeFormat), lines[0])
+	for _, line := range lines[1:] {
+		entry += "\n  " + line
+	}
+
+	return m.noteManager.AppendToNote(m.path, entry)
+}
+
+func (m *Manager) Items() ([]Item, error) {
+	lines, err := m.readLines()
+	if err != nil {
+		return nil, err
+	}
+
+	var items []Item
+
+	for i := 0; i < len(lines); i++ {
+		if !strings.HasPrefix(lines[i], "- ") {
+			continue
+		}
+
+		item := Item{startLine: i, endLine: i}
+		text := strings.TrimPrefix(lines[i], "- ")
+
+		if len(text) >= len(TimeFormat) {
+			if parsed, err := time.ParseInLocation(TimeFormat, text[:len(TimeFormat)], time.Local); err == nil {
+				item.Time = parsed
+				text = strings.TrimSpace(text[len(TimeFormat):])
+			}
+		}
+
+		for i+1 < len(lines) && strings.HasPrefix(lines[i+1], "  ") {
+			i++
+			item.endLine = i
+			text += "\n" + strings.TrimPrefix(lines[i], "  ")
+		}
+
+		item.Text = text
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+func (m *Manager) Remove(items []Item) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	lines, err := m.readLines()
+	if err != nil {
+		return err
+	}
+
+	removed := make(map[int]bool)
+	for _, item := range items {
+		for line := item.startLine; line <= item.endLine; line++ {
+			removed[line] = true
+		}
+	}
+
+	var kept []string
+	for i, line := range lines {
+		if !removed[i] {
+			kept = append(kept, line)
+		}
+	}
+
+	content := strings.Join(kept, "\n")
+	if err := os.WriteFile(filepath.Join(m.workingDir, m.path), []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to update inbox: %w", err)
+	}
+
+	return nil
+}
+
+func (m *Manager) readLines() ([]string, error) {
+	content, err := os.ReadFile(filepath.Join(m.workingDir, m.path))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read inbox: %w", err)
+	}
+
+	return strings.Split(string(content), "\n"), nil
+}
@@ -0,0 +1,97 @@
+package inbox
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gitnote/internal/config"
+)
+
+func TestNewManager(t *testing.T) {
+	manager := NewManager("", config.Default().Inbox)
+	if manager.workingDir != "." {
+		t.Errorf("Expected working dir to be '.', got %s", manager.workingDir)
+	}
+
+	if manager.Path() != "inbox.md" {
+		t.Errorf("Expected inbox path 'inbox.md', got %s", manager.Path())
+	}
+}
+
+func TestCapture(t *testing.T) {
+	tempDir := t.TempDir()
+	manager := NewManager(tempDir, config.InboxConfig{Path: filepath.Join("capture", "inbox.md")})
+
+	now := time.Date(2026, 10, 19, 14, 3, 0, 0, time.Local)
+
+	if err := manager.Capture("Buy milk", now); err != nil {
+		t.Fatalf("Capture failed: %v", err)
+	}
+
+	if err := manager.Capture("Idea for talk\nwith details\n", now.Add(time.Minute)); err != nil {
+		t.Fatalf("Capture failed: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(tempDir, "capture", "inbox.md"))
+	if err != nil {
+		t.Fatalf("Failed to read inbox: %v", err)
+	}
+
+	expected := "# Inbox\n\n- 2026-10-19 14:03 Buy milk\n- 2026-10-19 14:04 Idea for talk\n  with details\n"
+	if string(content) != expected {
+		t.Errorf("Expected inbox %q, got %q", expected, string(content))
+	}
+
+	if err := manager.Capture("   ", now); err == nil {
+		t.Error("Expected error when capturing empty text")
+	}
+}
+
+func TestItemsAndRemove(t *testing.T) {
+	tempDir := t.TempDir()
+	manager := NewManager(tempDir, config.Default().Inbox)
+
+	items, err := manager.Items()
+	if err != nil {
+		t.Fatalf("Items failed for missing inbox: %v", err)
+	}
+
+	if len(items) != 0 {
+		t.Errorf("Expected no items for missing inbox, got %d", len(items))
+	}
+
+	os.WriteFile(filepath.Join(tempDir, "inbox.md"), []byte("# Inbox\n\n- 2026-10-19 14:03 Buy milk\n- Untimed item\n- 2026-10-19 14:04 Idea for talk\n  with details\n"), 0644)
+
+	items, err = manager.Items()
+	if err != nil {
+		t.Fatalf("Items failed: %v", err)
+	}
+
+	if len(items) != 3 {
+		t.Fatalf("Expected 3 items, got %d", len(items))
+	}
+
+	if items[0].Text != "Buy milk" || items[0].Time.Format(TimeFormat) != "2026-10-19 14:03" {
+		t.Errorf("Unexpected first item: %+v", items[0])
+	}
+
+	if items[1].Text != "Untimed item" || !items[1].Time.IsZero() {
+		t.Errorf("Unexpected second item: %+v", items[1])
+	}
+
+	if items[2].Text != "Idea for talk\nwith details" {
+		t.Errorf("Unexpected multi-line item text: %q", items[2].Text)
+	}
+
+	if err := manager.Remove([]Item{items[0], items[2]}); err != nil {
+		t.Fatalf("Remove failed: %v", err)
+	}
+
+	content, _ := os.ReadFile(filepath.Join(tempDir, "inbox.md"))
+	expected := "# Inbox\n\n- Untimed item\n"
+	if string(content) != expected {
+		t.Errorf("Expected inbox %q after remove, got %q", expected, string(content))
+	}
+}
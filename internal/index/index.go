@@ -10,11 +10,21 @@ import (
 	"gitnote/internal/note"
 )
 
+const (
+	ReadmeFile  = "readme.md"
+	StartMarker = "<!-- gitnote:index:start -->"
+	EndMarker   = "<!-- gitnote:index:end -->"
+)
+
 type Generator struct {
 	workingDir string
 	noteManager *note.Manager
 }
 
+func IsGenerated(path string) bool {
+	return path == ReadmeFile
+}
+
 func NewGenerator(workingDir string) *Generator {
 	if workingDir == "" {
 		workingDir = "."
@@ -25,15 +35,24 @@ func NewGenerator(workingDir string) *Generator {
 	}
 }
 
+func (g *Generator) SetAssetDir(dir string) {
+	g.noteManager.SetAssetDir(dir)
+}
+
 func (g *Generator) GenerateReadme() error {
 	notes, err := g.noteManager.FindNotes()
 	if err != nil {
 		return fmt.Errorf("failed to find notes: %w", err)
 	}
 	
-	content := g.buildTableOfContents(notes)
+	readmePath := filepath.Join(g.workingDir, ReadmeFile)
+	
+	existing, err := os.ReadFile(readmePath)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read existing readme: %w", err)
+	}
 	
-	readmePath := filepath.Join(g.workingDir, "readme.md")
+	content := g.render(string(existing), notes)
 	
 	if err := os.WriteFile(readmePath, []byte(content), 0644); err != nil {
 		return fmt.Errorf("failed to write readme.md: %w", err)
@@ -42,6 +61,18 @@ func (g *Generator) GenerateReadme() error {
 	return nil
 }
 
+func (g *Generator) render(existing string, notes []note.Note) string {
+	toc := g.buildTableOfContents(notes)
+	
+	start := strings.Index(existing, StartMarker)
+	end := strings.LastIndex(existing, EndMarker)
+	if start == -1 || end < start {
+		return toc
+	}
+	
+	return existing[:start] + StartMarker + "\n" + toc + existing[end:]
+}
+
 func (g *Generator) buildTableOfContents(notes []note.Note) string {
 	var content strings.Builder
 	content.WriteString("# Notes Index\n\n")
@@ -67,7 +98,7 @@ func (g *Generator) buildTableOfContents(notes []note.Note) string {
 		
 		if category == "root" {
 			for _, note := range categoryNotes {
-				link := fmt.Sprintf("[%s](/%s)", note.Title, note.Path)
+				link := noteLink(note)
 				content.WriteString(fmt.Sprintf("%s\n", link))
 			}
 			if len(categoryNotes) > 0 {
@@ -81,6 +112,15 @@ func (g *Generator) buildTableOfContents(notes []note.Note) string {
 	return content.String()
 }
 
+func noteLink(n note.Note) string {
+	title := n.Title
+	if n.Encrypted {
+		title += " (encrypted)"
+	}
+	
+	return fmt.Sprintf("[%s](/%s)", title, n.Path)
+}
+
 func (g *Generator) buildCategorySection(content *strings.Builder, category string, notes []note.Note) {
 	parts := strings.Split(category, string(filepath.Separator))
 	
@@ -103,7 +143,7 @@ func (g *Generator) buildCategorySection(content *strings.Builder, category stri
 	}
 	
 	for _, note := range directNotes {
-		link := fmt.Sprintf("[%s](/%s)", note.Title, note.Path)
+		link := noteLink(note)
 		content.WriteString(fmt.Sprintf("%s\n", link))
 	}
 	
@@ -123,7 +163,7 @@ func (g *Generator) buildCategorySection(content *strings.Builder, category stri
 }
 
 func (g *Generator) IsReadmeUpToDate() (bool, error) {
-	readmePath := filepath.Join(g.workingDir, "readme.md")
+	readmePath := filepath.Join(g.workingDir, ReadmeFile)
 	
 	if _, err := os.Stat(readmePath); os.IsNotExist(err) {
 		return false, nil
@@ -139,7 +179,7 @@ func (g *Generator) IsReadmeUpToDate() (bool, error) {
 		return false, fmt.Errorf("failed to find notes: %w", err)
 	}
 	
-	expectedContent := g.buildTableOfContents(notes)
+	expectedContent := g.render(string(existingContent), notes)
 	
 	return string(existingContent) == expectedContent, nil
 }
\ No newline at end of file
@@ -127,4 +127,73 @@ func TestBuildTableOfContents(t *testing.T) {
 	if !strings.Contains(content, "## work") {
 		t.Error("Expected content to contain work heading")
 	}
+}
+
+func TestIsGenerated(t *testing.T) {
+	if !IsGenerated("readme.md") {
+		t.Error("Expected readme.md to be generated")
+	}
+	
+	if IsGenerated("work/readme.md") || IsGenerated("notes.md") {
+		t.Error("Expected other notes not to be generated")
+	}
+}
+
+func TestGenerateReadmeBetweenMarkers(t *testing.T) {
+	tempDir := t.TempDir()
+	generator := NewGenerator(tempDir)
+	
+	os.WriteFile(filepath.Join(tempDir, "2025-01-01 first.md"), []byte("# first"), 0644)
+	
+	readme := "# Team notes\n\nWelcome.\n\n" + StartMarker + "\nstale index\n" + EndMarker + "\n\nFooter\n"
+	os.WriteFile(filepath.Join(tempDir, ReadmeFile), []byte(readme), 0644)
+	
+	upToDate, err := generator.IsReadmeUpToDate()
+	if err != nil || upToDate {
+		t.Fatalf("Expected a stale readme, got %v, %v", upToDate, err)
+	}
+	
+	if err := generator.GenerateReadme(); err != nil {
+		t.Fatalf("GenerateReadme failed: %v", err)
+	}
+	
+	content, _ := os.ReadFile(filepath.Join(tempDir, ReadmeFile))
+	text := string(content)
+	
+	if !strings.HasPrefix(text, "# Team notes\n\nWelcome.\n\n"+StartMarker+"\n# Notes Index\n") {
+		t.Errorf("Expected the text before the markers to be kept, got:\n%s", text)
+	}
+	if !strings.HasSuffix(text, EndMarker+"\n\nFooter\n") {
+		t.Errorf("Expected the text after the markers to be kept, got:\n%s", text)
+	}
+	if strings.Contains(text, "stale index") || !strings.Contains(text, "[first](/2025-01-01 first.md)") {
+		t.Errorf("Expected the index between the markers to be regenerated, got:\n%s", text)
+	}
+	
+	upToDate, err = generator.IsReadmeUpToDate()
+	if err != nil || !upToDate {
+		t.Errorf("Expected the readme to be up to date, got %v, %v", upToDate, err)
+	}
+}
+
+func TestGenerateReadmeWithEncryptedNotes(t *testing.T) {
+	tempDir := t.TempDir()
+	
+	os.MkdirAll(filepath.Join(tempDir, "oncall"), 0755)
+	os.WriteFile(filepath.Join(tempDir, "oncall", "2025-02-01 database runbook.md.age"), []byte("root password: hunter2"), 0644)
+	
+	generator := NewGenerator(tempDir)
+	if err := generator.GenerateReadme(); err != nil {
+		t.Fatalf("GenerateReadme failed: %v", err)
+	}
+	
+	content, _ := os.ReadFile(filepath.Join(tempDir, ReadmeFile))
+	text := string(content)
+	
+	if !strings.Contains(text, "[database runbook (encrypted)](/oncall/2025-02-01 database runbook.md.age)") {
+		t.Errorf("Expected the encrypted note to be listed by its file name, got:\n%s", text)
+	}
+	if strings.Contains(text, "hunter2") {
+		t.Errorf("Expected the index not to read encrypted content, got:\n%s", text)
+	}
 }
\ No newline at end of file
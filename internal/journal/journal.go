@@ -0,0 +1,135 @@
+package journal
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/template"
+	"time"
+
+	"gitnote/internal/config"
+	"gitnote/internal/note"
+)
+
+const DateFormat = "2006-01-02"
+
+const defaultTemplate = `# {{.Date}}
+
+[« {{.PreviousDate}}]({{.Previous}}) | [{{.NextDate}} »]({{.Next}})
+
+`
+
+type Manager struct {
+	workingDir  string
+	config      config.JournalConfig
+	noteManager *note.Manager
+}
+
+type templateData struct {
+	Date         string
+	Weekday      string
+	Previous     string
+	PreviousDate string
+	Next         string
+	NextDate     string
+}
+
+func NewManager(workingDir string, cfg config.JournalConfig) *Manager {
+	if workingDir == "" {
+		workingDir = "."
+	}
+	return &Manager{
+		workingDir:  workingDir,
+		config:      cfg,
+		noteManager: note.NewManager(workingDir),
+	}
+}
+
+func (m *Manager) Path(date time.Time) string {
+	return filepath.Join(m.config.Category, date.Format("2006"), date.Format("01"), date.Format(DateFormat)+".md")
+}
+
+func (m *Manager) Open(date time.Time) (string, bool, error) {
+	notePath := m.Path(date)
+	fullPath := filepath.Join(m.workingDir, notePath)
+
+	if _, err := os.Stat(fullPath); err == nil {
+		return notePath, false, nil
+	} else if !os.IsNotExist(err) {
+		return "", false, fmt.Errorf("failed to check journal note: %w", err)
+	}
+
+	content, err := m.render(date)
+	if err != nil {
+		return "", false, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+		return "", false, fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	if err := os.WriteFile(fullPath, content, 0644); err != nil {
+		return "", false, fmt.Errorf("failed to create journal note: %w", err)
+	}
+
+	return notePath, true, nil
+}
+
+func (m *Manager) Append(date time.Time, text string) (string, error) {
+	notePath, _, err := m.Open(date)
+	if err != nil {
+		return "", err
+	}
+
+	if err := m.noteManager.AppendToNote(notePath, text); err != nil {
+		return "", err
+	}
+
+	return notePath, nil
+}
+
+func (m *Manager) render(date time.Time) ([]byte, error) {
+	source := defaultTemplate
+
+	if m.config.Template != "" {
+		content, err := os.ReadFile(filepath.Join(m.workingDir, m.config.Template))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read journal template: %w", err)
+		}
+		source = string(content)
+	}
+
+	tmpl, err := template.New("journal").Parse(source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse journal template: %w", err)
+	}
+
+	previous := date.AddDate(0, 0, -1)
+	next := date.AddDate(0, 0, 1)
+
+	data := templateData{
+		Date:         date.Format(DateFormat),
+		Weekday:      date.Weekday().String(),
+		Previous:     m.link(date, previous),
+		PreviousDate: previous.Format(DateFormat),
+		Next:         m.link(date, next),
+		NextDate:     next.Format(DateFormat),
+	}
+
+	var content bytes.Buffer
+	if err := tmpl.Execute(&content, data); err != nil {
+		return nil, fmt.Errorf("failed to render journal template: %w", err)
+	}
+
+	return content.Bytes(), nil
+}
+
+func (m *Manager) link(from, to time.Time) string {
+	relativePath, err := filepath.Rel(filepath.Dir(m.Path(from)), m.Path(to))
+	if err != nil {
+		return filepath.ToSlash("/" + m.Path(to))
+	}
+
+	return filepath.ToSlash(relativePath)
+}
@@ -0,0 +1,115 @@
+package journal
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gitnote/internal/config"
+)
+
+func TestNewManager(t *testing.T) {
+	manager := NewManager("", config.Default().Journal)
+	if manager.workingDir != "." {
+		t.Errorf("Expected working dir to be '.', got %s", manager.workingDir)
+	}
+}
+
+func TestPath(t *testing.T) {
+	manager := NewManager(t.TempDir(), config.Default().Journal)
+
+	date := time.Date(2026, 10, 18, 0, 0, 0, 0, time.Local)
+	expected := filepath.Join("journal", "2026", "10", "2026-10-18.md")
+
+	if path := manager.Path(date); path != expected {
+		t.Errorf("Expected path %s, got %s", expected, path)
+	}
+}
+
+func TestOpenCreatesNoteWithLinks(t *testing.T) {
+	tempDir := t.TempDir()
+	manager := NewManager(tempDir, config.Default().Journal)
+
+	date := time.Date(2026, 10, 1, 0, 0, 0, 0, time.Local)
+
+	notePath, created, err := manager.Open(date)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+
+	if !created {
+		t.Error("Expected journal note to be created")
+	}
+
+	content, err := os.ReadFile(filepath.Join(tempDir, notePath))
+	if err != nil {
+		t.Fatalf("Failed to read journal note: %v", err)
+	}
+
+	expected := "# 2026-10-01\n\n[« 2026-09-30](../09/2026-09-30.md) | [2026-10-02 »](2026-10-02.md)\n\n"
+	if string(content) != expected {
+		t.Errorf("Expected content %q, got %q", expected, string(content))
+	}
+
+	os.WriteFile(filepath.Join(tempDir, notePath), []byte("edited"), 0644)
+
+	_, created, err = manager.Open(date)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+
+	if created {
+		t.Error("Expected existing journal note to be reused")
+	}
+
+	content, _ = os.ReadFile(filepath.Join(tempDir, notePath))
+	if string(content) != "edited" {
+		t.Error("Expected existing journal note not to be overwritten")
+	}
+}
+
+func TestOpenWithTemplate(t *testing.T) {
+	tempDir := t.TempDir()
+	os.Mkdir(filepath.Join(tempDir, "templates"), 0755)
+	os.WriteFile(filepath.Join(tempDir, "templates", "journal.md"), []byte("# {{.Weekday}} {{.Date}}\n\n## Tasks\n"), 0644)
+
+	manager := NewManager(tempDir, config.JournalConfig{Category: "diary", Template: "templates/journal.md"})
+
+	notePath, _, err := manager.Open(time.Date(2026, 10, 18, 0, 0, 0, 0, time.Local))
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+
+	if !strings.HasPrefix(notePath, "diary") {
+		t.Errorf("Expected note in configured category, got %s", notePath)
+	}
+
+	content, _ := os.ReadFile(filepath.Join(tempDir, notePath))
+	if string(content) != "# Sunday 2026-10-18\n\n## Tasks\n" {
+		t.Errorf("Unexpected templated content: %q", string(content))
+	}
+
+	manager = NewManager(tempDir, config.JournalConfig{Category: "diary", Template: "templates/missing.md"})
+	if _, _, err := manager.Open(time.Date(2026, 10, 19, 0, 0, 0, 0, time.Local)); err == nil {
+		t.Error("Expected error for missing template")
+	}
+}
+
+func TestAppend(t *testing.T) {
+	tempDir := t.TempDir()
+	manager := NewManager(tempDir, config.Default().Journal)
+
+	date := time.Date(2026, 10, 18, 0, 0, 0, 0, time.Local)
+
+	notePath, err := manager.Append(date, "Called the bank")
+	if err != nil {
+		t.Fatalf("Append failed: %v", err)
+	}
+
+	content, _ := os.ReadFile(filepath.Join(tempDir, notePath))
+	if !strings.HasPrefix(string(content), "# 2026-10-18") || !strings.HasSuffix(string(content), "\nCalled the bank\n") {
+		t.Errorf("Unexpected content after append: %q", string(content))
+	}
+}
@@ -0,0 +1,130 @@
+package note
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const frontMatterDelimiter = "---"
+
+type FrontMatter struct {
+	Title string                 `yaml:"title,omitempty"`
+	Date  string                 `yaml:"date,omitempty"`
+	Tags  Tags                   `yaml:"tags,omitempty"`
+	Extra map[string]interface{} `yaml:",inline"`
+}
+
+type Tags []string
+
+func (t *Tags) UnmarshalYAML(value *yaml.Node) error {
+	switch value.Kind {
+	case yaml.ScalarNode:
+		*t = splitTags(value.Value)
+		return nil
+	case yaml.SequenceNode:
+		var tags []string
+		if err := value.Decode(&tags); err != nil {
+			return err
+		}
+		*t = nil
+		for _, tag := range tags {
+			tag = strings.TrimPrefix(strings.TrimSpace(tag), "#")
+			if tag != "" {
+				*t = append(*t, tag)
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("tags must be a string or a list")
+	}
+}
+
+func (t Tags) MarshalYAML() (interface{}, error) {
+	node := &yaml.Node{Kind: yaml.SequenceNode, Style: yaml.FlowStyle}
+
+	for _, tag := range t {
+		node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: tag})
+	}
+
+	return node, nil
+}
+
+func splitTags(value string) []string {
+	var tags []string
+
+	for _, tag := range strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ' ' }) {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "#")
+		if tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+
+	return tags
+}
+
+func ParseFrontMatter(content string) (FrontMatter, string, error) {
+	var frontMatter FrontMatter
+
+	normalised := strings.ReplaceAll(content, "\r\n", "\n")
+	if !strings.HasPrefix(normalised, frontMatterDelimiter+"\n") {
+		return frontMatter, content, nil
+	}
+
+	rest := normalised[len(frontMatterDelimiter)+1:]
+
+	var raw, body string
+	if strings.HasPrefix(rest, frontMatterDelimiter+"\n") || rest == frontMatterDelimiter {
+		body = strings.TrimPrefix(strings.TrimPrefix(rest, frontMatterDelimiter), "\n")
+	} else {
+		end := strings.Index(rest, "\n"+frontMatterDelimiter+"\n")
+		if end < 0 {
+			if !strings.HasSuffix(rest, "\n"+frontMatterDelimiter) {
+				return frontMatter, content, nil
+			}
+			end = len(rest) - len(frontMatterDelimiter) - 1
+		}
+
+		raw = rest[:end]
+		body = strings.TrimPrefix(rest[end+1+len(frontMatterDelimiter):], "\n")
+	}
+
+	if err := yaml.Unmarshal([]byte(raw), &frontMatter); err != nil {
+		return FrontMatter{}, content, fmt.Errorf("failed to parse front matter: %w", err)
+	}
+
+	return frontMatter, body, nil
+}
+
+func (f FrontMatter) IsEmpty() bool {
+	return f.Title == "" && f.Date == "" && len(f.Tags) == 0 && len(f.Extra) == 0
+}
+
+func (f FrontMatter) HasTag(tag string) bool {
+	tag = strings.TrimPrefix(tag, "#")
+
+	for _, existing := range f.Tags {
+		if strings.EqualFold(existing, tag) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (f FrontMatter) String() string {
+	if f.IsEmpty() {
+		return ""
+	}
+
+	var data strings.Builder
+	encoder := yaml.NewEncoder(&data)
+	encoder.SetIndent(2)
+
+	if err := encoder.Encode(f); err != nil {
+		return ""
+	}
+
+	return fmt.Sprintf("%s\n%s%s\n", frontMatterDelimiter, data.String(), frontMatterDelimiter)
+}
@@ -0,0 +1,97 @@
+package note
+
+import (
+	"testing"
+)
+
+func TestParseFrontMatter(t *testing.T) {
+	content := "---\ntitle: managing expectations\ntags: [work, management]\naliases: expectations\n---\n# managing expectations\n"
+
+	frontMatter, body, err := ParseFrontMatter(content)
+	if err != nil {
+		t.Fatalf("ParseFrontMatter failed: %v", err)
+	}
+
+	if frontMatter.Title != "managing expectations" {
+		t.Errorf("Expected title 'managing expectations', got %s", frontMatter.Title)
+	}
+
+	if len(frontMatter.Tags) != 2 || frontMatter.Tags[0] != "work" || frontMatter.Tags[1] != "management" {
+		t.Errorf("Expected tags [work management], got %v", frontMatter.Tags)
+	}
+
+	if frontMatter.Extra["aliases"] != "expectations" {
+		t.Errorf("Expected unknown keys to be preserved, got %v", frontMatter.Extra)
+	}
+
+	if body != "# managing expectations\n" {
+		t.Errorf("Expected body without front matter, got %q", body)
+	}
+}
+
+func TestParseFrontMatterTagString(t *testing.T) {
+	frontMatter, _, err := ParseFrontMatter("---\ntags: \"#work, ideas\"\n---\n")
+	if err != nil {
+		t.Fatalf("ParseFrontMatter failed: %v", err)
+	}
+
+	if len(frontMatter.Tags) != 2 || frontMatter.Tags[0] != "work" || frontMatter.Tags[1] != "ideas" {
+		t.Errorf("Expected tags [work ideas], got %v", frontMatter.Tags)
+	}
+
+	if !frontMatter.HasTag("#Work") {
+		t.Error("Expected HasTag to match case-insensitively and ignore #")
+	}
+}
+
+func TestParseFrontMatterWithoutFrontMatter(t *testing.T) {
+	content := "# note\n\n---\n\ntext"
+
+	frontMatter, body, err := ParseFrontMatter(content)
+	if err != nil {
+		t.Fatalf("ParseFrontMatter failed: %v", err)
+	}
+
+	if !frontMatter.IsEmpty() {
+		t.Errorf("Expected empty front matter, got %+v", frontMatter)
+	}
+
+	if body != content {
+		t.Errorf("Expected body to be unchanged, got %q", body)
+	}
+}
+
+func TestParseFrontMatterInvalid(t *testing.T) {
+	content := "---\ntags: [unclosed\n---\nbody"
+
+	_, body, err := ParseFrontMatter(content)
+	if err == nil {
+		t.Error("Expected error for invalid front matter")
+	}
+
+	if body != content {
+		t.Errorf("Expected original content on error, got %q", body)
+	}
+}
+
+func TestFrontMatterString(t *testing.T) {
+	frontMatter := FrontMatter{Tags: Tags{"work", "on call"}}
+
+	expected := "---\ntags: [work, on call]\n---\n"
+	if frontMatter.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, frontMatter.String())
+	}
+
+	if (FrontMatter{}).String() != "" {
+		t.Error("Expected empty front matter to render as an empty string")
+	}
+
+	parsed, _, err := ParseFrontMatter(frontMatter.String())
+	if err != nil {
+		t.Fatalf("ParseFrontMatter failed: %v", err)
+	}
+
+	if len(parsed.Tags) != 2 || parsed.Tags[1] != "on call" {
+		t.Errorf("Expected tags to round trip, got %v", parsed.Tags)
+	}
+}
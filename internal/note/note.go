@@ -1,30 +1,94 @@
 package note
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
+	"sync"
 	"time"
+
+	"gitnote/internal/git"
+)
+
+const (
+	EncryptedExt    = ".age"
+	DefaultAssetDir = "assets"
 )
 
+var ErrEncrypted = errors.New("note is encrypted")
+
 type Note struct {
-	Title    string
-	Path     string
-	Category string
-	Date     time.Time
+	Title     string    `json:"title"`
+	Path      string    `json:"path"`
+	Category  string    `json:"category"`
+	Date      time.Time `json:"date"`
+	Created   time.Time `json:"created"`
+	Updated   time.Time `json:"updated"`
+	Encrypted bool      `json:"encrypted,omitempty"`
 }
 
+type Decrypter func(ciphertext []byte) ([]byte, error)
+
 type Manager struct {
 	workingDir string
+	assetDir   string
+	gitManager *git.Manager
+	gitContext context.Context
+	decrypt    Decrypter
+	
+	historyMu   sync.Mutex
+	historyHead string
+	history     map[string]git.FileDates
 }
 
 func NewManager(workingDir string) *Manager {
 	if workingDir == "" {
 		workingDir = "."
 	}
-	return &Manager{workingDir: workingDir}
+	return &Manager{workingDir: workingDir, assetDir: DefaultAssetDir}
+}
+
+func (m *Manager) isCategoryDir(entry os.DirEntry) bool {
+	return entry.IsDir() && !strings.HasPrefix(entry.Name(), ".") && entry.Name() != m.assetDir
+}
+
+func (m *Manager) SetAssetDir(dir string) {
+	m.assetDir = dir
+}
+
+func (m *Manager) SetGitHistory(ctx context.Context, gitManager *git.Manager) {
+	m.gitContext = ctx
+	m.gitManager = gitManager
+}
+
+func (m *Manager) SetDecrypter(decrypt Decrypter) {
+	m.decrypt = decrypt
+}
+
+func (m *Manager) ReadNote(notePath string) ([]byte, error) {
+	content, err := os.ReadFile(filepath.Join(m.workingDir, notePath))
+	if err != nil {
+		return nil, err
+	}
+	
+	if !IsEncryptedPath(notePath) {
+		return content, nil
+	}
+	
+	if m.decrypt == nil {
+		return nil, fmt.Errorf("%s: %w", notePath, ErrEncrypted)
+	}
+	
+	plaintext, err := m.decrypt(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt %s: %w", notePath, err)
+	}
+	
+	return plaintext, nil
 }
 
 func (m *Manager) GetCategories() ([]string, error) {
@@ -36,7 +100,7 @@ func (m *Manager) GetCategories() ([]string, error) {
 	}
 	
 	for _, entry := range entries {
-		if entry.IsDir() && !strings.HasPrefix(entry.Name(), ".") {
+		if m.isCategoryDir(entry) {
 			categories = append(categories, entry.Name())
 		}
 	}
@@ -55,7 +119,7 @@ func (m *Manager) GetSubcategories(category string) ([]string, error) {
 	}
 	
 	for _, entry := range entries {
-		if entry.IsDir() && !strings.HasPrefix(entry.Name(), ".") {
+		if m.isCategoryDir(entry) {
 			subcategories = append(subcategories, entry.Name())
 		}
 	}
@@ -70,8 +134,7 @@ func (m *Manager) CreateCategory(path string) error {
 }
 
 func (m *Manager) CreateNote(categoryPath, title string) (string, error) {
-	now := time.Now()
-	filename := fmt.Sprintf("%s %s.md", now.Format("2006-01-02"), title)
+	filename := Filename(time.Now(), title)
 	
 	fullPath := filepath.Join(m.workingDir, categoryPath, filename)
 	
@@ -93,6 +156,72 @@ func (m *Manager) CreateNote(categoryPath, title string) (string, error) {
 	return relativePath, nil
 }
 
+func (m *Manager) AppendToNote(notePath, text string) error {
+	if IsEncryptedPath(notePath) {
+		return fmt.Errorf("cannot append to %s: %w", notePath, ErrEncrypted)
+	}
+	
+	fullPath := filepath.Join(m.workingDir, notePath)
+	
+	content, err := os.ReadFile(fullPath)
+	if err != nil {
+		return fmt.Errorf("failed to read note: %w", err)
+	}
+	
+	var addition strings.Builder
+	if len(content) > 0 && !strings.HasSuffix(string(content), "\n") {
+		addition.WriteString("\n")
+	}
+	addition.WriteString(strings.TrimRight(text, "\n"))
+	addition.WriteString("\n")
+	
+	file, err := os.OpenFile(fullPath, os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open note: %w", err)
+	}
+	defer file.Close()
+	
+	if _, err := file.WriteString(addition.String()); err != nil {
+		return fmt.Errorf("failed to append to note: %w", err)
+	}
+	
+	return nil
+}
+
+func Filename(date time.Time, title string) string {
+	return fmt.Sprintf("%s %s.md", date.Format("2006-01-02"), title)
+}
+
+func ParseFilename(filename string) (string, time.Time, bool) {
+	filename = strings.TrimSuffix(filename, EncryptedExt)
+	name := strings.TrimSuffix(filename, filepath.Ext(filename))
+	
+	if len(name) < 10 {
+		return name, time.Time{}, false
+	}
+	
+	date, err := time.ParseInLocation("2006-01-02", name[:10], time.Local)
+	if err != nil || (len(name) > 10 && name[10] != ' ') {
+		return name, time.Time{}, false
+	}
+	
+	title := strings.TrimSpace(name[10:])
+	if title == "" {
+		title = name
+	}
+	
+	return title, date, true
+}
+
+func IsNotePath(relativePath string) bool {
+	lower := strings.ToLower(relativePath)
+	return (strings.HasSuffix(lower, ".md") || strings.HasSuffix(lower, ".md"+EncryptedExt)) && !strings.HasPrefix(relativePath, ".")
+}
+
+func IsEncryptedPath(relativePath string) bool {
+	return strings.HasSuffix(strings.ToLower(relativePath), ".md"+EncryptedExt)
+}
+
 func (m *Manager) FindNotes() ([]Note, error) {
 	var notes []Note
 	
@@ -101,19 +230,24 @@ func (m *Manager) FindNotes() ([]Note, error) {
 			return err
 		}
 		
-		if !info.IsDir() && strings.HasSuffix(strings.ToLower(path), ".md") {
+		if info.IsDir() && info.Name() == m.assetDir && path != m.workingDir {
+			return filepath.SkipDir
+		}
+		
+		if !info.IsDir() && (strings.HasSuffix(strings.ToLower(path), ".md") || IsEncryptedPath(path)) {
 			relativePath, err := filepath.Rel(m.workingDir, path)
 			if err != nil {
 				return err
 			}
 			
-			if strings.HasPrefix(relativePath, ".") {
+			if !IsNotePath(relativePath) {
 				return nil
 			}
 			
 			note := Note{
-				Path: relativePath,
-				Date: info.ModTime(),
+				Path:      relativePath,
+				Date:      info.ModTime(),
+				Encrypted: IsEncryptedPath(relativePath),
 			}
 			
 			dirPath := filepath.Dir(relativePath)
@@ -121,13 +255,10 @@ func (m *Manager) FindNotes() ([]Note, error) {
 				note.Category = dirPath
 			}
 			
-			filename := filepath.Base(relativePath)
-			filename = strings.TrimSuffix(filename, ".md")
-			
-			if len(filename) > 10 && filename[4] == '-' && filename[7] == '-' {
-				note.Title = strings.TrimSpace(filename[11:])
-			} else {
-				note.Title = filename
+			title, date, hasDate := ParseFilename(filepath.Base(relativePath))
+			note.Title = title
+			if hasDate {
+				note.Date = date
 			}
 			
 			notes = append(notes, note)
@@ -144,9 +275,101 @@ func (m *Manager) FindNotes() ([]Note, error) {
 		return notes[i].Path < notes[j].Path
 	})
 	
+	if err := m.applyGitHistory(notes); err != nil {
+		return nil, err
+	}
+	
 	return notes, nil
 }
 
+func (m *Manager) applyGitHistory(notes []Note) error {
+	if m.gitManager == nil {
+		return nil
+	}
+	
+	dates, err := m.fileDates(m.gitContext)
+	if err != nil {
+		return fmt.Errorf("failed to read note history: %w", err)
+	}
+	
+	for i := range notes {
+		history, ok := dates[notes[i].Path]
+		if !ok {
+			continue
+		}
+		
+		notes[i].Created = history.Created
+		notes[i].Updated = history.Updated
+		
+		if _, _, hasDate := ParseFilename(filepath.Base(notes[i].Path)); !hasDate {
+			notes[i].Date = history.Created
+		}
+	}
+	
+	return nil
+}
+
+func (m *Manager) fileDates(ctx context.Context) (map[string]git.FileDates, error) {
+	if !m.gitManager.IsGitRepo(ctx) {
+		return nil, nil
+	}
+	
+	head, err := m.gitManager.Head(ctx)
+	if err != nil || head == "" {
+		return nil, err
+	}
+	
+	m.historyMu.Lock()
+	defer m.historyMu.Unlock()
+	
+	if m.history != nil && m.historyHead == head {
+		return m.history, nil
+	}
+	
+	dates, err := m.gitManager.FileDates(ctx)
+	if err != nil {
+		return nil, err
+	}
+	
+	m.historyHead = head
+	m.history = dates
+	return dates, nil
+}
+
+func (m *Manager) FindNotesInCategory(category string) ([]Note, error) {
+	allNotes, err := m.FindNotes()
+	if err != nil {
+		return nil, err
+	}
+	
+	return FilterByCategory(allNotes, category), nil
+}
+
+func FilterByCategory(notes []Note, category string) []Note {
+	if filepath.Clean(category) == "." {
+		return notes
+	}
+	
+	var filtered []Note
+	
+	for _, note := range notes {
+		if InCategory(note.Category, category) {
+			filtered = append(filtered, note)
+		}
+	}
+	
+	return filtered
+}
+
+func InCategory(noteCategory, category string) bool {
+	category = filepath.Clean(category)
+	if category == "." {
+		return true
+	}
+	
+	return noteCategory == category || strings.HasPrefix(noteCategory, category+string(filepath.Separator))
+}
+
 func (m *Manager) SearchNotes(query string, searchContent bool) ([]Note, error) {
 	allNotes, err := m.FindNotes()
 	if err != nil {
@@ -165,9 +388,11 @@ func (m *Manager) SearchNotes(query string, searchContent bool) ([]Note, error)
 		}
 		
 		if searchContent {
-			fullPath := filepath.Join(m.workingDir, note.Path)
-			content, err := os.ReadFile(fullPath)
+			content, err := m.ReadNote(note.Path)
 			if err != nil {
+				if note.Encrypted && m.decrypt != nil {
+					return nil, err
+				}
 				continue
 			}
 			
@@ -1,10 +1,16 @@
 package note
 
 import (
+	"context"
+	"errors"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
+
+	"gitnote/internal/git"
 )
 
 func TestNewManager(t *testing.T) {
@@ -140,6 +146,9 @@ func TestFindNotes(t *testing.T) {
 	if note1.Category != "" {
 		t.Errorf("Expected empty category, got %s", note1.Category)
 	}
+	if note1.Date.Format("2006-01-02") != "2025-01-01" {
+		t.Errorf("Expected date from filename 2025-01-01, got %s", note1.Date.Format("2006-01-02"))
+	}
 	
 	note2 := notes[1]
 	if note2.Title != "work note" {
@@ -193,4 +202,268 @@ func TestSearchNotes(t *testing.T) {
 	if len(results) != 0 {
 		t.Fatalf("Expected 0 results for nonexistent search, got %d", len(results))
 	}
+}
+
+func TestFindNotesInCategory(t *testing.T) {
+	tempDir := t.TempDir()
+	manager := NewManager(tempDir)
+	
+	os.MkdirAll(filepath.Join(tempDir, "work", "meetings"), 0755)
+	os.Mkdir(filepath.Join(tempDir, "workshop"), 0755)
+	
+	os.WriteFile(filepath.Join(tempDir, "2025-01-01 root note.md"), []byte("# root note"), 0644)
+	os.WriteFile(filepath.Join(tempDir, "work", "2025-01-02 work note.md"), []byte("# work note"), 0644)
+	os.WriteFile(filepath.Join(tempDir, "work", "meetings", "2025-01-03 standup.md"), []byte("# standup"), 0644)
+	os.WriteFile(filepath.Join(tempDir, "workshop", "2025-01-04 bench.md"), []byte("# bench"), 0644)
+	
+	notes, err := manager.FindNotesInCategory("work")
+	if err != nil {
+		t.Fatalf("FindNotesInCategory failed: %v", err)
+	}
+	
+	if len(notes) != 2 {
+		t.Fatalf("Expected 2 notes in work and its subcategories, got %d", len(notes))
+	}
+	
+	notes, err = manager.FindNotesInCategory("")
+	if err != nil {
+		t.Fatalf("FindNotesInCategory failed: %v", err)
+	}
+	
+	if len(notes) != 4 {
+		t.Fatalf("Expected all 4 notes for empty category, got %d", len(notes))
+	}
+}
+
+func TestParseFilename(t *testing.T) {
+	tests := []struct {
+		filename string
+		title    string
+		date     string
+		ok       bool
+	}{
+		{"2025-01-05 managing expectations.md", "managing expectations", "2025-01-05", true},
+		{"2026-10-18.md", "2026-10-18", "2026-10-18", true},
+		{"managing expectations.md", "managing expectations", "", false},
+		{"2025-13-45 invalid date.md", "2025-13-45 invalid date", "", false},
+		{"2025-01-05-no-space.md", "2025-01-05-no-space", "", false},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.filename, func(t *testing.T) {
+			title, date, ok := ParseFilename(tt.filename)
+			if title != tt.title {
+				t.Errorf("Expected title %q, got %q", tt.title, title)
+			}
+			if ok != tt.ok {
+				t.Errorf("Expected ok %v, got %v", tt.ok, ok)
+			}
+			if ok && date.Format("2006-01-02") != tt.date {
+				t.Errorf("Expected date %s, got %s", tt.date, date.Format("2006-01-02"))
+			}
+		})
+	}
+}
+
+
+func TestFindNotesDateFallback(t *testing.T) {
+	tempDir := t.TempDir()
+	manager := NewManager(tempDir)
+	
+	notePath := filepath.Join(tempDir, "undated note.md")
+	os.WriteFile(notePath, []byte("# undated note"), 0644)
+	
+	modified := time.Date(2024, 6, 1, 12, 0, 0, 0, time.Local)
+	os.Chtimes(notePath, modified, modified)
+	
+	notes, err := manager.FindNotes()
+	if err != nil {
+		t.Fatalf("FindNotes failed: %v", err)
+	}
+	
+	if !notes[0].Date.Equal(modified) {
+		t.Errorf("Expected undated note to fall back to modification time, got %s", notes[0].Date)
+	}
+}
+
+func TestFindNotesWithGitHistory(t *testing.T) {
+	tempDir := t.TempDir()
+	
+	for _, args := range [][]string{
+		{"init"},
+		{"config", "user.email", "test@example.com"},
+		{"config", "user.name", "Test User"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = tempDir
+		if err := cmd.Run(); err != nil {
+			t.Fatalf("Failed to run git %v: %v", args, err)
+		}
+	}
+	
+	os.WriteFile(filepath.Join(tempDir, "2025-01-01 dated note.md"), []byte("# dated note"), 0644)
+	os.WriteFile(filepath.Join(tempDir, "undated note.md"), []byte("# undated note"), 0644)
+	
+	for _, args := range [][]string{{"add", "-A"}, {"commit", "-m", "Add notes"}} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = tempDir
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_DATE=@1700000000 +0000", "GIT_COMMITTER_DATE=@1700000000 +0000")
+		if err := cmd.Run(); err != nil {
+			t.Fatalf("Failed to run git %v: %v", args, err)
+		}
+	}
+	
+	manager := NewManager(tempDir)
+	manager.SetGitHistory(context.Background(), git.NewManager(tempDir))
+	
+	notes, err := manager.FindNotes()
+	if err != nil {
+		t.Fatalf("FindNotes failed: %v", err)
+	}
+	
+	if len(notes) != 2 {
+		t.Fatalf("Expected 2 notes, got %d", len(notes))
+	}
+	
+	if notes[0].Created.Unix() != 1700000000 || notes[0].Updated.Unix() != 1700000000 {
+		t.Errorf("Expected git dates for dated note, got %+v", notes[0])
+	}
+	
+	if notes[0].Date.Format("2006-01-02") != "2025-01-01" {
+		t.Errorf("Expected filename date to take precedence, got %s", notes[0].Date)
+	}
+	
+	if notes[1].Date.Unix() != 1700000000 {
+		t.Errorf("Expected undated note to use first commit date, got %s", notes[1].Date)
+	}
+	
+	cachedHead := manager.historyHead
+	os.WriteFile(filepath.Join(tempDir, "2025-01-01 dated note.md"), []byte("# dated note\n\nmore"), 0644)
+	cmd := exec.Command("git", "commit", "-am", "Edit dated note")
+	cmd.Dir = tempDir
+	cmd.Env = append(os.Environ(), "GIT_AUTHOR_DATE=@1700000500 +0000", "GIT_COMMITTER_DATE=@1700000500 +0000")
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("Failed to commit: %v", err)
+	}
+	
+	notes, err = manager.FindNotes()
+	if err != nil {
+		t.Fatalf("FindNotes failed: %v", err)
+	}
+	
+	if manager.historyHead == cachedHead || notes[0].Updated.Unix() != 1700000500 {
+		t.Errorf("Expected a new commit to refresh the cached history, got %+v", notes[0])
+	}
+}
+
+
+func TestAppendToNote(t *testing.T) {
+	tempDir := t.TempDir()
+	manager := NewManager(tempDir)
+	
+	os.WriteFile(filepath.Join(tempDir, "note.md"), []byte("# note"), 0644)
+	
+	if err := manager.AppendToNote("note.md", "first line"); err != nil {
+		t.Fatalf("AppendToNote failed: %v", err)
+	}
+	
+	if err := manager.AppendToNote("note.md", "second line\n"); err != nil {
+		t.Fatalf("AppendToNote failed: %v", err)
+	}
+	
+	content, _ := os.ReadFile(filepath.Join(tempDir, "note.md"))
+	expected := "# note\nfirst line\nsecond line\n"
+	if string(content) != expected {
+		t.Errorf("Expected content %q, got %q", expected, string(content))
+	}
+	
+	if err := manager.AppendToNote("missing.md", "text"); err == nil {
+		t.Error("Expected error when appending to a missing note")
+	}
+}
+
+func TestFindNotesSkipsAssetDirectories(t *testing.T) {
+	tempDir := t.TempDir()
+	
+	os.MkdirAll(filepath.Join(tempDir, "work", "assets"), 0755)
+	os.MkdirAll(filepath.Join(tempDir, "assets"), 0755)
+	os.MkdirAll(filepath.Join(tempDir, "personal", "_files"), 0755)
+	os.WriteFile(filepath.Join(tempDir, "work", "plan.md"), []byte("# plan"), 0644)
+	os.WriteFile(filepath.Join(tempDir, "work", "assets", "attached.md"), []byte("# attached"), 0644)
+	os.WriteFile(filepath.Join(tempDir, "assets", "readme-copy.md"), []byte("# copy"), 0644)
+	os.WriteFile(filepath.Join(tempDir, "personal", "_files", "kept.md"), []byte("# kept"), 0644)
+	
+	manager := NewManager(tempDir)
+	
+	notes, err := manager.FindNotes()
+	if err != nil {
+		t.Fatalf("FindNotes failed: %v", err)
+	}
+	if len(notes) != 2 || notes[0].Path != filepath.Join("personal", "_files", "kept.md") || notes[1].Path != filepath.Join("work", "plan.md") {
+		t.Errorf("Expected asset directories to be skipped, got %+v", notes)
+	}
+	
+	categories, _ := manager.GetCategories()
+	if len(categories) != 2 || categories[0] != "personal" || categories[1] != "work" {
+		t.Errorf("Expected assets not to be a category, got %v", categories)
+	}
+	
+	manager.SetAssetDir("_files")
+	
+	subcategories, _ := manager.GetSubcategories("personal")
+	if len(subcategories) != 0 {
+		t.Errorf("Expected the configured asset directory to be skipped, got %v", subcategories)
+	}
+	
+	notes, _ = manager.FindNotes()
+	if len(notes) != 3 {
+		t.Errorf("Expected notes in assets/ once another asset directory is configured, got %+v", notes)
+	}
+}
+
+func TestEncryptedNotes(t *testing.T) {
+	tempDir := t.TempDir()
+	
+	os.MkdirAll(filepath.Join(tempDir, "oncall"), 0755)
+	os.WriteFile(filepath.Join(tempDir, "oncall", "2025-02-01 database runbook.md.age"), []byte("ciphertext"), 0644)
+	os.WriteFile(filepath.Join(tempDir, "oncall", "escalation.md"), []byte("# escalation"), 0644)
+	
+	manager := NewManager(tempDir)
+	
+	notes, err := manager.FindNotes()
+	if err != nil {
+		t.Fatalf("FindNotes failed: %v", err)
+	}
+	if len(notes) != 2 || !notes[0].Encrypted || notes[1].Encrypted {
+		t.Fatalf("Expected one encrypted and one plain note, got %+v", notes)
+	}
+	if notes[0].Title != "database runbook" || notes[0].Date.Format("2006-01-02") != "2025-02-01" {
+		t.Errorf("Expected the title and date from the file name, got %+v", notes[0])
+	}
+	
+	if _, err := manager.ReadNote(notes[0].Path); !errors.Is(err, ErrEncrypted) {
+		t.Errorf("Expected ErrEncrypted without a decrypter, got %v", err)
+	}
+	if err := manager.AppendToNote(notes[0].Path, "more"); !errors.Is(err, ErrEncrypted) {
+		t.Errorf("Expected appending to an encrypted note to fail, got %v", err)
+	}
+	
+	results, _ := manager.SearchNotes("password", true)
+	if len(results) != 0 {
+		t.Errorf("Expected encrypted content to stay unsearchable, got %+v", results)
+	}
+	
+	manager.SetDecrypter(func(ciphertext []byte) ([]byte, error) {
+		return []byte("# database runbook\n\nroot password: hunter2\n"), nil
+	})
+	
+	content, err := manager.ReadNote(notes[0].Path)
+	if err != nil || !strings.Contains(string(content), "hunter2") {
+		t.Errorf("Expected decrypted content, got %q, %v", content, err)
+	}
+	
+	results, _ = manager.SearchNotes("password", true)
+	if len(results) != 1 || results[0].Path != notes[0].Path {
+		t.Errorf("Expected the decrypted note to match, got %+v", results)
+	}
 }
\ No newline at end of file
@@ -0,0 +1,183 @@
+package notebook
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+
+	"gitnote/internal/note"
+)
+
+const FileName = "notebooks.yaml"
+
+type Notebook struct {
+	Name string `yaml:"name"`
+	Path string `yaml:"path"`
+}
+
+type Result struct {
+	Notebook string
+	Note     note.Note
+}
+
+type Registry struct {
+	Default   string     `yaml:"default,omitempty"`
+	Notebooks []Notebook `yaml:"notebooks"`
+	path      string
+}
+
+func DefaultPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate user config directory: %w", err)
+	}
+	return filepath.Join(dir, "gitnote", FileName), nil
+}
+
+func Load(path string) (*Registry, error) {
+	registry := &Registry{path: path}
+
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return registry, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	if err := yaml.Unmarshal(content, registry); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	return registry, nil
+}
+
+func (r *Registry) Save() error {
+	content, err := yaml.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("failed to encode notebooks: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(r.path), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	if err := os.WriteFile(r.path, content, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", r.path, err)
+	}
+
+	return nil
+}
+
+func (r *Registry) Get(name string) (Notebook, bool) {
+	for _, notebook := range r.Notebooks {
+		if notebook.Name == name {
+			return notebook, true
+		}
+	}
+	return Notebook{}, false
+}
+
+func (r *Registry) DefaultNotebook() (Notebook, bool) {
+	if r.Default == "" {
+		return Notebook{}, false
+	}
+	return r.Get(r.Default)
+}
+
+func (r *Registry) Add(name, path string) (Notebook, error) {
+	if name == "" {
+		return Notebook{}, fmt.Errorf("notebook name cannot be empty")
+	}
+	if _, exists := r.Get(name); exists {
+		return Notebook{}, fmt.Errorf("notebook %q already exists", name)
+	}
+
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return Notebook{}, fmt.Errorf("failed to resolve %s: %w", path, err)
+	}
+
+	info, err := os.Stat(abs)
+	if err != nil || !info.IsDir() {
+		return Notebook{}, fmt.Errorf("%s is not a directory", abs)
+	}
+
+	notebook := Notebook{Name: name, Path: abs}
+	r.Notebooks = append(r.Notebooks, notebook)
+	sort.Slice(r.Notebooks, func(i, j int) bool {
+		return r.Notebooks[i].Name < r.Notebooks[j].Name
+	})
+
+	if r.Default == "" {
+		r.Default = name
+	}
+
+	return notebook, nil
+}
+
+func (r *Registry) Remove(name string) error {
+	for i, notebook := range r.Notebooks {
+		if notebook.Name == name {
+			r.Notebooks = append(r.Notebooks[:i], r.Notebooks[i+1:]...)
+			if r.Default == name {
+				r.Default = ""
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("no notebook named %q", name)
+}
+
+func (r *Registry) SetDefault(name string) error {
+	if _, exists := r.Get(name); !exists {
+		return fmt.Errorf("no notebook named %q", name)
+	}
+	r.Default = name
+	return nil
+}
+
+func Search(notebooks []Notebook, open func(Notebook) (*note.Manager, error), query string, searchContent bool) ([]Result, error) {
+	if open == nil {
+		open = func(notebook Notebook) (*note.Manager, error) {
+			return note.NewManager(notebook.Path), nil
+		}
+	}
+
+	found := make([][]note.Note, len(notebooks))
+	failures := make([]error, len(notebooks))
+
+	var wg sync.WaitGroup
+	for i, notebook := range notebooks {
+		wg.Add(1)
+		go func(i int, notebook Notebook) {
+			defer wg.Done()
+			noteManager, err := open(notebook)
+			if err != nil {
+				failures[i] = fmt.Errorf("notebook %s: %w", notebook.Name, err)
+				return
+			}
+			notes, err := noteManager.SearchNotes(query, searchContent)
+			if err != nil {
+				failures[i] = fmt.Errorf("notebook %s: %w", notebook.Name, err)
+				return
+			}
+			found[i] = notes
+		}(i, notebook)
+	}
+	wg.Wait()
+
+	var results []Result
+	for i, notebook := range notebooks {
+		for _, n := range found[i] {
+			results = append(results, Result{Notebook: notebook.Name, Note: n})
+		}
+	}
+
+	return results, errors.Join(failures...)
+}
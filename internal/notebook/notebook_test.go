@@ -0,0 +1,142 @@
+package notebook
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gitnote/internal/note"
+)
+
+func TestLoadMissing(t *testing.T) {
+	registry, err := Load(filepath.Join(t.TempDir(), FileName))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if len(registry.Notebooks) != 0 || registry.Default != "" {
+		t.Errorf("Expected an empty registry, got %+v", registry)
+	}
+}
+
+func TestAddSaveAndLoad(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "gitnote", FileName)
+	personal := t.TempDir()
+	work := t.TempDir()
+
+	registry, _ := Load(configPath)
+
+	if _, err := registry.Add("work", work); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	if _, err := registry.Add("personal", personal); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+
+	if _, err := registry.Add("work", personal); err == nil {
+		t.Error("Expected an error for a duplicate notebook")
+	}
+	if _, err := registry.Add("missing", filepath.Join(work, "missing")); err == nil {
+		t.Error("Expected an error for a missing directory")
+	}
+
+	if err := registry.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	loaded, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if len(loaded.Notebooks) != 2 || loaded.Notebooks[0].Name != "personal" || loaded.Notebooks[1].Name != "work" {
+		t.Fatalf("Expected notebooks sorted by name, got %+v", loaded.Notebooks)
+	}
+
+	defaultNotebook, ok := loaded.DefaultNotebook()
+	if !ok || defaultNotebook.Name != "work" || defaultNotebook.Path != work {
+		t.Errorf("Expected the first notebook added to be the default, got %+v", defaultNotebook)
+	}
+}
+
+func TestSetDefaultAndRemove(t *testing.T) {
+	registry, _ := Load(filepath.Join(t.TempDir(), FileName))
+	registry.Add("work", t.TempDir())
+	registry.Add("personal", t.TempDir())
+
+	if err := registry.SetDefault("personal"); err != nil {
+		t.Fatalf("SetDefault failed: %v", err)
+	}
+	if registry.Default != "personal" {
+		t.Errorf("Expected default personal, got %s", registry.Default)
+	}
+	if err := registry.SetDefault("missing"); err == nil {
+		t.Error("Expected an error for an unknown notebook")
+	}
+
+	if err := registry.Remove("personal"); err != nil {
+		t.Fatalf("Remove failed: %v", err)
+	}
+	if _, ok := registry.Get("personal"); ok {
+		t.Error("Expected personal to be removed")
+	}
+	if registry.Default != "" {
+		t.Errorf("Expected no default after removing it, got %s", registry.Default)
+	}
+	if err := registry.Remove("personal"); err == nil {
+		t.Error("Expected an error when removing an unknown notebook")
+	}
+}
+
+func TestSearch(t *testing.T) {
+	personal := t.TempDir()
+	work := t.TempDir()
+
+	os.WriteFile(filepath.Join(personal, "2025-01-01 meeting prep.md"), []byte("# meeting prep"), 0644)
+	os.WriteFile(filepath.Join(work, "meeting notes.md"), []byte("# meeting notes"), 0644)
+	os.WriteFile(filepath.Join(work, "roadmap.md"), []byte("# roadmap\n\nDiscussed at the meeting"), 0644)
+
+	notebooks := []Notebook{{Name: "personal", Path: personal}, {Name: "work", Path: work}}
+
+	results, err := Search(notebooks, nil, "meeting", false)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+
+	if len(results) != 2 || results[0].Notebook != "personal" || results[1].Notebook != "work" {
+		t.Fatalf("Expected one result per notebook, got %+v", results)
+	}
+	if results[1].Note.Path != "meeting notes.md" {
+		t.Errorf("Expected meeting notes.md, got %s", results[1].Note.Path)
+	}
+
+	results, err = Search(notebooks, nil, "meeting", true)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(results) != 3 {
+		t.Errorf("Expected content matches too, got %+v", results)
+	}
+
+	os.MkdirAll(filepath.Join(work, "media"), 0755)
+	os.WriteFile(filepath.Join(work, "media", "meeting slides.md"), []byte("# meeting slides"), 0644)
+	open := func(notebook Notebook) (*note.Manager, error) {
+		noteManager := note.NewManager(notebook.Path)
+		noteManager.SetAssetDir("media")
+		return noteManager, nil
+	}
+	results, err = Search(notebooks, open, "meeting", false)
+	if err != nil || len(results) != 2 {
+		t.Errorf("Expected the notebook's asset dir to be skipped, got %+v, %v", results, err)
+	}
+
+	notebooks = append(notebooks, Notebook{Name: "gone", Path: filepath.Join(work, "gone")})
+	results, err = Search(notebooks, open, "meeting", false)
+	if err == nil || !strings.Contains(err.Error(), "notebook gone") {
+		t.Errorf("Expected an error naming the failing notebook, got %v", err)
+	}
+	if len(results) != 2 {
+		t.Errorf("Expected results from the other notebooks, got %+v", results)
+	}
+}
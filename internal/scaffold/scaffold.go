@@ -0,0 +1,162 @@
+package scaffold
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gitnote/internal/config"
+	"gitnote/internal/index"
+)
+
+const TemplatesDir = ".gitnote/templates"
+
+const starterConfig = `journal:
+  category: journal
+  template: .gitnote/templates/journal.md
+inbox:
+  path: inbox.md
+sync:
+  strategy: merge
+git:
+  backend: exec
+  timeout: 2m
+attachments:
+  dir: assets
+  layout: category
+  naming: hash
+lfs:
+  threshold: 5MB
+  track: false
+`
+
+const starterGitignore = `.DS_Store
+Thumbs.db
+*.swp
+*~
+`
+
+const starterJournalTemplate = `# {{.Weekday}} {{.Date}}
+
+[« {{.PreviousDate}}]({{.Previous}}) | [{{.NextDate}} »]({{.Next}})
+
+## Notes
+
+## Tasks
+
+`
+
+const starterReadme = `# Notes
+
+Everything between the markers below is regenerated by ` + "`gitnote index`" + `.
+
+` + index.StartMarker + `
+` + index.EndMarker + `
+`
+
+const PreCommitHook = `#!/bin/sh
+# Installed by gitnote init: stop commits of notes that still contain conflict markers.
+git diff --cached --name-only --diff-filter=ACM -z -- '*.md' |
+	xargs -0 grep -l -E '^(<<<<<<<|>>>>>>>) ' 2>/dev/null && {
+	echo "gitnote: resolve the conflict markers in the notes above before committing" >&2
+	exit 1
+}
+exit 0
+`
+
+type Scaffolder struct {
+	workingDir string
+}
+
+func NewScaffolder(workingDir string) *Scaffolder {
+	if workingDir == "" {
+		workingDir = "."
+	}
+	return &Scaffolder{workingDir: workingDir}
+}
+
+func (s *Scaffolder) Create(categories []string) ([]string, error) {
+	var created []string
+
+	files := []struct {
+		path    string
+		content string
+	}{
+		{config.FileName, starterConfig},
+		{".gitignore", starterGitignore},
+		{filepath.Join(TemplatesDir, "journal.md"), starterJournalTemplate},
+	}
+
+	for _, category := range categories {
+		category = filepath.Clean(strings.TrimSpace(category))
+		if category == "." || category == "" || strings.HasPrefix(category, ".") || filepath.IsAbs(category) {
+			return created, fmt.Errorf("invalid category %q", category)
+		}
+		files = append(files, struct {
+			path    string
+			content string
+		}{filepath.Join(category, ".gitkeep"), ""})
+	}
+
+	for _, file := range files {
+		written, err := s.writeIfMissing(file.path, file.content)
+		if err != nil {
+			return created, err
+		}
+		if written {
+			created = append(created, file.path)
+		}
+	}
+
+	updated, err := s.prepareReadme()
+	if err != nil {
+		return created, err
+	}
+	if updated {
+		created = append(created, index.ReadmeFile)
+	}
+
+	return created, nil
+}
+
+func (s *Scaffolder) writeIfMissing(path, content string) (bool, error) {
+	fullPath := filepath.Join(s.workingDir, path)
+
+	if _, err := os.Stat(fullPath); err == nil {
+		return false, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+		return false, fmt.Errorf("failed to create directory for %s: %w", path, err)
+	}
+
+	if err := os.WriteFile(fullPath, []byte(content), 0644); err != nil {
+		return false, fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	return true, nil
+}
+
+func (s *Scaffolder) prepareReadme() (bool, error) {
+	readmePath := filepath.Join(s.workingDir, index.ReadmeFile)
+
+	existing, err := os.ReadFile(readmePath)
+	if os.IsNotExist(err) {
+		return s.writeIfMissing(index.ReadmeFile, starterReadme)
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read %s: %w", index.ReadmeFile, err)
+	}
+
+	if strings.Contains(string(existing), index.StartMarker) {
+		return false, nil
+	}
+
+	content := strings.TrimRight(string(existing), "\n") + "\n\n" + index.StartMarker + "\n" + index.EndMarker + "\n"
+	if err := os.WriteFile(readmePath, []byte(content), 0644); err != nil {
+		return false, fmt.Errorf("failed to update %s: %w", index.ReadmeFile, err)
+	}
+
+	return true, nil
+}
@@ -0,0 +1,88 @@
+package scaffold
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gitnote/internal/config"
+	"gitnote/internal/index"
+)
+
+func TestCreate(t *testing.T) {
+	tempDir := t.TempDir()
+
+	created, err := NewScaffolder(tempDir).Create([]string{"work", "personal"})
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	expected := []string{
+		config.FileName,
+		".gitignore",
+		filepath.Join(TemplatesDir, "journal.md"),
+		filepath.Join("work", ".gitkeep"),
+		filepath.Join("personal", ".gitkeep"),
+		index.ReadmeFile,
+	}
+	if strings.Join(created, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected %v, got %v", expected, created)
+	}
+
+	cfg, err := config.Load(tempDir)
+	if err != nil {
+		t.Fatalf("Starter config does not load: %v", err)
+	}
+	if cfg.Journal.Template != filepath.ToSlash(filepath.Join(TemplatesDir, "journal.md")) {
+		t.Errorf("Expected the journal template to be configured, got %q", cfg.Journal.Template)
+	}
+
+	readme, _ := os.ReadFile(filepath.Join(tempDir, index.ReadmeFile))
+	if !strings.Contains(string(readme), index.StartMarker) || !strings.Contains(string(readme), index.EndMarker) {
+		t.Errorf("Expected index markers in readme, got %q", readme)
+	}
+
+	created, err = NewScaffolder(tempDir).Create([]string{"work"})
+	if err != nil {
+		t.Fatalf("Second Create failed: %v", err)
+	}
+	if len(created) != 0 {
+		t.Errorf("Expected nothing to be created twice, got %v", created)
+	}
+}
+
+func TestCreateKeepsExistingFiles(t *testing.T) {
+	tempDir := t.TempDir()
+	os.WriteFile(filepath.Join(tempDir, ".gitignore"), []byte("build/\n"), 0644)
+	os.WriteFile(filepath.Join(tempDir, index.ReadmeFile), []byte("# My notes\n\nHand-written intro.\n"), 0644)
+
+	created, err := NewScaffolder(tempDir).Create(nil)
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	for _, path := range created {
+		if path == ".gitignore" {
+			t.Error("Expected the existing .gitignore to be kept")
+		}
+	}
+
+	gitignore, _ := os.ReadFile(filepath.Join(tempDir, ".gitignore"))
+	if string(gitignore) != "build/\n" {
+		t.Errorf("Expected .gitignore to be untouched, got %q", gitignore)
+	}
+
+	readme, _ := os.ReadFile(filepath.Join(tempDir, index.ReadmeFile))
+	if !strings.HasPrefix(string(readme), "# My notes\n\nHand-written intro.\n\n"+index.StartMarker) {
+		t.Errorf("Expected markers appended after the existing readme, got %q", readme)
+	}
+}
+
+func TestCreateRejectsInvalidCategories(t *testing.T) {
+	for _, category := range []string{"", ".", ".hidden", "/abs"} {
+		if _, err := NewScaffolder(t.TempDir()).Create([]string{category}); err == nil {
+			t.Errorf("Expected an error for category %q", category)
+		}
+	}
+}
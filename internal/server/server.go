@@ -0,0 +1,365 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"html/template"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+
+	"gitnote/internal/git"
+	"gitnote/internal/note"
+)
+
+type Server struct {
+	workingDir     string
+	noteManager    *note.Manager
+	markdown       goldmark.Markdown
+	liveReload     bool
+	reloadInterval time.Duration
+}
+
+type link struct {
+	Title string
+	URL   string
+}
+
+type page struct {
+	Title      string
+	Query      string
+	Body       template.HTML
+	Categories []link
+	Notes      []link
+	Empty      string
+	LiveReload bool
+}
+
+func NewServer(workingDir string) *Server {
+	if workingDir == "" {
+		workingDir = "."
+	}
+
+	noteManager := note.NewManager(workingDir)
+	noteManager.SetGitHistory(context.Background(), git.NewManager(workingDir))
+
+	return &Server{
+		workingDir:     workingDir,
+		noteManager:    noteManager,
+		markdown:       goldmark.New(goldmark.WithExtensions(extension.GFM)),
+		liveReload:     true,
+		reloadInterval: time.Second,
+	}
+}
+
+func (s *Server) SetGitManager(ctx context.Context, gitManager *git.Manager) {
+	s.noteManager.SetGitHistory(ctx, gitManager)
+}
+
+func (s *Server) SetAssetDir(dir string) {
+	s.noteManager.SetAssetDir(dir)
+}
+
+func (s *Server) SetLiveReload(enabled bool) {
+	s.liveReload = enabled
+}
+
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/notes", s.handleAPINotes)
+	mux.HandleFunc("/api/search", s.handleAPISearch)
+	mux.HandleFunc("/search", s.handleSearch)
+	mux.HandleFunc("/events", s.handleEvents)
+	mux.HandleFunc("/", s.handlePath)
+	return mux
+}
+
+func (s *Server) handleAPINotes(w http.ResponseWriter, r *http.Request) {
+	notes, err := s.noteManager.FindNotes()
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to find notes: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	category := filepath.FromSlash(strings.Trim(r.URL.Query().Get("category"), "/"))
+	if category != "" {
+		notes = note.FilterByCategory(notes, category)
+	}
+
+	writeJSON(w, notes)
+}
+
+func (s *Server) handleAPISearch(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query().Get("q")
+	if query == "" {
+		http.Error(w, "missing query parameter q", http.StatusBadRequest)
+		return
+	}
+
+	results, err := s.noteManager.SearchNotes(query, r.URL.Query().Get("full") == "true")
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to search notes: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, results)
+}
+
+func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query().Get("q")
+	p := page{Title: "Search", Query: query}
+
+	if query != "" {
+		p.Empty = "No notes found matching the query"
+		results, err := s.noteManager.SearchNotes(query, r.URL.Query().Get("full") == "true")
+		if err != nil {
+			http.Error(w, fmt.Sprintf("failed to search notes: %v", err), http.StatusInternalServerError)
+			return
+		}
+		p.Title = fmt.Sprintf("Search: %s", query)
+		p.Notes = noteLinks(results, true)
+	}
+
+	s.render(w, p)
+}
+
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	last, err := s.Snapshot()
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to watch notes: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	ticker := time.NewTicker(s.reloadInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+			current, err := s.Snapshot()
+			if err != nil || current == last {
+				continue
+			}
+			last = current
+			fmt.Fprint(w, "event: reload\ndata: {}\n\n")
+			flusher.Flush()
+		}
+	}
+}
+
+func (s *Server) handlePath(w http.ResponseWriter, r *http.Request) {
+	relativePath := filepath.FromSlash(strings.TrimPrefix(path.Clean("/"+r.URL.Path), "/"))
+
+	for _, part := range strings.Split(filepath.ToSlash(relativePath), "/") {
+		if strings.HasPrefix(part, ".") {
+			http.NotFound(w, r)
+			return
+		}
+	}
+
+	info, err := os.Stat(filepath.Join(s.workingDir, relativePath))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	if info.IsDir() {
+		s.serveCategory(w, relativePath)
+		return
+	}
+
+	if strings.HasSuffix(strings.ToLower(relativePath), ".md") {
+		s.serveNote(w, relativePath)
+		return
+	}
+
+	if note.IsEncryptedPath(relativePath) {
+		title, _, _ := note.ParseFilename(filepath.Base(relativePath))
+		s.render(w, page{Title: title, Empty: "This note is encrypted. Open it with gitnote open."})
+		return
+	}
+
+	http.ServeFile(w, r, filepath.Join(s.workingDir, relativePath))
+}
+
+func (s *Server) serveCategory(w http.ResponseWriter, category string) {
+	var subcategories []string
+	var err error
+
+	if category == "" {
+		subcategories, err = s.noteManager.GetCategories()
+	} else {
+		subcategories, err = s.noteManager.GetSubcategories(category)
+	}
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to read categories: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	notes, err := s.noteManager.FindNotes()
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to find notes: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	var directNotes []note.Note
+	for _, n := range notes {
+		if n.Category == category {
+			directNotes = append(directNotes, n)
+		}
+	}
+
+	p := page{Title: "Notes Index", Empty: "No notes in this category"}
+	if category != "" {
+		p.Title = filepath.ToSlash(category)
+	}
+
+	for _, subcategory := range subcategories {
+		p.Categories = append(p.Categories, link{
+			Title: subcategory,
+			URL:   pathURL(filepath.Join(category, subcategory)) + "/",
+		})
+	}
+	p.Notes = noteLinks(directNotes, false)
+
+	s.render(w, p)
+}
+
+func (s *Server) serveNote(w http.ResponseWriter, notePath string) {
+	content, err := os.ReadFile(filepath.Join(s.workingDir, notePath))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to read note: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	var body bytes.Buffer
+	if err := s.markdown.Convert(content, &body); err != nil {
+		http.Error(w, fmt.Sprintf("failed to render note: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	s.render(w, page{
+		Title: strings.TrimSuffix(filepath.Base(notePath), filepath.Ext(notePath)),
+		Body:  template.HTML(body.String()),
+	})
+}
+
+func (s *Server) render(w http.ResponseWriter, p page) {
+	p.LiveReload = s.liveReload
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := pageTemplate.Execute(w, p); err != nil {
+		http.Error(w, fmt.Sprintf("failed to render page: %v", err), http.StatusInternalServerError)
+	}
+}
+
+func (s *Server) Snapshot() (uint64, error) {
+	hash := fnv.New64a()
+
+	err := filepath.Walk(s.workingDir, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() && p != s.workingDir && strings.HasPrefix(info.Name(), ".") {
+			return filepath.SkipDir
+		}
+
+		if !info.IsDir() && strings.HasSuffix(strings.ToLower(p), ".md") {
+			fmt.Fprintf(hash, "%s|%d|%d\n", p, info.Size(), info.ModTime().UnixNano())
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to walk directory: %w", err)
+	}
+
+	return hash.Sum64(), nil
+}
+
+func noteLinks(notes []note.Note, withPath bool) []link {
+	links := make([]link, 0, len(notes))
+
+	for _, n := range notes {
+		title := n.Title
+		if withPath {
+			title = filepath.ToSlash(n.Path)
+		}
+		links = append(links, link{Title: title, URL: pathURL(n.Path)})
+	}
+
+	return links
+}
+
+func pathURL(relativePath string) string {
+	u := url.URL{Path: "/" + filepath.ToSlash(relativePath)}
+	return u.EscapedPath()
+}
+
+func writeJSON(w http.ResponseWriter, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(value); err != nil {
+		http.Error(w, fmt.Sprintf("failed to encode response: %v", err), http.StatusInternalServerError)
+	}
+}
+
+var pageTemplate = template.Must(template.New("page").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}} - gitnote</title>
+<style>
+body { font-family: sans-serif; max-width: 50em; margin: 2em auto; padding: 0 1em; line-height: 1.5; }
+nav { display: flex; justify-content: space-between; border-bottom: 1px solid #ddd; padding-bottom: 0.5em; }
+pre { background: #f6f8fa; padding: 1em; overflow-x: auto; }
+</style>
+</head>
+<body>
+<nav>
+<a href="/">Notes Index</a>
+<form action="/search">
+<input type="search" name="q" value="{{.Query}}" placeholder="Search notes">
+<label><input type="checkbox" name="full" value="true"> full text</label>
+</form>
+</nav>
+<main>
+{{if .Body}}{{.Body}}{{else}}<h1>{{.Title}}</h1>
+{{if .Categories}}<h2>Categories</h2>
+<ul>{{range .Categories}}
+<li><a href="{{.URL}}">{{.Title}}</a></li>{{end}}
+</ul>{{end}}
+{{if .Notes}}<h2>Notes</h2>
+<ul>{{range .Notes}}
+<li><a href="{{.URL}}">{{.Title}}</a></li>{{end}}
+</ul>{{else if not .Categories}}<p>{{.Empty}}</p>{{end}}{{end}}
+</main>
+{{if .LiveReload}}<script>
+new EventSource("/events").addEventListener("reload", function () { location.reload(); });
+</script>{{end}}
+</body>
+</html>
+`))
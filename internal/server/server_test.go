@@ -0,0 +1,247 @@
+package server
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gitnote/internal/note"
+)
+
+func setupNotes(t *testing.T) string {
+	tempDir := t.TempDir()
+
+	os.MkdirAll(filepath.Join(tempDir, "work", "meetings"), 0755)
+	os.Mkdir(filepath.Join(tempDir, ".git"), 0755)
+
+	os.WriteFile(filepath.Join(tempDir, "2025-01-01 root note.md"), []byte("# root note\n"), 0644)
+	os.WriteFile(filepath.Join(tempDir, "work", "2025-01-02 work note.md"), []byte("# work note\n\nSome **bold** text"), 0644)
+	os.WriteFile(filepath.Join(tempDir, "work", "meetings", "2025-01-03 standup.md"), []byte("# standup\n\nDiscussed the roadmap"), 0644)
+	os.WriteFile(filepath.Join(tempDir, ".git", "secret.md"), []byte("# secret"), 0644)
+
+	return tempDir
+}
+
+func get(t *testing.T, handler http.Handler, target string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(http.MethodGet, target, nil)
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func TestNewServer(t *testing.T) {
+	s := NewServer("")
+	if s.workingDir != "." {
+		t.Errorf("Expected working dir to be '.', got %s", s.workingDir)
+	}
+
+	if !s.liveReload {
+		t.Error("Expected live reload to be enabled by default")
+	}
+}
+
+func TestServeRootIndex(t *testing.T) {
+	handler := NewServer(setupNotes(t)).Handler()
+
+	response := get(t, handler, "/")
+	if response.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", response.Code)
+	}
+
+	body := response.Body.String()
+	if !strings.Contains(body, `href="/work/"`) {
+		t.Error("Expected index to link to work category")
+	}
+
+	if !strings.Contains(body, `href="/2025-01-01%20root%20note.md"`) {
+		t.Errorf("Expected index to link to root note, got %s", body)
+	}
+
+	if !strings.Contains(body, "new EventSource") {
+		t.Error("Expected live reload script to be included")
+	}
+}
+
+func TestServeCategoryIndex(t *testing.T) {
+	handler := NewServer(setupNotes(t)).Handler()
+
+	response := get(t, handler, "/work/")
+	if response.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", response.Code)
+	}
+
+	body := response.Body.String()
+	if !strings.Contains(body, `href="/work/meetings/"`) {
+		t.Error("Expected category index to link to meetings subcategory")
+	}
+
+	if !strings.Contains(body, ">work note<") {
+		t.Error("Expected category index to list work note")
+	}
+
+	if strings.Contains(body, "root note") {
+		t.Error("Expected category index to exclude notes from other categories")
+	}
+}
+
+func TestServeNote(t *testing.T) {
+	handler := NewServer(setupNotes(t)).Handler()
+
+	response := get(t, handler, "/work/2025-01-02%20work%20note.md")
+	if response.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", response.Code)
+	}
+
+	if !strings.Contains(response.Body.String(), "<strong>bold</strong>") {
+		t.Error("Expected note markdown to be rendered as HTML")
+	}
+}
+
+func TestServeEncryptedNote(t *testing.T) {
+	tempDir := setupNotes(t)
+	os.WriteFile(filepath.Join(tempDir, "work", "2025-01-03 secrets.md.age"), []byte("-----BEGIN AGE ENCRYPTED FILE-----\nciphertext\n"), 0644)
+	handler := NewServer(tempDir).Handler()
+
+	response := get(t, handler, "/work/2025-01-03%20secrets.md.age")
+	if response.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", response.Code)
+	}
+
+	body := response.Body.String()
+	if strings.Contains(body, "ciphertext") || !strings.Contains(body, "This note is encrypted") || !strings.Contains(body, "<h1>secrets</h1>") {
+		t.Errorf("Expected a notice instead of the ciphertext, got %s", body)
+	}
+}
+
+func TestServeHiddenAndMissingPaths(t *testing.T) {
+	handler := NewServer(setupNotes(t)).Handler()
+
+	for _, target := range []string{"/.git/secret.md", "/missing.md", "/work/.hidden/"} {
+		response := get(t, handler, target)
+		if response.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404 for %s, got %d", target, response.Code)
+		}
+	}
+}
+
+func TestAPINotes(t *testing.T) {
+	handler := NewServer(setupNotes(t)).Handler()
+
+	response := get(t, handler, "/api/notes")
+	if response.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", response.Code)
+	}
+
+	var notes []note.Note
+	if err := json.Unmarshal(response.Body.Bytes(), &notes); err != nil {
+		t.Fatalf("Failed to decode notes: %v", err)
+	}
+
+	if len(notes) != 3 {
+		t.Fatalf("Expected 3 notes, got %d", len(notes))
+	}
+
+	response = get(t, handler, "/api/notes?category=work")
+
+	notes = nil
+	if err := json.Unmarshal(response.Body.Bytes(), &notes); err != nil {
+		t.Fatalf("Failed to decode notes: %v", err)
+	}
+
+	if len(notes) != 2 {
+		t.Fatalf("Expected 2 notes in work category, got %d", len(notes))
+	}
+}
+
+func TestAPISearch(t *testing.T) {
+	handler := NewServer(setupNotes(t)).Handler()
+
+	response := get(t, handler, "/api/search")
+	if response.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 without query, got %d", response.Code)
+	}
+
+	var notes []note.Note
+
+	response = get(t, handler, "/api/search?q=roadmap")
+	json.Unmarshal(response.Body.Bytes(), &notes)
+	if len(notes) != 0 {
+		t.Errorf("Expected no title matches, got %d", len(notes))
+	}
+
+	response = get(t, handler, "/api/search?q=roadmap&full=true")
+	json.Unmarshal(response.Body.Bytes(), &notes)
+	if len(notes) != 1 || notes[0].Title != "standup" {
+		t.Errorf("Expected standup note for content search, got %v", notes)
+	}
+}
+
+func TestSearchPage(t *testing.T) {
+	handler := NewServer(setupNotes(t)).Handler()
+
+	body := get(t, handler, "/search?q=standup").Body.String()
+	if !strings.Contains(body, "work/meetings/2025-01-03 standup.md") {
+		t.Error("Expected search page to list matching note")
+	}
+
+	body = get(t, handler, "/search?q=nothing").Body.String()
+	if !strings.Contains(body, "No notes found matching the query") {
+		t.Error("Expected search page to report no matches")
+	}
+}
+
+func TestSnapshotChangesWithNotes(t *testing.T) {
+	tempDir := setupNotes(t)
+	s := NewServer(tempDir)
+
+	before, err := s.Snapshot()
+	if err != nil {
+		t.Fatalf("Snapshot failed: %v", err)
+	}
+
+	os.WriteFile(filepath.Join(tempDir, ".git", "ignored.md"), []byte("# ignored"), 0644)
+
+	after, _ := s.Snapshot()
+	if before != after {
+		t.Error("Expected hidden directories to be ignored by snapshot")
+	}
+
+	os.WriteFile(filepath.Join(tempDir, "2025-01-04 new note.md"), []byte("# new note"), 0644)
+
+	after, _ = s.Snapshot()
+	if before == after {
+		t.Error("Expected snapshot to change after adding a note")
+	}
+}
+
+func TestEventsStreamReload(t *testing.T) {
+	tempDir := setupNotes(t)
+	s := NewServer(tempDir)
+	s.reloadInterval = 10 * time.Millisecond
+
+	testServer := httptest.NewServer(s.Handler())
+	defer testServer.Close()
+
+	response, err := http.Get(testServer.URL + "/events")
+	if err != nil {
+		t.Fatalf("Failed to connect to events: %v", err)
+	}
+	defer response.Body.Close()
+
+	os.WriteFile(filepath.Join(tempDir, "2025-01-04 new note.md"), []byte("# new note"), 0644)
+
+	scanner := bufio.NewScanner(response.Body)
+	for scanner.Scan() {
+		if scanner.Text() == "event: reload" {
+			return
+		}
+	}
+
+	t.Error("Expected reload event after note change")
+}
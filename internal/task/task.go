@@ -0,0 +1,259 @@
+package task
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"gitnote/internal/note"
+)
+
+const DueFormat = "2006-01-02"
+
+var (
+	taskPattern     = regexp.MustCompile(`^\s*[-*+] \[([ xX])\] (.*)$`)
+	duePattern      = regexp.MustCompile(`@due\((\d{4}-\d{2}-\d{2})\)`)
+	assigneePattern = regexp.MustCompile(`(?:^|\s)@([\w.-]+)(\()?`)
+	tagPattern      = regexp.MustCompile(`(?:^|\s)#([\w/-]*[A-Za-z_/-][\w/-]*)`)
+)
+
+type Task struct {
+	ID        string
+	Path      string
+	Category  string
+	Line      int
+	Text      string
+	Done      bool
+	Due       time.Time
+	Assignees []string
+	Tags      []string
+}
+
+type Filter struct {
+	Category    string
+	Tag         string
+	Assignee    string
+	DueBy       time.Time
+	IncludeDone bool
+}
+
+type Manager struct {
+	workingDir  string
+	noteManager *note.Manager
+}
+
+func NewManager(workingDir string) *Manager {
+	if workingDir == "" {
+		workingDir = "."
+	}
+	return &Manager{
+		workingDir:  workingDir,
+		noteManager: note.NewManager(workingDir),
+	}
+}
+
+func (m *Manager) SetAssetDir(dir string) {
+	m.noteManager.SetAssetDir(dir)
+}
+
+func Parse(notePath, content string) []Task {
+	var tasks []Task
+	occurrences := make(map[string]int)
+	inCodeBlock := false
+
+	for i, line := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inCodeBlock = !inCodeBlock
+			continue
+		}
+
+		if inCodeBlock {
+			continue
+		}
+
+		match := taskPattern.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+
+		text := strings.TrimSpace(match[2])
+
+		task := Task{
+			ID:   taskID(notePath, text, occurrences[text]),
+			Path: notePath,
+			Line: i + 1,
+			Text: text,
+			Done: match[1] != " ",
+		}
+		occurrences[text]++
+
+		if dir := filepath.Dir(notePath); dir != "." {
+			task.Category = dir
+		}
+
+		if due := duePattern.FindStringSubmatch(text); due != nil {
+			if date, err := time.ParseInLocation(DueFormat, due[1], time.Local); err == nil {
+				task.Due = date
+			}
+		}
+
+		for _, assignee := range assigneePattern.FindAllStringSubmatch(text, -1) {
+			if assignee[2] == "" {
+				task.Assignees = append(task.Assignees, strings.TrimRight(assignee[1], "."))
+			}
+		}
+
+		for _, tag := range tagPattern.FindAllStringSubmatch(text, -1) {
+			task.Tags = append(task.Tags, tag[1])
+		}
+
+		tasks = append(tasks, task)
+	}
+
+	return tasks
+}
+
+func taskID(notePath, text string, occurrence int) string {
+	hash := sha1.Sum([]byte(fmt.Sprintf("%s\n%s\n%d", filepath.ToSlash(notePath), text, occurrence)))
+	return hex.EncodeToString(hash[:])[:7]
+}
+
+func (m *Manager) FindTasks() ([]Task, error) {
+	notes, err := m.noteManager.FindNotes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find notes: %w", err)
+	}
+
+	var tasks []Task
+
+	for _, n := range notes {
+		if n.Encrypted {
+			continue
+		}
+
+		content, err := os.ReadFile(filepath.Join(m.workingDir, n.Path))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read note %s: %w", n.Path, err)
+		}
+
+		frontMatter, _, _ := note.ParseFrontMatter(string(content))
+
+		for _, task := range Parse(n.Path, string(content)) {
+			task.Tags = append(task.Tags, frontMatter.Tags...)
+			tasks = append(tasks, task)
+		}
+	}
+
+	return tasks, nil
+}
+
+func FilterTasks(tasks []Task, filter Filter) []Task {
+	var filtered []Task
+
+	for _, task := range tasks {
+		if task.Done && !filter.IncludeDone {
+			continue
+		}
+
+		if filter.Category != "" && !note.InCategory(task.Category, filter.Category) {
+			continue
+		}
+
+		if filter.Tag != "" && !containsFold(task.Tags, strings.TrimPrefix(filter.Tag, "#")) {
+			continue
+		}
+
+		if filter.Assignee != "" && !containsFold(task.Assignees, strings.TrimPrefix(filter.Assignee, "@")) {
+			continue
+		}
+
+		if !filter.DueBy.IsZero() && (task.Due.IsZero() || task.Due.After(filter.DueBy)) {
+			continue
+		}
+
+		filtered = append(filtered, task)
+	}
+
+	sort.SliceStable(filtered, func(i, j int) bool {
+		a, b := filtered[i], filtered[j]
+		if a.Due.IsZero() != b.Due.IsZero() {
+			return !a.Due.IsZero()
+		}
+		if !a.Due.Equal(b.Due) {
+			return a.Due.Before(b.Due)
+		}
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		return a.Line < b.Line
+	})
+
+	return filtered
+}
+
+func (m *Manager) Complete(id string) (Task, error) {
+	tasks, err := m.FindTasks()
+	if err != nil {
+		return Task{}, err
+	}
+
+	var matches []Task
+	for _, task := range tasks {
+		if strings.HasPrefix(task.ID, id) {
+			matches = append(matches, task)
+		}
+	}
+
+	if len(matches) == 0 {
+		return Task{}, fmt.Errorf("no task found with id %s", id)
+	}
+
+	if len(matches) > 1 {
+		return Task{}, fmt.Errorf("task id %s is ambiguous", id)
+	}
+
+	task := matches[0]
+	if task.Done {
+		return task, fmt.Errorf("task %s is already done", task.ID)
+	}
+
+	fullPath := filepath.Join(m.workingDir, task.Path)
+
+	content, err := os.ReadFile(fullPath)
+	if err != nil {
+		return Task{}, fmt.Errorf("failed to read note: %w", err)
+	}
+
+	lines := strings.Split(string(content), "\n")
+	line := lines[task.Line-1]
+
+	index := strings.Index(line, "[ ]")
+	if index < 0 {
+		return Task{}, fmt.Errorf("task %s has changed on disk", task.ID)
+	}
+
+	lines[task.Line-1] = line[:index] + "[x]" + line[index+3:]
+
+	if err := os.WriteFile(fullPath, []byte(strings.Join(lines, "\n")), 0644); err != nil {
+		return Task{}, fmt.Errorf("failed to update note: %w", err)
+	}
+
+	task.Done = true
+	return task, nil
+}
+
+func containsFold(values []string, value string) bool {
+	for _, existing := range values {
+		if strings.EqualFold(existing, value) {
+			return true
+		}
+	}
+
+	return false
+}
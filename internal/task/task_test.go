@@ -0,0 +1,133 @@
+package task
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const sampleNote = "---\ntags: [ops]\n---\n# release\n\n- [ ] Write changelog @due(2026-10-20) @alice #docs\n- [x] Tag release @bob\n* [ ] Email team@example.com about it\n\n```\n- [ ] not a task\n```\n- [ ] Write changelog\n"
+
+func TestNewManager(t *testing.T) {
+	manager := NewManager("")
+	if manager.workingDir != "." {
+		t.Errorf("Expected working dir to be '.', got %s", manager.workingDir)
+	}
+}
+
+func TestParse(t *testing.T) {
+	tasks := Parse(filepath.Join("work", "release.md"), sampleNote)
+
+	if len(tasks) != 4 {
+		t.Fatalf("Expected 4 tasks, got %d", len(tasks))
+	}
+
+	changelog := tasks[0]
+	if changelog.Line != 6 || changelog.Done || changelog.Category != "work" {
+		t.Errorf("Unexpected first task: %+v", changelog)
+	}
+
+	if changelog.Due.Format(DueFormat) != "2026-10-20" {
+		t.Errorf("Expected due date 2026-10-20, got %s", changelog.Due.Format(DueFormat))
+	}
+
+	if !reflect.DeepEqual(changelog.Assignees, []string{"alice"}) {
+		t.Errorf("Expected assignees [alice], got %v", changelog.Assignees)
+	}
+
+	if !reflect.DeepEqual(changelog.Tags, []string{"docs"}) {
+		t.Errorf("Expected tags [docs], got %v", changelog.Tags)
+	}
+
+	if !tasks[1].Done {
+		t.Error("Expected second task to be done")
+	}
+
+	if len(tasks[2].Assignees) != 0 {
+		t.Errorf("Expected email address not to be an assignee, got %v", tasks[2].Assignees)
+	}
+
+	if tasks[0].ID == tasks[3].ID {
+		t.Error("Expected tasks with the same text to have distinct ids")
+	}
+
+	if again := Parse(filepath.Join("work", "release.md"), sampleNote); again[0].ID != tasks[0].ID {
+		t.Error("Expected task ids to be stable")
+	}
+}
+
+func TestFindTasksAndFilter(t *testing.T) {
+	tempDir := t.TempDir()
+	os.MkdirAll(filepath.Join(tempDir, "work"), 0755)
+	os.WriteFile(filepath.Join(tempDir, "work", "2026-10-01 release.md"), []byte(sampleNote), 0644)
+	os.WriteFile(filepath.Join(tempDir, "2026-10-02 home.md"), []byte("# home\n\n- [ ] Fix fence @due(2026-10-19)\n- [ ] Paint shed\n"), 0644)
+
+	tasks, err := NewManager(tempDir).FindTasks()
+	if err != nil {
+		t.Fatalf("FindTasks failed: %v", err)
+	}
+
+	if len(tasks) != 6 {
+		t.Fatalf("Expected 6 tasks, got %d", len(tasks))
+	}
+
+	open := FilterTasks(tasks, Filter{})
+	if len(open) != 5 {
+		t.Fatalf("Expected 5 open tasks, got %d", len(open))
+	}
+
+	if open[0].Text != "Fix fence @due(2026-10-19)" || open[1].Due.IsZero() {
+		t.Errorf("Expected tasks with due dates to be listed first, got %v", open)
+	}
+
+	if work := FilterTasks(tasks, Filter{Category: "work"}); len(work) != 3 {
+		t.Errorf("Expected 3 open work tasks, got %d", len(work))
+	}
+
+	if tagged := FilterTasks(tasks, Filter{Tag: "#OPS"}); len(tagged) != 3 {
+		t.Errorf("Expected front matter tags to apply to tasks, got %d", len(tagged))
+	}
+
+	if assigned := FilterTasks(tasks, Filter{Assignee: "@bob", IncludeDone: true}); len(assigned) != 1 {
+		t.Errorf("Expected 1 task assigned to bob, got %d", len(assigned))
+	}
+
+	dueBy := time.Date(2026, 10, 19, 0, 0, 0, 0, time.Local)
+	if due := FilterTasks(tasks, Filter{DueBy: dueBy}); len(due) != 1 || !strings.HasPrefix(due[0].Text, "Fix fence") {
+		t.Errorf("Expected only the fence task to be due, got %v", due)
+	}
+}
+
+func TestComplete(t *testing.T) {
+	tempDir := t.TempDir()
+	notePath := filepath.Join(tempDir, "2026-10-02 home.md")
+	os.WriteFile(notePath, []byte("# home\n\n- [ ] Fix fence\n- [ ] Paint shed\n"), 0644)
+
+	manager := NewManager(tempDir)
+	tasks, _ := manager.FindTasks()
+
+	completed, err := manager.Complete(tasks[1].ID[:5])
+	if err != nil {
+		t.Fatalf("Complete failed: %v", err)
+	}
+
+	if !completed.Done || completed.Text != "Paint shed" {
+		t.Errorf("Unexpected completed task: %+v", completed)
+	}
+
+	content, _ := os.ReadFile(notePath)
+	if string(content) != "# home\n\n- [ ] Fix fence\n- [x] Paint shed\n" {
+		t.Errorf("Unexpected note content: %q", string(content))
+	}
+
+	if _, err := manager.Complete(tasks[1].ID); err == nil {
+		t.Error("Expected error when completing a done task")
+	}
+
+	if _, err := manager.Complete("zzzzzzz"); err == nil {
+		t.Error("Expected error for unknown task id")
+	}
+}